@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/filter"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// command is a subcommand of the CLI, e.g. "promptwatch files"
+type command struct {
+	summary string
+	run     func(args []string) error
+}
+
+// commands holds the subcommands by name. It is filled in init because the
+// commands print their usage from it.
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"commits": {"List the git commits made during sessions", runCommits},
+		"diff":    {"Align the prompts of two sessions and diff what followed", runDiff},
+		"files":   {"Report the files sessions read and changed", runFiles},
+		"history": {"Show and diff file states at session checkpoints", runHistory},
+		"ingest":  {"Add new session lines to the SQLite session database", runIngest},
+		"record":  {"Sample running processes into the resource history file", runRecord},
+		"sql":     {"Query the session database with SQL", runSQL},
+		"tools":   {"Report tool usage: calls, errors, latency and sizes", runTools},
+	}
+}
+
+// newFlagSet creates the flag set of a subcommand, including -config
+func newFlagSet(name, synopsis string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to config file (default $XDG_CONFIG_HOME/promptwatch/config.toml)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: promptwatch %s %s\n\n%s.\n\nFlags:\n", name, synopsis, commands[name].summary)
+		fs.PrintDefaults()
+	}
+	return fs, configPath
+}
+
+// setup loads the config for a subcommand and installs its roots and pricing
+func setup(configPath string) (*config.Config, error) {
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	applyConfig(cfg)
+	return cfg, nil
+}
+
+// printUsage lists the flags of the TUI and the subcommands
+func printUsage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: promptwatch [flags]\n       promptwatch <command> [flags] [args]\n\nFlags:\n")
+	flag.PrintDefaults()
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(out, "\nCommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-10s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintf(out, "\nRun 'promptwatch <command> -h' for the flags of a command.\n")
+}
+
+// sessionFilesFor resolves a command argument to session files: a .jsonl
+// file is one session, anything else a project working directory
+func sessionFilesFor(arg string) ([]string, error) {
+	if arg == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get working directory: %w", err)
+		}
+		arg = cwd
+	}
+
+	info, err := os.Stat(arg)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{arg}, nil
+	}
+
+	dir, err := filepath.Abs(arg)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := monitor.FindSessionsForDirectory(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("no sessions found for directory: %s", dir)
+	}
+
+	files := make([]string, len(sessions))
+	for i, s := range sessions {
+		files[i] = s.FilePath
+	}
+	return files, nil
+}
+
+// filterSessionFiles keeps the session files matching a session filter
+// expression; an empty expression keeps them all
+func filterSessionFiles(files []string, src string) ([]string, error) {
+	expr, err := filter.ParseSessions(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	if expr.IsEmpty() {
+		return files, nil
+	}
+
+	var matched []string
+	for _, path := range files {
+		stats, err := monitor.ParseSessionFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if expr.Match(filter.SessionFromStats(stats)) {
+			matched = append(matched, path)
+		}
+	}
+	return matched, nil
+}
+
+// parseSessionFiles parses session files, keeping the sessions matching a
+// session filter
+func parseSessionFiles(files []string, src string) ([]*monitor.SessionStats, error) {
+	expr, err := filter.ParseSessions(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	var sessions []*monitor.SessionStats
+	for _, path := range files {
+		stats, err := monitor.ParseSessionFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+		}
+		if expr.Match(filter.SessionFromStats(stats)) {
+			sessions = append(sessions, stats)
+		}
+	}
+	return sessions, nil
+}
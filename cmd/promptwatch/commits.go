@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// runCommits lists the git commits made during each session of a project or
+// during a single session, marking the files the session edited
+func runCommits(args []string) error {
+	fs, configPath := newFlagSet("commits", "[flags] [DIR | SESSION.jsonl]")
+	grace := fs.Duration("grace", monitor.CommitGrace, "Also count commits made this long after the last message")
+	all := fs.Bool("all", false, "Also list sessions without commits")
+	sessionFilter := fs.String("filter", "", "Only sessions matching this filter expression, e.g. 'tool:Bash cost>1'")
+	fs.Parse(args)
+
+	if _, err := setup(*configPath); err != nil {
+		return err
+	}
+	monitor.CommitGrace = *grace
+
+	sessionFiles, err := sessionFilesFor(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if sessionFiles, err = filterSessionFiles(sessionFiles, *sessionFilter); err != nil {
+		return err
+	}
+
+	found := 0
+	for _, path := range sessionFiles {
+		stats, err := monitor.ParseSessionFile(path)
+		if err != nil {
+			return err
+		}
+		commits, err := monitor.SessionCommits(stats)
+		if err != nil {
+			if len(sessionFiles) == 1 {
+				return err
+			}
+			continue // Sessions outside a repository have no commits
+		}
+		if len(commits) == 0 && !*all {
+			continue
+		}
+		found += len(commits)
+
+		fmt.Printf("%s  %s – %s  %d commits\n",
+			strings.TrimSuffix(filepath.Base(path), ".jsonl"),
+			stats.CreatedAt.Local().Format("2006-01-02 15:04"),
+			stats.LastActivity.Local().Format("15:04"),
+			len(commits),
+		)
+		for _, c := range commits {
+			fmt.Printf("  %s %s %s\n", c.ShortHash(), c.Time.Local().Format("2006-01-02 15:04"), c.Subject)
+			for _, file := range c.Files {
+				mark := " "
+				if c.IsEdited(file) {
+					mark = "*"
+				}
+				fmt.Printf("    %s %s\n", mark, file)
+			}
+		}
+		fmt.Println()
+	}
+
+	if found == 0 {
+		fmt.Println("No commits were made during these sessions")
+		return nil
+	}
+	fmt.Println("* file also changed by the session's Edit, MultiEdit or Write calls")
+	return nil
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/thieso2/promptwatch/internal/diff"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// runDiff aligns the user prompts of two sessions and prints the differences
+// in the responses and tool calls that followed them
+func runDiff(args []string) error {
+	fs, configPath := newFlagSet("diff", "[flags] A.jsonl B.jsonl")
+	format := fs.String("format", "text", "Output format: text or html")
+	context := fs.Int("context", 2, "Unchanged response lines shown around each change")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("need two session files")
+	}
+	if *format != "text" && *format != "html" {
+		return fmt.Errorf("unknown format %q (want text or html)", *format)
+	}
+	if _, err := setup(*configPath); err != nil {
+		return err
+	}
+
+	a, err := monitor.ParseSessionFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	b, err := monitor.ParseSessionFile(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	report := newDiffReport(fs.Arg(0), fs.Arg(1), monitor.DiffSessions(a, b), *context)
+
+	if *format == "html" {
+		return diffPage.Execute(os.Stdout, report)
+	}
+	report.writeText(os.Stdout)
+	return nil
+}
+
+// diffReport is a session diff laid out for printing
+type diffReport struct {
+	A, B    string // Session file names
+	Turns   []diffTurn
+	Changed int
+}
+
+// diffTurn is an aligned pair of turns laid out for printing
+type diffTurn struct {
+	Label      string // "A3 ↔ B2", or "A4" for a turn only one session has
+	Status     string // "same prompt", "prompt changed", "only in A", "only in B"
+	Changed    bool
+	PromptA    string // Empty when the turn is missing in A
+	PromptB    string
+	SamePrompt bool
+	Tools      []diff.Line
+	Hunks      []diff.Hunk // Response changes with context
+	Responses  int         // Response lines of the longer side
+}
+
+// newDiffReport lays out the turn diffs of two sessions
+func newDiffReport(pathA, pathB string, diffs []monitor.TurnDiff, context int) diffReport {
+	report := diffReport{A: filepath.Base(pathA), B: filepath.Base(pathB)}
+	for _, d := range diffs {
+		t := diffTurn{SamePrompt: d.SamePrompt, Changed: d.Changed(), Tools: d.Tools, Hunks: diff.Hunks(d.Responses, context)}
+		switch {
+		case d.A == nil:
+			t.Label, t.Status = fmt.Sprintf("B%d", d.IndexB+1), "only in B"
+		case d.B == nil:
+			t.Label, t.Status = fmt.Sprintf("A%d", d.IndexA+1), "only in A"
+		case d.SamePrompt:
+			t.Label, t.Status = fmt.Sprintf("A%d ↔ B%d", d.IndexA+1, d.IndexB+1), "same prompt"
+		default:
+			t.Label, t.Status = fmt.Sprintf("A%d ↔ B%d", d.IndexA+1, d.IndexB+1), "prompt changed"
+		}
+		if d.A != nil {
+			t.PromptA = promptText(d.A)
+			t.Responses = len(d.A.Responses)
+		}
+		if d.B != nil {
+			t.PromptB = promptText(d.B)
+			t.Responses = max(t.Responses, len(d.B.Responses))
+		}
+		if t.Changed {
+			report.Changed++
+		}
+		report.Turns = append(report.Turns, t)
+	}
+	return report
+}
+
+// promptText returns a turn's prompt, naming the turn before the first prompt
+func promptText(t *monitor.Turn) string {
+	if t.Prompt == "" {
+		return "(before the first prompt)"
+	}
+	return t.Prompt
+}
+
+// writeText prints the report: unchanged turns on one line, changed turns
+// with their prompts, tool call sequence and response hunks
+func (r diffReport) writeText(w io.Writer) {
+	fmt.Fprintf(w, "--- A: %s\n+++ B: %s\n", r.A, r.B)
+	fmt.Fprintf(w, "%d aligned turns, %d differ\n", len(r.Turns), r.Changed)
+
+	for _, t := range r.Turns {
+		if !t.Changed {
+			fmt.Fprintf(w, "\n  %s · identical · %s\n", t.Label, truncate(t.PromptA, 60))
+			continue
+		}
+		fmt.Fprintf(w, "\n* %s · %s\n", t.Label, t.Status)
+		switch {
+		case t.SamePrompt:
+			fmt.Fprintf(w, "  > %s\n", truncate(t.PromptA, 100))
+		default:
+			if t.PromptA != "" {
+				fmt.Fprintf(w, "  -> %s\n", truncate(t.PromptA, 100))
+			}
+			if t.PromptB != "" {
+				fmt.Fprintf(w, "  +> %s\n", truncate(t.PromptB, 100))
+			}
+		}
+
+		if len(t.Tools) > 0 {
+			var calls []string
+			for _, l := range t.Tools {
+				calls = append(calls, strings.TrimSpace(l.Op.Prefix())+l.Text)
+			}
+			fmt.Fprintf(w, "  tools: %s\n", strings.Join(calls, " "))
+		}
+		if len(t.Hunks) == 0 {
+			fmt.Fprintf(w, "  responses identical (%d lines)\n", t.Responses)
+			continue
+		}
+		for _, h := range t.Hunks {
+			fmt.Fprintf(w, "  %s\n", h.Header())
+			for _, l := range h.Lines {
+				fmt.Fprintf(w, "  %s%s\n", l.Op.Prefix(), l.Text)
+			}
+		}
+	}
+}
+
+// opClass names the CSS class of an edit operation
+func opClass(op diff.Op) string {
+	switch op {
+	case diff.Delete:
+		return "del"
+	case diff.Insert:
+		return "ins"
+	}
+	return "eq"
+}
+
+// diffPage renders a report as a self-contained HTML page
+var diffPage = template.Must(template.New("diff").Funcs(template.FuncMap{"op": opClass}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Session diff: {{.A}} ↔ {{.B}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 2em; color: #222; }
+h1 { font-size: 1.3em; }
+section { border: 1px solid #ddd; border-radius: 4px; margin: 1em 0; padding: 0.5em 1em; }
+section.same { color: #888; }
+h2 { font-size: 1em; margin: 0.3em 0; }
+.prompts { display: grid; grid-template-columns: 1fr 1fr; gap: 1em; }
+.prompt { white-space: pre-wrap; background: #f6f8fa; padding: 0.5em; border-radius: 4px; }
+.tool { display: inline-block; font-family: monospace; padding: 0 0.4em; margin: 0.1em; border-radius: 3px; background: #eee; }
+pre { background: #fafafa; padding: 0.5em; overflow-x: auto; }
+.del { background: #ffebe9; color: #82071e; }
+.ins { background: #dafbe1; color: #116329; }
+.tool.del { text-decoration: line-through; }
+.hunk { color: #6e7781; }
+</style>
+</head>
+<body>
+<h1>A: {{.A}} ↔ B: {{.B}}</h1>
+<p>{{len .Turns}} aligned turns, {{.Changed}} differ</p>
+{{range .Turns}}{{if .Changed}}<section>
+<h2>{{.Label}} · {{.Status}}</h2>
+{{if .SamePrompt}}<div class="prompt">{{.PromptA}}</div>
+{{else}}<div class="prompts"><div class="prompt del">{{.PromptA}}</div><div class="prompt ins">{{.PromptB}}</div></div>
+{{end}}{{if .Tools}}<p>Tools: {{range .Tools}}<span class="tool {{op .Op}}">{{.Text}}</span>{{end}}</p>
+{{end}}{{if .Hunks}}<pre>{{range .Hunks}}<span class="hunk">{{.Header}}</span>
+{{range .Lines}}<span class="{{op .Op}}">{{.Op.Prefix}}{{.Text}}</span>
+{{end}}{{end}}</pre>
+{{else}}<p>Responses identical ({{.Responses}} lines)</p>
+{{end}}</section>
+{{else}}<section class="same"><h2>{{.Label}} · identical</h2><div>{{.PromptA}}</div></section>
+{{end}}{{end}}</body>
+</html>
+`))
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// runFiles prints the file activity of a session or project
+func runFiles(args []string) error {
+	fs, configPath := newFlagSet("files", "[flags] [DIR | SESSION.jsonl]")
+	sortBy := fs.String("sort", "churn", "Sort order: "+strings.Join(monitor.FileSortKeys, ", "))
+	depth := fs.Int("depth", 0, "Group by directory, keeping this many path components (0 lists files)")
+	limit := fs.Int("limit", 0, "Show at most this many rows (0 shows all)")
+	sessionFilter := fs.String("filter", "", "Only sessions matching this filter expression, e.g. 'tool:Bash cost>1'")
+	fs.Parse(args)
+
+	if !slices.Contains(monitor.FileSortKeys, *sortBy) {
+		return fmt.Errorf("unknown sort order %q (want one of %s)", *sortBy, strings.Join(monitor.FileSortKeys, ", "))
+	}
+	if _, err := setup(*configPath); err != nil {
+		return err
+	}
+
+	sessionFiles, err := sessionFilesFor(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	sessions, err := parseSessionFiles(sessionFiles, *sessionFilter)
+	if err != nil {
+		return err
+	}
+	report := monitor.CollectFileActivity(sessions)
+
+	rows := report.Files
+	if *depth > 0 {
+		rows = report.ByDirectory(*depth)
+	}
+	monitor.SortFileActivity(rows, *sortBy)
+	if *limit > 0 && len(rows) > *limit {
+		rows = rows[:*limit]
+	}
+
+	if len(rows) == 0 {
+		fmt.Println("No file activity found")
+		return nil
+	}
+
+	fmt.Printf("%d files touched in %d sessions", len(report.Files), report.Sessions)
+	if report.WorkingDir != "" {
+		fmt.Printf(" (paths relative to %s)", report.WorkingDir)
+	}
+	fmt.Print("\n\n")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tREADS\tEDITS\t+LINES\t-LINES\tSESSIONS\tLAST TOUCH")
+	fmt.Fprintln(w, "----\t-----\t-----\t------\t------\t--------\t----------")
+	for _, file := range rows {
+		path := file.Path
+		if *depth == 0 {
+			path = report.RelativePath(path)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t+%d\t-%d\t%d\t%s\n",
+			path,
+			file.Reads,
+			file.Edits,
+			file.LinesAdded,
+			file.LinesRemoved,
+			file.Sessions,
+			file.LastTouch.Local().Format("2006-01-02 15:04"),
+		)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/thieso2/promptwatch/internal/diff"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// runHistory lists the checkpoints of a session and shows or diffs the
+// state of a tracked file at them
+func runHistory(args []string) error {
+	fs, configPath := newFlagSet("history", "[flags] SESSION.jsonl [FILE]")
+	at := fs.String("at", "", "Print FILE as it was at checkpoint N")
+	diffRange := fs.String("diff", "", "Diff FILE between two checkpoints, e.g. 2:5 or 3:current")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("missing session file")
+	}
+	if (*at != "" || *diffRange != "") && fs.NArg() < 2 {
+		return fmt.Errorf("-at and -diff need a FILE argument")
+	}
+	if _, err := setup(*configPath); err != nil {
+		return err
+	}
+
+	stats, err := monitor.ParseSessionFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	h := monitor.NewFileHistory(stats)
+	if len(h.Checkpoints) == 0 {
+		fmt.Println("Session has no file-history checkpoints")
+		return nil
+	}
+
+	file := fs.Arg(1)
+	switch {
+	case *at != "":
+		n, err := parseCheckpoint(h, *at)
+		if err != nil {
+			return err
+		}
+		content, exists, err := h.Content(file, n)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%s did not exist at checkpoint %d", file, n+1)
+		}
+		fmt.Print(content)
+		return nil
+	case *diffRange != "":
+		return printCheckpointDiff(h, file, *diffRange)
+	case file != "":
+		return printFileVersions(h, file)
+	}
+	return printCheckpoints(h)
+}
+
+// parseCheckpoint converts a 1-based checkpoint number to an index
+func parseCheckpoint(h *monitor.FileHistory, s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > len(h.Checkpoints) {
+		return 0, fmt.Errorf("invalid checkpoint %q (want 1-%d)", s, len(h.Checkpoints))
+	}
+	return n - 1, nil
+}
+
+// printCheckpoints lists every checkpoint with the files it recorded
+func printCheckpoints(h *monitor.FileHistory) error {
+	fmt.Printf("%d checkpoints, %d tracked files", len(h.Checkpoints), len(h.Files()))
+	if h.Dir == "" {
+		fmt.Print(" (backups not found)")
+	}
+	fmt.Print("\n\n")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tTIME\tFILES\tPROMPT")
+	fmt.Fprintln(w, "-\t----\t-----\t------")
+	for i, c := range h.Checkpoints {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\n",
+			i+1,
+			c.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			len(c.Backups),
+			truncate(h.Prompts[i], 60),
+		)
+	}
+	return w.Flush()
+}
+
+// printFileVersions lists the state of file at each checkpoint
+func printFileVersions(h *monitor.FileHistory, file string) error {
+	versions := h.Versions(file)
+	if len(versions) == 0 {
+		return fmt.Errorf("%s is not tracked in this session (tracked: %s)", file, strings.Join(h.Files(), ", "))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tTIME\tVERSION\tSTATE\tPROMPT")
+	fmt.Fprintln(w, "-\t----\t-------\t-----\t------")
+	for _, v := range versions {
+		state := "saved"
+		if !v.Backup.Exists() {
+			state = "absent"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\n",
+			v.Checkpoint+1,
+			h.Checkpoints[v.Checkpoint].Timestamp.Local().Format("2006-01-02 15:04:05"),
+			v.Backup.Version,
+			state,
+			truncate(h.Prompts[v.Checkpoint], 60),
+		)
+	}
+	return w.Flush()
+}
+
+// printCheckpointDiff prints a unified diff of file between two checkpoints;
+// "current" stands for the working tree
+func printCheckpointDiff(h *monitor.FileHistory, file, spec string) error {
+	from, to, ok := strings.Cut(spec, ":")
+	if !ok {
+		return fmt.Errorf("invalid -diff %q (want FROM:TO)", spec)
+	}
+
+	load := func(s string) (string, string, error) {
+		if s == "current" {
+			content, _, err := h.CurrentContent(file)
+			return content, "current", err
+		}
+		n, err := parseCheckpoint(h, s)
+		if err != nil {
+			return "", "", err
+		}
+		content, _, err := h.Content(file, n)
+		return content, fmt.Sprintf("checkpoint %d", n+1), err
+	}
+
+	oldText, oldLabel, err := load(from)
+	if err != nil {
+		return err
+	}
+	newText, newLabel, err := load(to)
+	if err != nil {
+		return err
+	}
+
+	out := diff.Unified(file+" ("+oldLabel+")", file+" ("+newLabel+")", oldText, newText, 3)
+	if out == "" {
+		fmt.Printf("%s is identical at %s and %s\n", file, oldLabel, newLabel)
+		return nil
+	}
+	fmt.Print(out)
+	return nil
+}
+
+// truncate shortens s to one line of at most n runes
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
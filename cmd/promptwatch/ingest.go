@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/monitor"
+	"github.com/thieso2/promptwatch/internal/store"
+)
+
+// runIngest adds new session lines to the session database
+func runIngest(args []string) error {
+	fs, configPath := newFlagSet("ingest", "[flags] [DIR | SESSION.jsonl]")
+	dbPath := fs.String("db", "", "Database file (default [database] path, $XDG_DATA_HOME/promptwatch/sessions.db)")
+	full := fs.Bool("full", false, "Drop the ingested data and ingest every file from the start")
+	fs.Parse(args)
+
+	cfg, err := setup(*configPath)
+	if err != nil {
+		return err
+	}
+	db, err := openDatabase(cfg, *dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if db.Dropped {
+		fmt.Println("The database was made by another version of promptwatch; its data was dropped and every file is ingested again")
+	}
+	if *full {
+		if err := db.Reset(); err != nil {
+			return err
+		}
+	}
+
+	// Without an argument every session of every project is ingested
+	sessionFiles := monitor.AllSessionFiles(time.Time{})
+	if fs.NArg() > 0 {
+		if sessionFiles, err = sessionFilesFor(fs.Arg(0)); err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	stats, err := db.Ingest(sessionFiles)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Ingested %d lines from %d files (%d unchanged", stats.Lines, stats.Files, stats.Unchanged)
+	if stats.Rewritten > 0 {
+		fmt.Printf(", %d rewritten", stats.Rewritten)
+	}
+	fmt.Printf("): %d messages, %d tool calls in %v\n", stats.Messages, stats.ToolCalls, time.Since(start).Round(time.Millisecond))
+	fmt.Printf("Database: %s\n", db.Path)
+	return nil
+}
+
+// openDatabase opens the session database at path, or at the configured path
+func openDatabase(cfg *config.Config, path string) (*store.DB, error) {
+	if path == "" {
+		var err error
+		if path, err = cfg.DatabasePath(); err != nil {
+			return nil, err
+		}
+	}
+	return store.Open(path)
+}
@@ -4,23 +4,60 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
 	"text/tabwriter"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/filter"
 	"github.com/thieso2/promptwatch/internal/monitor"
 	"github.com/thieso2/promptwatch/internal/ui"
 )
 
 func main() {
+	// Subcommands such as "promptwatch files" have their own flags
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	// Parse CLI flags
 	interval := flag.Duration("interval", 1*time.Second, "Refresh interval")
 	showHelpers := flag.Bool("show-helpers", false, "Show MCP helper processes")
 	processMode := flag.Bool("p", false, "Show processes (CLI mode)")
 	sessionsDir := flag.String("d", "", "Show sessions for directory (CLI mode)")
 	inspectFile := flag.String("i", "", "Inspect session file (CLI mode)")
+	configPath := flag.String("config", "", "Path to config file (default $XDG_CONFIG_HOME/promptwatch/config.toml)")
+	viewName := flag.String("view", "", "Open a saved view ([[view]] in the config); filters -d and -i output")
+	flag.Usage = printUsage
 	flag.Parse()
 
+	// Load and validate the config, then install matchers, roots and pricing
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	overrideFromFlags(cfg, *interval)
+	applyConfig(cfg)
+
+	var view config.ViewConfig
+	if *viewName != "" {
+		if view, err = lookupView(cfg, *viewName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Handle CLI modes
 	if *processMode {
 		cliShowProcesses(*showHelpers)
@@ -28,18 +65,22 @@ func main() {
 	}
 
 	if *sessionsDir != "" {
-		cliShowSessions(*sessionsDir)
+		cliShowSessions(*sessionsDir, viewFilter(view, "sessions", "-d"))
 		return
 	}
 
 	if *inspectFile != "" {
-		cliInspectSession(*inspectFile)
+		cliInspectSession(*inspectFile, viewFilter(view, "messages", "-i"))
 		return
 	}
 
 	// Run TUI mode
-	model := ui.NewModel(*interval, *showHelpers)
+	model := ui.NewModel(cfg, *showHelpers)
+	if *viewName != "" {
+		model.StartInView(view)
+	}
 	program := tea.NewProgram(model, tea.WithAltScreen())
+	go reloadOnHangup(program, *configPath, *interval)
 
 	if _, err := program.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -47,6 +88,81 @@ func main() {
 	}
 }
 
+// loadConfig reads the config file from the given path or the default location
+func loadConfig(path string) (*config.Config, error) {
+	if path == "" {
+		defaultPath, err := config.DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+	return config.Load(path)
+}
+
+// applyConfig installs the settings that live in the monitor package
+func applyConfig(cfg *config.Config) {
+	// Matchers were compiled during validation, so errors cannot occur here
+	matchers, _ := cfg.ProcessMatchers()
+	monitor.SetExtraMatchers(matchers)
+	monitor.SetDataRoots(cfg.DataRoots())
+	monitor.SetPricingOverrides(cfg.PricingOverrides())
+	monitor.SetContextLimits(cfg.Context.Limits)
+	monitor.SetAutoCompactThreshold(cfg.Context.AutoCompact)
+}
+
+// lookupView finds a saved view by name
+func lookupView(cfg *config.Config, name string) (config.ViewConfig, error) {
+	if v, ok := cfg.View(name); ok {
+		return v, nil
+	}
+	if len(cfg.SavedViews) == 0 {
+		return config.ViewConfig{}, fmt.Errorf("unknown view %q (no [[view]] tables in the config)", name)
+	}
+	return config.ViewConfig{}, fmt.Errorf("unknown view %q (want one of %s)", name, strings.Join(cfg.ViewNames(), ", "))
+}
+
+// viewFilter returns the filter of the saved view for a CLI mode, exiting
+// when the view is of another mode. Without a view it returns nil.
+func viewFilter(v config.ViewConfig, mode, flagName string) *filter.Expr {
+	if v.Name == "" {
+		return nil
+	}
+	if v.Mode != mode {
+		fmt.Fprintf(os.Stderr, "Error: %s takes a %s view, %q is a %s view\n", flagName, mode, v.Name, v.Mode)
+		os.Exit(1)
+	}
+	expr, _ := v.Expr() // Parsed when the config was validated
+	return expr
+}
+
+// overrideFromFlags lets command-line flags that were set explicitly win over the config file
+func overrideFromFlags(cfg *config.Config, interval time.Duration) {
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "interval" {
+			cfg.UI.RefreshInterval = interval
+		}
+	})
+}
+
+// reloadOnHangup re-reads the config file whenever the process receives SIGHUP.
+// An invalid file is reported in the UI and the running config is kept.
+func reloadOnHangup(program *tea.Program, path string, interval time.Duration) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	for range hangup {
+		cfg, err := loadConfig(path)
+		if err != nil {
+			program.Send(ui.ConfigErrorMsg{Err: err})
+			continue
+		}
+		overrideFromFlags(cfg, interval)
+		applyConfig(cfg)
+		program.Send(ui.ConfigReloadedMsg{Config: cfg})
+	}
+}
+
 // cliShowProcesses displays all Claude processes in CLI mode
 func cliShowProcesses(showHelpers bool) {
 	processes, err := monitor.FindClaudeProcesses(showHelpers)
@@ -61,15 +177,17 @@ func cliShowProcesses(showHelpers bool) {
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "PID\tCPU%\tMEM\tUPTIME\tWORKDIR\tCOMMAND")
-	fmt.Fprintln(w, "---\t----\t---\t------\t-------\t-------")
+	fmt.Fprintln(w, "PID\tCPU%\tMEM\tUPTIME\tLAUNCH\tROOT\tWORKDIR\tCOMMAND")
+	fmt.Fprintln(w, "---\t----\t---\t------\t------\t----\t-------\t-------")
 
 	for _, proc := range processes {
-		fmt.Fprintf(w, "%d\t%.1f%%\t%.2fM\t%v\t%s\t%s\n",
+		fmt.Fprintf(w, "%d\t%.1f%%\t%.2fM\t%v\t%s\t%s\t%s\t%s\n",
 			proc.PID,
 			proc.CPUPercent,
 			proc.MemoryMB,
 			proc.Uptime,
+			proc.Launcher,
+			proc.Root,
 			proc.WorkingDir,
 			truncateCmd(proc.Command, 50),
 		)
@@ -77,13 +195,20 @@ func cliShowProcesses(showHelpers bool) {
 	w.Flush()
 }
 
-// cliShowSessions displays all sessions for a directory in CLI mode
-func cliShowSessions(dir string) {
+// cliShowSessions displays the sessions for a directory that pass the
+// session filter in CLI mode
+func cliShowSessions(dir string, sessionFilter *filter.Expr) {
 	sessions, err := monitor.FindSessionsForDirectory(dir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error finding sessions: %v\n", err)
 		os.Exit(1)
 	}
+	if !sessionFilter.IsEmpty() {
+		sessions = slices.DeleteFunc(sessions, func(s monitor.Session) bool {
+			stats, err := monitor.ParseSessionFile(s.FilePath)
+			return err != nil || !sessionFilter.Match(filter.SessionFromStats(stats))
+		})
+	}
 
 	if len(sessions) == 0 {
 		fmt.Printf("No sessions found for directory: %s\n", dir)
@@ -93,12 +218,13 @@ func cliShowSessions(dir string) {
 	fmt.Printf("Found %d sessions for: %s\n\n", len(sessions), dir)
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "SESSION ID\tTITLE\tUPDATED\tFILE")
-	fmt.Fprintln(w, "----------\t-----\t-------\t----")
+	fmt.Fprintln(w, "SESSION ID\tROOT\tTITLE\tUPDATED\tFILE")
+	fmt.Fprintln(w, "----------\t----\t-----\t-------\t----")
 
 	for _, sess := range sessions {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
 			sess.ID[:8]+"...",
+			sess.Root,
 			sess.GetSessionInfo(),
 			sess.GetSessionTime(),
 			sess.FilePath,
@@ -107,8 +233,9 @@ func cliShowSessions(dir string) {
 	w.Flush()
 }
 
-// cliInspectSession displays detailed information about a session in CLI mode
-func cliInspectSession(filePath string) {
+// cliInspectSession displays detailed information about a session in CLI
+// mode, listing the messages that pass the message filter
+func cliInspectSession(filePath string, messageFilter *filter.Expr) {
 	stats, err := monitor.ParseSessionFile(filePath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing session: %v\n", err)
@@ -140,9 +267,9 @@ func cliInspectSession(filePath string) {
 	fmt.Printf("Errors:              %d\n", stats.ErrorCount)
 	fmt.Println()
 
-	if len(stats.MessageHistory) > 0 {
+	if messages := messageFilter.Messages(stats.MessageHistory); len(messages) > 0 {
 		fmt.Println("=== CONVERSATION ===")
-		for i, msg := range stats.MessageHistory {
+		for i, msg := range messages {
 			role := msg.Role
 			if msg.Role == "user" {
 				role = "👤 user"
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// runRecord samples the running Claude processes into the sample file until
+// interrupted, for the resource history view
+func runRecord(args []string) error {
+	fs, configPath := newFlagSet("record", "[flags]")
+	interval := fs.Duration("interval", 0, "Time between samples (default [record] interval, 30s)")
+	file := fs.String("file", "", "Sample file (default [record] path, $XDG_STATE_HOME/promptwatch/samples.jsonl)")
+	once := fs.Bool("once", false, "Take one sample and exit, e.g. from cron")
+	fs.Parse(args)
+
+	cfg, err := setup(*configPath)
+	if err != nil {
+		return err
+	}
+	if *file != "" {
+		cfg.Record.Path = *file
+	}
+	if *interval == 0 {
+		*interval = cfg.Record.Interval
+	} else if *interval < time.Second {
+		return fmt.Errorf("interval %v is too short (minimum 1s)", *interval)
+	}
+	store, err := cfg.SampleStore()
+	if err != nil {
+		return err
+	}
+
+	record := func() error {
+		// Helpers are included so their memory counts towards the project
+		processes, err := monitor.FindClaudeProcesses(true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "promptwatch record: %v\n", err)
+			return nil // Try again at the next sample
+		}
+		return store.Append(monitor.NewSample(processes, time.Now()))
+	}
+
+	if *once {
+		return record()
+	}
+	fmt.Fprintf(os.Stderr, "Recording to %s every %v (Ctrl+C to stop)\n", store.Path, *interval)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		if err := record(); err != nil {
+			return err
+		}
+		<-ticker.C
+	}
+}
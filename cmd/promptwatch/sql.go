@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/thieso2/promptwatch/internal/store"
+)
+
+// runSQL runs a query against the session database and prints the rows
+func runSQL(args []string) error {
+	fs, configPath := newFlagSet("sql", "[flags] QUERY")
+	dbPath := fs.String("db", "", "Database file (default [database] path, $XDG_DATA_HOME/promptwatch/sessions.db)")
+	format := fs.String("format", "table", "Output format: table or csv")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("missing query")
+	}
+	if *format != "table" && *format != "csv" {
+		return fmt.Errorf("unknown format %q (want table or csv)", *format)
+	}
+	cfg, err := setup(*configPath)
+	if err != nil {
+		return err
+	}
+	if *dbPath == "" {
+		if *dbPath, err = cfg.DatabasePath(); err != nil {
+			return err
+		}
+	}
+	if _, err := os.Stat(*dbPath); os.IsNotExist(err) {
+		return fmt.Errorf("no database at %s; run 'promptwatch ingest' first", *dbPath)
+	}
+	db, err := store.OpenReadOnly(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	result, err := db.Query(strings.Join(fs.Args(), " "))
+	if err != nil {
+		return err
+	}
+
+	if *format == "csv" {
+		w := csv.NewWriter(os.Stdout)
+		w.Write(result.Columns)
+		w.WriteAll(result.Rows)
+		return w.Error()
+	}
+	if len(result.Columns) == 0 {
+		return nil // A statement without rows, e.g. CREATE VIEW
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(result.Columns, "\t"))
+	dashes := make([]string, len(result.Columns))
+	for i, col := range result.Columns {
+		dashes[i] = strings.Repeat("-", len(col))
+	}
+	fmt.Fprintln(w, strings.Join(dashes, "\t"))
+	for _, row := range result.Rows {
+		for i, v := range row {
+			// Keep multi-line text on one row
+			row[i] = strings.ReplaceAll(v, "\n", " ")
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n%d rows\n", len(result.Rows))
+	return nil
+}
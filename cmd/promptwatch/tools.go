@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// runTools prints tool usage statistics across sessions
+func runTools(args []string) error {
+	fs, configPath := newFlagSet("tools", "[flags] [DIR | SESSION.jsonl]")
+	project := fs.String("project", "", "Only calls made in this working directory (path or directory name)")
+	model := fs.String("model", "", "Only calls made by models containing this text, e.g. opus")
+	since := fs.String("since", "", "Only calls on or after this date (YYYY-MM-DD)")
+	until := fs.String("until", "", "Only calls on or before this date (YYYY-MM-DD)")
+	sessionFilter := fs.String("filter", "", "Only sessions matching this filter expression, e.g. 'tool:Bash cost>1'")
+	sortBy := fs.String("sort", "calls", "Sort order: "+strings.Join(monitor.ToolSortKeys, ", "))
+	fs.Parse(args)
+
+	if !slices.Contains(monitor.ToolSortKeys, *sortBy) {
+		return fmt.Errorf("unknown sort order %q (want one of %s)", *sortBy, strings.Join(monitor.ToolSortKeys, ", "))
+	}
+	filter := monitor.ToolFilter{Project: *project, Model: *model}
+	var err error
+	if filter.Since, err = parseDate(*since); err != nil {
+		return err
+	}
+	if filter.Until, err = parseDate(*until); err != nil {
+		return err
+	}
+	if !filter.Until.IsZero() {
+		filter.Until = filter.Until.AddDate(0, 0, 1) // Include the whole day
+	}
+	if _, err := setup(*configPath); err != nil {
+		return err
+	}
+
+	// Without an argument every session of every project counts
+	sessionFiles := monitor.AllSessionFiles(filter.Since)
+	if fs.NArg() > 0 {
+		if sessionFiles, err = sessionFilesFor(fs.Arg(0)); err != nil {
+			return err
+		}
+	}
+	if sessionFiles, err = filterSessionFiles(sessionFiles, *sessionFilter); err != nil {
+		return err
+	}
+	calls, err := monitor.LoadToolCalls(sessionFiles)
+	if err != nil {
+		return err
+	}
+
+	report := monitor.AggregateToolCalls(calls, filter)
+	if report.Calls == 0 {
+		fmt.Println("No tool calls found")
+		return nil
+	}
+	fmt.Printf("%d calls of %d tools in %d sessions\n\n", report.Calls, len(report.Tools), len(sessionFiles))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TOOL\tCALLS\tERR%\tP50\tP95\tAVG INPUT\tAVG RESULT")
+	fmt.Fprintln(w, "----\t-----\t----\t---\t---\t---------\t----------")
+	for _, tool := range report.Grouped(*sortBy) {
+		name := tool.Name
+		switch {
+		case tool.ServerTotal:
+			name = "mcp: " + tool.Server
+		case tool.Server != "":
+			name = "  " + tool.Name
+		}
+		fmt.Fprintf(w, "%s\t%d\t%.1f\t%s\t%s\t%s\t%s\n",
+			name,
+			tool.Calls,
+			tool.ErrorRate(),
+			monitor.FormatLatency(tool.MedianLatency),
+			monitor.FormatLatency(tool.P95Latency),
+			monitor.FormatSize(tool.AvgInputSize),
+			monitor.FormatSize(tool.AvgResultSize),
+		)
+	}
+	return w.Flush()
+}
+
+// parseDate parses a YYYY-MM-DD date in local time; empty means no date
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (want YYYY-MM-DD)", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,751 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/thieso2/promptwatch/internal/filter"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// Config holds user settings loaded from config.toml
+type Config struct {
+	UI         UIConfig                  `toml:"ui"`
+	Columns    ColumnsConfig             `toml:"columns"`
+	Colors     ColorsConfig              `toml:"colors"`
+	Themes     map[string]ThemeConfig    `toml:"themes"`
+	Thresholds ThresholdsConfig          `toml:"thresholds"`
+	Pricing    map[string]PricingConfig  `toml:"pricing"`
+	Context    ContextConfig             `toml:"context"`
+	Health     HealthConfig              `toml:"health"`
+	Notify     NotifyConfig              `toml:"notify"`
+	Record     RecordConfig              `toml:"record"`
+	Database   DatabaseConfig            `toml:"database"`
+	Keys       map[string][]string       `toml:"keys"`
+	Renderers  map[string]RendererConfig `toml:"renderers"`
+	Matchers   []MatcherConfig           `toml:"matcher"`
+	Roots      []RootConfig              `toml:"root"`
+	SavedViews []ViewConfig              `toml:"view"`
+
+	path string // File the config was loaded from; see SaveColumns
+}
+
+// UIConfig holds general TUI settings
+type UIConfig struct {
+	DefaultView     string        `toml:"default_view"`     // "processes" or "projects"
+	RefreshInterval time.Duration `toml:"refresh_interval"` // e.g. "1s", "500ms"
+	Keymap          string        `toml:"keymap"`           // "default", "vim" or "emacs"
+	Theme           string        `toml:"theme"`            // Built-in or [themes.<name>] theme
+}
+
+// ColumnsConfig lists the visible columns of each table, in display order.
+// An empty list shows the default columns.
+type ColumnsConfig struct {
+	Processes []string `toml:"processes"`
+	Projects  []string `toml:"projects"`
+	Sessions  []string `toml:"sessions"`
+}
+
+// ColorsConfig overrides the theme's threshold highlighting colours.
+// Values are ANSI colour numbers ("0"-"255") or hex colours ("#ff8800").
+type ColorsConfig struct {
+	OK       string `toml:"ok"`
+	Warning  string `toml:"warning"`
+	Critical string `toml:"critical"`
+}
+
+// ThemeConfig defines a custom theme as colours per role (see ThemeRoles).
+// The optional "base" entry names the built-in theme it starts from.
+//
+//	[themes.solarized]
+//	base      = "light"
+//	highlight = "#b58900"
+type ThemeConfig map[string]string
+
+// ThresholdsConfig holds the limits at which values are highlighted
+type ThresholdsConfig struct {
+	CPUWarning       float64 `toml:"cpu_warning"`        // Percent
+	CPUCritical      float64 `toml:"cpu_critical"`       // Percent
+	MemoryWarningMB  float64 `toml:"memory_warning_mb"`  // Resident memory in MB
+	MemoryCriticalMB float64 `toml:"memory_critical_mb"` // Resident memory in MB
+	CostWarning      float64 `toml:"cost_warning"`       // USD per message
+	CostCritical     float64 `toml:"cost_critical"`      // USD per message
+	ContextWarning   float64 `toml:"context_warning"`    // Percent of the context window
+	ContextCritical  float64 `toml:"context_critical"`   // Percent of the context window
+}
+
+// PricingConfig overrides token prices for models whose name starts with the
+// table key, e.g. [pricing."claude-opus-4"]. Prices are USD per million tokens.
+type PricingConfig struct {
+	Input      float64 `toml:"input"`
+	Output     float64 `toml:"output"`
+	CacheWrite float64 `toml:"cache_write"`
+	CacheRead  float64 `toml:"cache_read"`
+}
+
+// ContextConfig sets the context windows used for context utilisation
+//
+//	[context]
+//	auto_compact = 0.92                      # Share of the window that triggers auto-compact
+//	limits = { "claude-sonnet-4" = 1000000 } # Tokens, matched by model name prefix
+type ContextConfig struct {
+	AutoCompact float64        `toml:"auto_compact"`
+	Limits      map[string]int `toml:"limits"`
+}
+
+// HealthConfig sets the thresholds of the runaway and stuck-agent alerts
+// shown in the process view. A zero value disables its rule.
+type HealthConfig struct {
+	RepeatCalls int           `toml:"repeat_calls"` // Identical tool calls in a row
+	ErrorStreak int           `toml:"error_streak"` // Failed tool results in a row
+	BusyCPU     float64       `toml:"busy_cpu"`     // CPU percent that counts as busy
+	BusySilence time.Duration `toml:"busy_silence"` // Busy this long without new session lines
+	IdleAfter   time.Duration `toml:"idle_after"`   // No new session lines this long while running
+}
+
+// NotifyConfig selects the session events to announce and how
+//
+//	[notify]
+//	backends = ["osc9", "command"]
+//	events   = ["turn_done", "permission"]
+type NotifyConfig struct {
+	Backends       []string      `toml:"backends"`        // See NotifyBackends; none by default
+	Events         []string      `toml:"events"`          // See monitor.EventKinds; all by default
+	Command        []string      `toml:"command"`         // Program and arguments; title and body are appended
+	Webhook        string        `toml:"webhook"`         // URL the events are posted to as JSON
+	TurnSettle     time.Duration `toml:"turn_settle"`     // Quiet time before a reply counts as the end of the turn
+	PermissionWait time.Duration `toml:"permission_wait"` // Pending time before a tool call counts as a permission prompt
+	ToolCPU        float64       `toml:"tool_cpu"`        // CPU percent of the process and its children that keeps a pending tool call running
+	CostStep       float64       `toml:"cost_step"`       // USD; announce each time a session's cost passes a multiple
+}
+
+// RecordConfig sets where and how often `promptwatch record` samples the
+// running processes
+//
+//	[record]
+//	interval = "1m"
+//	max_size_mb = 20
+type RecordConfig struct {
+	Path      string        `toml:"path"`        // Sample file; $XDG_STATE_HOME/promptwatch/samples.jsonl by default
+	Interval  time.Duration `toml:"interval"`    // Time between samples
+	MaxSizeMB float64       `toml:"max_size_mb"` // Size at which the file is rotated
+	Keep      int           `toml:"keep"`        // Rotated files kept besides the current one
+}
+
+// DatabaseConfig sets where `promptwatch ingest` keeps the session database
+//
+//	[database]
+//	path = "/data/promptwatch/sessions.db"
+type DatabaseConfig struct {
+	Path string `toml:"path"` // $XDG_DATA_HOME/promptwatch/sessions.db by default
+}
+
+// ViewConfig is a saved view: a view mode with its sort order, columns and
+// filter expression, picked by name in the TUI or with --view
+//
+//	[[view]]
+//	name   = "failing Bash calls"
+//	mode   = "messages"
+//	filter = "tool:Bash error:true"
+type ViewConfig struct {
+	Name    string   `toml:"name"`
+	Mode    string   `toml:"mode"`    // See ViewModes
+	Sort    string   `toml:"sort"`    // A column of SortColumns, "-column" for descending; the mode's default when empty
+	Columns []string `toml:"columns"` // Table columns of processes, projects and sessions; [columns] when empty
+	Filter  string   `toml:"filter"`  // Session filter for sessions, message filter for messages
+}
+
+// NotifyBackends lists the notification backends
+var NotifyBackends = []string{"bell", "osc9", "osc777", "command", "webhook"}
+
+// MatcherConfig declares an extra process matcher for wrappers around the CLI
+//
+//	[[matcher]]
+//	name = "team-wrapper"
+//	exe  = "/opt/acme/bin/acme-claude$"
+//	args = "--profile acme"
+type MatcherConfig struct {
+	Name string `toml:"name"`
+	Exe  string `toml:"exe"`  // Regular expression matched against the executable path
+	Args string `toml:"args"` // Regular expression matched against argv joined by spaces
+}
+
+// RootConfig declares a Claude data root to watch. When no roots are
+// configured, $CLAUDE_CONFIG_DIR and ~/.claude are used.
+//
+//	[[root]]
+//	label = "alice"
+//	dir   = "/home/alice/.claude"
+type RootConfig struct {
+	Label string `toml:"label"` // Optional; derived from the directory when empty
+	Dir   string `toml:"dir"`
+}
+
+// RendererConfig declares template renderers for tools whose name matches the
+// table key, either a tool name or a glob such as "mcp__github__*". Templates
+// use Go text/template syntax with the functions in RendererFuncs.
+//
+//	[renderers."mcp__github__create_issue"]
+//	input  = "{{.owner}}/{{.repo}}: {{.title}}"
+//	result = "{{.html_url}}"
+type RendererConfig struct {
+	Input  string `toml:"input"`  // Executed with the decoded tool input
+	Result string `toml:"result"` // Executed with the decoded JSON result, or the result text
+}
+
+// RendererTemplate holds the compiled templates of a renderer; unset templates are nil
+type RendererTemplate struct {
+	Pattern string
+	Input   *template.Template
+	Result  *template.Template
+}
+
+// RendererFuncs are the functions available in renderer templates
+var RendererFuncs = template.FuncMap{
+	// json formats a value as indented JSON
+	"json": func(v interface{}) (string, error) {
+		b, err := json.MarshalIndent(v, "", "  ")
+		return string(b), err
+	},
+	// truncate shortens text to n characters, marking the cut with "…"
+	"truncate": func(n int, v interface{}) string {
+		s := []rune(fmt.Sprint(v))
+		if len(s) <= n {
+			return string(s)
+		}
+		return string(s[:max(n-1, 0)]) + "…"
+	},
+	// join concatenates the items of a list with sep
+	"join": func(sep string, items []interface{}) string {
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, sep)
+	},
+	// default returns def when v is missing or empty
+	"default": func(def, v interface{}) interface{} {
+		if v == nil || v == "" {
+			return def
+		}
+		return v
+	},
+}
+
+// Views that can be opened on startup
+var Views = []string{"processes", "projects"}
+
+// ViewModes lists the modes a saved view can open. Sessions views list the
+// sessions of every project; messages views filter the sessions opened.
+var ViewModes = []string{"processes", "projects", "sessions", "messages"}
+
+// BuiltinThemes lists the themes that ship with promptwatch
+var BuiltinThemes = []string{"dark", "light", "high-contrast", "no-color"}
+
+// ThemeRoles lists the colour roles a custom theme can set
+var ThemeRoles = []string{
+	"highlight", "text", "faint", "muted", "subtle", "border", "accent",
+	"assistant", "tool", "info", "success", "warning", "error", "selected_fg", "selected_bg",
+}
+
+// Keymaps lists the key binding presets; [keys] entries replace single actions
+var Keymaps = []string{"default", "vim", "emacs"}
+
+// Column keys available in each table, in default order
+var (
+	ProcessColumns = []string{"pid", "status", "cpu", "mem", "ctx", "alert", "uptime", "workdir", "cmd"}
+	ProjectColumns = []string{"root", "name", "modified", "sessions"}
+	SessionColumns = []string{"version", "gitbranch", "lastmsgtime", "tokens", "started", "duration", "commits", "lastmessage"}
+)
+
+// MessageSortColumns lists the orders of the message cards, which have no columns
+var MessageSortColumns = []string{"time", "cost", "tokens"}
+
+// SortColumns lists the columns each table, keyed by view mode, can be sorted by
+var SortColumns = map[string][]string{
+	"processes": ProcessColumns,
+	"projects":  ProjectColumns,
+	"sessions":  SessionColumns,
+	"messages":  MessageSortColumns,
+}
+
+// Actions that can be bound to keys in the [keys] table. Each entry replaces
+// the keys the keymap preset assigns to the action.
+var Actions = []string{
+	"quit", "back", "open", "help", "up", "down", "page_up", "page_down", "home", "end",
+	"prev", "next", "refresh", "toggle_helpers", "toggle_projects",
+	"filter_user", "filter_assistant", "filter_all", "filter", "sort", "reverse_sort",
+	"columns", "toggle_column", "toggle_markdown",
+	"file_activity", "group_dirs", "file_history", "mark", "diff", "commits",
+	"tools", "filter_model", "filter_range", "usage_history", "views", "compare",
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	return &Config{
+		UI: UIConfig{
+			DefaultView:     "processes",
+			RefreshInterval: time.Second,
+			Keymap:          "default",
+			Theme:           "dark",
+		},
+		Thresholds: ThresholdsConfig{
+			CPUWarning:       50,
+			CPUCritical:      80,
+			MemoryWarningMB:  1024,
+			MemoryCriticalMB: 2048,
+			CostWarning:      0.01,
+			CostCritical:     0.10,
+			ContextWarning:   60,
+			ContextCritical:  80,
+		},
+		Context: ContextConfig{
+			AutoCompact: monitor.DefaultAutoCompact,
+		},
+		Health: HealthConfig(monitor.DefaultHealthRules),
+		Notify: NotifyConfig{
+			Events:         append([]string{}, monitor.EventKinds...),
+			Command:        []string{"notify-send", "--app-name=promptwatch"},
+			TurnSettle:     monitor.DefaultEventRules.TurnSettle,
+			PermissionWait: monitor.DefaultEventRules.PermissionWait,
+			ToolCPU:        monitor.DefaultEventRules.ToolCPU,
+			CostStep:       monitor.DefaultEventRules.CostStep,
+		},
+		Record: RecordConfig{
+			Interval:  30 * time.Second,
+			MaxSizeMB: 10,
+			Keep:      4,
+		},
+	}
+}
+
+// DefaultPath returns $XDG_CONFIG_HOME/promptwatch/config.toml (~/.config when unset)
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot get home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "promptwatch", "config.toml"), nil
+}
+
+// Load reads and validates the config file at path on top of the defaults.
+// A missing file yields the default config.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	cfg.path = path
+
+	meta, err := toml.DecodeFile(path, cfg)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+
+	// Misspelled keys would otherwise be silently ignored
+	var problems []string
+	for _, key := range meta.Undecoded() {
+		problems = append(problems, fmt.Sprintf("%s: unknown setting", key))
+	}
+	problems = append(problems, cfg.validate()...)
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid config %s:\n  %s", path, strings.Join(problems, "\n  "))
+	}
+
+	return cfg, nil
+}
+
+// Validate checks the config for invalid values
+func (c *Config) Validate() error {
+	if problems := c.validate(); len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validate returns one message per invalid setting
+func (c *Config) validate() []string {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if !slices.Contains(Views, c.UI.DefaultView) {
+		add("ui.default_view: unknown view %q (want one of %s)", c.UI.DefaultView, strings.Join(Views, ", "))
+	}
+	if !slices.Contains(Keymaps, c.UI.Keymap) {
+		add("ui.keymap: unknown keymap %q (want one of %s)", c.UI.Keymap, strings.Join(Keymaps, ", "))
+	}
+	if c.UI.RefreshInterval < 100*time.Millisecond {
+		add("ui.refresh_interval: %v is too short (minimum 100ms)", c.UI.RefreshInterval)
+	}
+
+	checkColumns := func(name string, got, known []string) {
+		seen := make(map[string]bool)
+		for _, col := range got {
+			if !slices.Contains(known, col) {
+				add("%s: unknown column %q (want one of %s)", name, col, strings.Join(known, ", "))
+			} else if seen[col] {
+				add("%s: column %q listed twice", name, col)
+			}
+			seen[col] = true
+		}
+	}
+	checkColumns("columns.processes", c.Columns.Processes, ProcessColumns)
+	checkColumns("columns.projects", c.Columns.Projects, ProjectColumns)
+	checkColumns("columns.sessions", c.Columns.Sessions, SessionColumns)
+
+	for name, value := range map[string]string{
+		"ok":       c.Colors.OK,
+		"warning":  c.Colors.Warning,
+		"critical": c.Colors.Critical,
+	} {
+		if value != "" && !validColor(value) {
+			add("colors.%s: invalid colour %q (want 0-255 or #rrggbb)", name, value)
+		}
+	}
+
+	if _, custom := c.Themes[c.UI.Theme]; !custom && !slices.Contains(BuiltinThemes, c.UI.Theme) {
+		add("ui.theme: unknown theme %q (want one of %s or a [themes.%s] table)",
+			c.UI.Theme, strings.Join(BuiltinThemes, ", "), c.UI.Theme)
+	}
+	for name, theme := range c.Themes {
+		for role, value := range theme {
+			switch {
+			case role == "base":
+				if !slices.Contains(BuiltinThemes, value) {
+					add("themes.%s.base: unknown theme %q (want one of %s)", name, value, strings.Join(BuiltinThemes, ", "))
+				}
+			case !slices.Contains(ThemeRoles, role):
+				add("themes.%s.%s: unknown role (want one of %s)", name, role, strings.Join(ThemeRoles, ", "))
+			case value != "" && !validColor(value):
+				add("themes.%s.%s: invalid colour %q (want 0-255 or #rrggbb)", name, role, value)
+			}
+		}
+	}
+
+	checkRange := func(name string, warning, critical float64) {
+		if warning < 0 || critical < 0 {
+			add("thresholds.%s: values must not be negative", name)
+		} else if warning > critical {
+			add("thresholds.%s_warning (%g) is above %s_critical (%g)", name, warning, name, critical)
+		}
+	}
+	checkRange("cpu", c.Thresholds.CPUWarning, c.Thresholds.CPUCritical)
+	checkRange("memory", c.Thresholds.MemoryWarningMB, c.Thresholds.MemoryCriticalMB)
+	checkRange("cost", c.Thresholds.CostWarning, c.Thresholds.CostCritical)
+	checkRange("context", c.Thresholds.ContextWarning, c.Thresholds.ContextCritical)
+
+	if c.Context.AutoCompact <= 0 || c.Context.AutoCompact > 1 {
+		add("context.auto_compact: %g is not between 0 and 1", c.Context.AutoCompact)
+	}
+	h := c.Health
+	if h.RepeatCalls < 0 || h.ErrorStreak < 0 || h.BusyCPU < 0 || h.BusySilence < 0 || h.IdleAfter < 0 {
+		add("health: thresholds must not be negative")
+	}
+	n := c.Notify
+	for _, backend := range n.Backends {
+		if !slices.Contains(NotifyBackends, backend) {
+			add("notify.backends: unknown backend %q (want one of %s)", backend, strings.Join(NotifyBackends, ", "))
+		}
+	}
+	for _, event := range n.Events {
+		if !slices.Contains(monitor.EventKinds, event) {
+			add("notify.events: unknown event %q (want one of %s)", event, strings.Join(monitor.EventKinds, ", "))
+		}
+	}
+	if slices.Contains(n.Backends, "command") && len(n.Command) == 0 {
+		add("notify.command: required by the command backend")
+	}
+	if slices.Contains(n.Backends, "webhook") && !strings.HasPrefix(n.Webhook, "http://") && !strings.HasPrefix(n.Webhook, "https://") {
+		add("notify.webhook: %q is not an http(s) URL", n.Webhook)
+	}
+	if n.TurnSettle < 0 || n.PermissionWait < 0 || n.ToolCPU < 0 || n.CostStep < 0 {
+		add("notify: durations, tool_cpu and cost_step must not be negative")
+	}
+	r := c.Record
+	if r.Interval < time.Second {
+		add("record.interval: %v is too short (minimum 1s)", r.Interval)
+	}
+	if r.MaxSizeMB <= 0 {
+		add("record.max_size_mb: must be positive")
+	}
+	if r.Keep < 0 {
+		add("record.keep: must not be negative")
+	}
+	for model, limit := range c.Context.Limits {
+		if limit <= 0 {
+			add("context.limits.%s: limit must be positive", model)
+		}
+	}
+
+	for model, p := range c.Pricing {
+		if p.Input < 0 || p.Output < 0 || p.CacheWrite < 0 || p.CacheRead < 0 {
+			add("pricing.%s: prices must not be negative", model)
+		}
+	}
+
+	for action, keys := range c.Keys {
+		if !slices.Contains(Actions, action) {
+			add("keys.%s: unknown action (want one of %s)", action, strings.Join(Actions, ", "))
+		}
+		for _, k := range keys {
+			if strings.TrimSpace(k) == "" {
+				add("keys.%s: empty key", action)
+			}
+		}
+	}
+
+	for pattern, r := range c.Renderers {
+		if _, err := path.Match(pattern, ""); err != nil {
+			add("renderers.%s: invalid glob: %v", pattern, err)
+		}
+		if r.Input == "" && r.Result == "" {
+			add("renderers.%s: needs an input or result template", pattern)
+		}
+	}
+	if _, err := c.RendererTemplates(); err != nil {
+		add("%v", err)
+	}
+
+	if _, err := c.ProcessMatchers(); err != nil {
+		add("matcher: %v", err)
+	}
+
+	for i, r := range c.Roots {
+		if r.Dir == "" {
+			add("root[%d]: dir is required", i)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, v := range c.SavedViews {
+		where := fmt.Sprintf("view[%d]", i)
+		if v.Name == "" {
+			add("%s: name is required", where)
+		} else if names[v.Name] {
+			add("%s: name %q is used twice", where, v.Name)
+		}
+		names[v.Name] = true
+		if !slices.Contains(ViewModes, v.Mode) {
+			add("%s.mode: unknown mode %q (want one of %s)", where, v.Mode, strings.Join(ViewModes, ", "))
+			continue
+		}
+		if column := strings.TrimPrefix(v.Sort, "-"); v.Sort != "" && !slices.Contains(SortColumns[v.Mode], column) {
+			add("%s.sort: unknown column %q (want one of %s, with - for descending)", where, column, strings.Join(SortColumns[v.Mode], ", "))
+		}
+		switch v.Mode {
+		case "processes":
+			checkColumns(where+".columns", v.Columns, ProcessColumns)
+		case "projects":
+			checkColumns(where+".columns", v.Columns, ProjectColumns)
+		case "sessions":
+			checkColumns(where+".columns", v.Columns, SessionColumns)
+		default:
+			if len(v.Columns) > 0 {
+				add("%s.columns: %s views have no columns", where, v.Mode)
+			}
+		}
+		if _, err := v.Expr(); err != nil {
+			add("%s.filter: %v", where, err)
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+// hexColor matches #rgb and #rrggbb colours
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor reports whether s is an ANSI colour number or a hex colour
+func validColor(s string) bool {
+	if hexColor.MatchString(s) {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// ProcessMatchers compiles the configured matchers
+func (c *Config) ProcessMatchers() ([]monitor.ProcessMatcher, error) {
+	var matchers []monitor.ProcessMatcher
+	for i, mc := range c.Matchers {
+		name := mc.Name
+		if name == "" {
+			name = fmt.Sprintf("matcher[%d]", i)
+		}
+		m, err := monitor.NewPatternMatcher(name, mc.Exe, mc.Args)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// RendererTemplates compiles the configured renderer templates, sorted by pattern
+func (c *Config) RendererTemplates() ([]RendererTemplate, error) {
+	patterns := make([]string, 0, len(c.Renderers))
+	for pattern := range c.Renderers {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	parse := func(pattern, field, text string) (*template.Template, error) {
+		if text == "" {
+			return nil, nil
+		}
+		t, err := template.New(pattern + "." + field).Funcs(RendererFuncs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("renderers.%s.%s: %w", pattern, field, err)
+		}
+		return t, nil
+	}
+
+	var templates []RendererTemplate
+	for _, pattern := range patterns {
+		r := c.Renderers[pattern]
+		input, err := parse(pattern, "input", r.Input)
+		if err != nil {
+			return nil, err
+		}
+		result, err := parse(pattern, "result", r.Result)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, RendererTemplate{Pattern: pattern, Input: input, Result: result})
+	}
+	return templates, nil
+}
+
+// View returns the saved view with the given name
+func (c *Config) View(name string) (ViewConfig, bool) {
+	for _, v := range c.SavedViews {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return ViewConfig{}, false
+}
+
+// ViewNames lists the names of the saved views in config order
+func (c *Config) ViewNames() []string {
+	names := make([]string, len(c.SavedViews))
+	for i, v := range c.SavedViews {
+		names[i] = v.Name
+	}
+	return names
+}
+
+// Expr parses the view's filter: a session filter for sessions views and a
+// message filter for messages views. Other modes take no filter.
+func (v ViewConfig) Expr() (*filter.Expr, error) {
+	switch v.Mode {
+	case "sessions":
+		return filter.ParseSessions(v.Filter)
+	case "messages":
+		return filter.ParseMessages(v.Filter)
+	}
+	if v.Filter != "" {
+		return nil, fmt.Errorf("%s views take no filter", v.Mode)
+	}
+	return nil, nil
+}
+
+// DataRoots converts the configured roots, returning nil when none are set
+func (c *Config) DataRoots() []monitor.DataRoot {
+	var roots []monitor.DataRoot
+	for _, rc := range c.Roots {
+		if rc.Dir == "" {
+			continue
+		}
+		roots = append(roots, monitor.NewDataRoot(rc.Label, rc.Dir))
+	}
+	return roots
+}
+
+// PricingOverrides converts the [pricing] tables for the monitor package
+func (c *Config) PricingOverrides() map[string]monitor.ModelPricing {
+	overrides := make(map[string]monitor.ModelPricing, len(c.Pricing))
+	for model, p := range c.Pricing {
+		overrides[model] = monitor.ModelPricing{
+			Input:      p.Input,
+			Output:     p.Output,
+			CacheWrite: p.CacheWrite,
+			CacheRead:  p.CacheRead,
+		}
+	}
+	return overrides
+}
+
+// HealthRules converts the [health] table for the monitor package
+func (c *Config) HealthRules() monitor.HealthRules {
+	return monitor.HealthRules(c.Health)
+}
+
+// EventRules converts the event timings of the [notify] table for the monitor package
+func (c *Config) EventRules() monitor.EventRules {
+	return monitor.EventRules{
+		TurnSettle:     c.Notify.TurnSettle,
+		PermissionWait: c.Notify.PermissionWait,
+		ToolCPU:        c.Notify.ToolCPU,
+		CostStep:       c.Notify.CostStep,
+	}
+}
+
+// SampleStore returns the file `promptwatch record` writes and the history
+// view reads
+func (c *Config) SampleStore() (monitor.SampleStore, error) {
+	path := c.Record.Path
+	if path == "" {
+		dir := os.Getenv("XDG_STATE_HOME")
+		if dir == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return monitor.SampleStore{}, fmt.Errorf("cannot get home directory: %w", err)
+			}
+			dir = filepath.Join(home, ".local", "state")
+		}
+		path = filepath.Join(dir, "promptwatch", "samples.jsonl")
+	}
+	return monitor.SampleStore{
+		Path:    path,
+		MaxSize: int64(c.Record.MaxSizeMB * 1024 * 1024),
+		Keep:    c.Record.Keep,
+	}, nil
+}
+
+// DatabasePath returns the SQLite file `promptwatch ingest` fills and
+// `promptwatch sql` queries
+func (c *Config) DatabasePath() (string, error) {
+	if c.Database.Path != "" {
+		return c.Database.Path, nil
+	}
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("cannot get home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "promptwatch", "sessions.db"), nil
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes a config file into a temporary directory and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+// TestLoadMissingFile tests that a missing config yields the defaults
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.toml"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.UI.DefaultView != "processes" || cfg.UI.RefreshInterval != time.Second {
+		t.Errorf("Expected defaults, got %+v", cfg.UI)
+	}
+}
+
+// TestLoadSettings tests that settings override the defaults
+func TestLoadSettings(t *testing.T) {
+	path := writeConfig(t, `
+[ui]
+default_view = "projects"
+refresh_interval = "2s"
+
+[columns]
+processes = ["pid", "cpu", "workdir"]
+
+[thresholds]
+cpu_warning = 30
+
+[pricing."claude-opus-4"]
+input = 15
+output = 75
+cache_write = 18.75
+cache_read = 1.5
+
+[keys]
+down = ["j"]
+up = ["k"]
+
+[themes.mine]
+base = "light"
+accent = "#d75f00"
+
+[renderers."mcp__github__*"]
+input = "{{.owner}}/{{.repo}}"
+
+[[view]]
+name = "failing Bash calls"
+mode = "messages"
+sort = "time"
+filter = "tool:Bash error:true"
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      interface{}
+		expected interface{}
+	}{
+		{"DefaultView", cfg.UI.DefaultView, "projects"},
+		{"RefreshInterval", cfg.UI.RefreshInterval, 2 * time.Second},
+		{"ProcessColumns", strings.Join(cfg.Columns.Processes, ","), "pid,cpu,workdir"},
+		{"CPUWarning", cfg.Thresholds.CPUWarning, 30.0},
+		{"CPUCritical", cfg.Thresholds.CPUCritical, 80.0}, // Default kept
+		{"OpusOutput", cfg.PricingOverrides()["claude-opus-4"].Output, 75.0},
+		{"DownKey", strings.Join(cfg.Keys["down"], ","), "j"},
+		{"ThemeAccent", cfg.Themes["mine"]["accent"], "#d75f00"},
+		{"RendererInput", cfg.Renderers["mcp__github__*"].Input, "{{.owner}}/{{.repo}}"},
+		{"ViewNames", strings.Join(cfg.ViewNames(), ","), "failing Bash calls"},
+		{"ViewFilter", cfg.SavedViews[0].Filter, "tool:Bash error:true"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.expected)
+		}
+	}
+}
+
+// TestLoadInvalid tests that invalid settings are reported by name
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown key", "[ui]\ndefault_veiw = \"projects\"", "ui.default_veiw: unknown setting"},
+		{"unknown view", "[ui]\ndefault_view = \"files\"", "ui.default_view: unknown view"},
+		{"short interval", "[ui]\nrefresh_interval = \"10ms\"", "ui.refresh_interval"},
+		{"unknown column", "[columns]\nsessions = [\"cost\"]", "columns.sessions: unknown column \"cost\""},
+		{"duplicate column", "[columns]\nprojects = [\"name\", \"name\"]", "listed twice"},
+		{"bad colour", "[colors]\nwarning = \"orange\"", "colors.warning: invalid colour"},
+		{"inverted thresholds", "[thresholds]\ncpu_warning = 90", "thresholds.cpu_warning (90) is above cpu_critical (80)"},
+		{"negative price", "[pricing.claude]\ninput = -1", "pricing.claude: prices must not be negative"},
+		{"unknown keymap", "[ui]\nkeymap = \"helix\"", "ui.keymap: unknown keymap \"helix\""},
+		{"unknown theme", "[ui]\ntheme = \"solarized\"", "ui.theme: unknown theme \"solarized\""},
+		{"bad theme base", "[themes.mine]\nbase = \"sepia\"", "themes.mine.base: unknown theme \"sepia\""},
+		{"unknown theme role", "[themes.mine]\nheading = \"1\"", "themes.mine.heading: unknown role"},
+		{"bad theme colour", "[themes.mine]\ntext = \"white\"", "themes.mine.text: invalid colour"},
+		{"bad renderer glob", "[renderers.\"mcp[\"]\ninput = \"x\"", "renderers.mcp[: invalid glob"},
+		{"empty renderer", "[renderers.Read]", "renderers.Read: needs an input or result template"},
+		{"bad renderer template", "[renderers.Read]\nresult = \"{{.x\"", "renderers.Read.result:"},
+		{"unknown template function", "[renderers.Read]\ninput = \"{{shout .x}}\"", "function \"shout\" not defined"},
+		{"unknown action", "[keys]\nexplode = [\"x\"]", "keys.explode: unknown action"},
+		{"bad matcher", "[[matcher]]\nname = \"m\"\nexe = \"(\"", "matcher:"},
+		{"root without dir", "[[root]]\nlabel = \"x\"", "root[0]: dir is required"},
+		{"bad auto compact", "[context]\nauto_compact = 1.5", "context.auto_compact: 1.5 is not between 0 and 1"},
+		{"negative health", "[health]\nrepeat_calls = -1", "health: thresholds must not be negative"},
+		{"unknown backend", "[notify]\nbackends = [\"pager\"]", "notify.backends: unknown backend \"pager\""},
+		{"unknown event", "[notify]\nevents = [\"done\"]", "notify.events: unknown event \"done\""},
+		{"webhook without url", "[notify]\nbackends = [\"webhook\"]", "notify.webhook: \"\" is not an http(s) URL"},
+		{"short record interval", "[record]\ninterval = \"100ms\"", "record.interval: 100ms is too short"},
+		{"zero record size", "[record]\nmax_size_mb = 0", "record.max_size_mb: must be positive"},
+		{"view without name", "[[view]]\nmode = \"processes\"", "view[0]: name is required"},
+		{"unknown view mode", "[[view]]\nname = \"x\"\nmode = \"files\"", "view[0].mode: unknown mode \"files\""},
+		{"unknown view sort", "[[view]]\nname = \"x\"\nmode = \"messages\"\nsort = \"-speed\"", "view[0].sort: unknown column \"speed\""},
+		{"unknown view column", "[[view]]\nname = \"x\"\nmode = \"sessions\"\ncolumns = [\"pid\"]", "view[0].columns: unknown column \"pid\""},
+		{"bad view filter", "[[view]]\nname = \"x\"\nmode = \"sessions\"\nfilter = \"role:user\"", "view[0].filter: unknown field \"role\""},
+		{"filter on process view", "[[view]]\nname = \"x\"\nmode = \"processes\"\nfilter = \"cost>1\"", "processes views take no filter"},
+		{"syntax error", "[ui", "cannot parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.content))
+			if err == nil {
+				t.Fatalf("Expected error containing %q", tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Error %q does not contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestSaveColumns tests that saving columns rewrites only that setting
+func TestSaveColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"missing file", "", "[columns]\nsessions = [\"tokens\", \"version\"]\n"},
+		{"no section", "[ui]\nkeymap = \"vim\" # Comment\n",
+			"[ui]\nkeymap = \"vim\" # Comment\n\n[columns]\nsessions = [\"tokens\", \"version\"]\n"},
+		{"replace", "[columns]\nprojects = [\"name\"]\nsessions = [\"commits\"] # Old\n\n[ui]\nkeymap = \"vim\"\n",
+			"[columns]\nprojects = [\"name\"]\nsessions = [\"tokens\", \"version\"]\n\n[ui]\nkeymap = \"vim\"\n"},
+		{"multi-line array", "[columns]\nsessions = [\n  \"commits\",\n]\nprojects = [\"name\"]\n",
+			"[columns]\nsessions = [\"tokens\", \"version\"]\nprojects = [\"name\"]\n"},
+		{"add to section", "[columns]\nprojects = [\"name\"]\n\n[[view]]\nname = \"x\"\nmode = \"projects\"\n",
+			"[columns]\nprojects = [\"name\"]\nsessions = [\"tokens\", \"version\"]\n\n[[view]]\nname = \"x\"\nmode = \"projects\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "promptwatch", "config.toml")
+			if tt.content != "" {
+				os.MkdirAll(filepath.Dir(path), 0755)
+				os.WriteFile(path, []byte(tt.content), 0600)
+			}
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if err := cfg.SaveColumns("sessions", []string{"tokens", "version"}); err != nil {
+				t.Fatalf("SaveColumns failed: %v", err)
+			}
+			got, _ := os.ReadFile(path)
+			if string(got) != tt.want {
+				t.Errorf("Saved file:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if reloaded, err := Load(path); err != nil || len(reloaded.Columns.Sessions) != 2 {
+				t.Errorf("Reloading the saved file: %v, %v", reloaded, err)
+			}
+		})
+	}
+
+	if err := Default().SaveColumns("sessions", nil); err == nil {
+		t.Error("Saved a config that was not loaded from a file")
+	}
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tableHeader matches a TOML table or array-of-tables header line
+var tableHeader = regexp.MustCompile(`^\s*\[`)
+
+// SaveColumns sets the visible columns of a table ("processes", "projects" or
+// "sessions") and writes them to the [columns] section of the config file.
+// Only that one setting is rewritten; comments and other settings are kept.
+func (c *Config) SaveColumns(table string, keys []string) error {
+	known, ok := map[string][]string{
+		"processes": ProcessColumns,
+		"projects":  ProjectColumns,
+		"sessions":  SessionColumns,
+	}[table]
+	if !ok {
+		return fmt.Errorf("unknown column table %q", table)
+	}
+	for _, key := range keys {
+		if !slices.Contains(known, key) {
+			return fmt.Errorf("columns.%s: unknown column %q", table, key)
+		}
+	}
+	if c.path == "" {
+		return errors.New("no config file to save to")
+	}
+
+	content, err := os.ReadFile(c.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	updated := setColumnsLine(string(content), table, keys)
+
+	// Never write a file the next start would reject
+	var check Config
+	if _, err := toml.Decode(updated, &check); err != nil {
+		return fmt.Errorf("cannot update %s: %w", c.path, err)
+	}
+	if err := writeFileAtomic(c.path, []byte(updated)); err != nil {
+		return err
+	}
+
+	switch table {
+	case "processes":
+		c.Columns.Processes = keys
+	case "projects":
+		c.Columns.Projects = keys
+	case "sessions":
+		c.Columns.Sessions = keys
+	}
+	return nil
+}
+
+// setColumnsLine replaces or adds the "table = [...]" line of the [columns]
+// section, adding the section when the file has none
+func setColumnsLine(content, table string, keys []string) string {
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = strconv.Quote(key)
+	}
+	line := fmt.Sprintf("%s = [%s]", table, strings.Join(quoted, ", "))
+
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+	setting := regexp.MustCompile(`^\s*` + table + `\s*=`)
+
+	section := -1
+	for i, l := range lines {
+		if strings.HasPrefix(strings.TrimSpace(l), "[columns]") {
+			section = i
+			break
+		}
+	}
+	if section < 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "[columns]", line)
+		return strings.Join(lines, "\n") + "\n"
+	}
+
+	// The section ends at the next table header; new settings go after its
+	// last non-blank line
+	end, last := len(lines), section
+	for i := section + 1; i < len(lines); i++ {
+		if tableHeader.MatchString(lines[i]) {
+			end = i
+			break
+		}
+		if strings.TrimSpace(lines[i]) != "" {
+			last = i
+		}
+		if !setting.MatchString(lines[i]) {
+			continue
+		}
+		// An array may continue over several lines
+		stop := i
+		for stop < end-1 && !strings.Contains(stripComment(lines[stop]), "]") {
+			stop++
+		}
+		lines = append(lines[:i], append([]string{line}, lines[stop+1:]...)...)
+		return strings.Join(lines, "\n") + "\n"
+	}
+	lines = append(lines[:last+1], append([]string{line}, lines[last+1:]...)...)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// stripComment drops a trailing # comment outside of quotes
+func stripComment(line string) string {
+	inString := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			inString = !inString
+		case r == '#' && !inString:
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// writeFileAtomic replaces path with data through a temporary file, keeping
+// the file's permissions and creating its directory when needed
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*.toml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,233 @@
+// Package diff computes line diffs and formats them as unified diffs
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the kind of change a diff line represents
+type Op int
+
+const (
+	Equal  Op = iota // Line is in both texts
+	Delete           // Line is only in the old text
+	Insert           // Line is only in the new text
+)
+
+// Line is one line of an edit script
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Hunk is a run of changes with surrounding context, using 1-based line numbers
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Lines              []Line
+}
+
+// Header returns the unified diff hunk header, e.g. "@@ -1,3 +1,4 @@"
+func (h Hunk) Header() string {
+	oldStart, newStart := h.OldStart, h.NewStart
+	// An empty range starts at the line before it
+	if h.OldLines == 0 {
+		oldStart--
+	}
+	if h.NewLines == 0 {
+		newStart--
+	}
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, h.OldLines, newStart, h.NewLines)
+}
+
+// SplitLines splits text into lines, ignoring a trailing newline
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// Lines returns the shortest edit script turning a into b (Myers' algorithm)
+func Lines(a, b []string) []Line {
+	// Common prefix and suffix need no search
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var lines []Line
+	for _, text := range a[:prefix] {
+		lines = append(lines, Line{Equal, text})
+	}
+	lines = append(lines, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, text := range a[len(a)-suffix:] {
+		lines = append(lines, Line{Equal, text})
+	}
+	return lines
+}
+
+// myers finds the edit script by searching furthest-reaching paths per edit distance
+func myers(a, b []string) []Line {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] holds the frontier before step d, for k in [-d-1, d+1]
+	var trace [][]int
+
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // Step down: insert
+			} else {
+				x = v[offset+k-1] + 1 // Step right: delete
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack walks the recorded frontiers from the end to recover the edit script
+func backtrack(a, b []string, trace [][]int) []Line {
+	var lines []Line
+	x, y := len(a), len(b)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		frontier := trace[d]
+		at := func(k int) int { return frontier[k+d+1] }
+
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			lines = append(lines, Line{Equal, a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				lines = append(lines, Line{Insert, b[y-1]})
+			} else {
+				lines = append(lines, Line{Delete, a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+// Hunks groups an edit script into hunks with up to context unchanged lines around each change
+func Hunks(lines []Line, context int) []Hunk {
+	// Line numbers in the old and new text at each position of the script
+	oldPos := make([]int, len(lines)+1)
+	newPos := make([]int, len(lines)+1)
+	oldPos[0], newPos[0] = 1, 1
+	for i, l := range lines {
+		oldPos[i+1], newPos[i+1] = oldPos[i], newPos[i]
+		if l.Op != Insert {
+			oldPos[i+1]++
+		}
+		if l.Op != Delete {
+			newPos[i+1]++
+		}
+	}
+
+	var hunks []Hunk
+	for i := 0; i < len(lines); {
+		if lines[i].Op == Equal {
+			i++
+			continue
+		}
+
+		// Extend over changes separated by at most 2*context unchanged lines
+		end := i
+		for j := i; j < len(lines); {
+			if lines[j].Op != Equal {
+				j++
+				end = j
+				continue
+			}
+			run := j
+			for run < len(lines) && lines[run].Op == Equal {
+				run++
+			}
+			if run == len(lines) || run-j > 2*context {
+				break
+			}
+			j = run
+		}
+
+		start := max(0, i-context)
+		stop := min(len(lines), end+context)
+		hunk := Hunk{OldStart: oldPos[start], NewStart: newPos[start], Lines: lines[start:stop]}
+		for _, l := range hunk.Lines {
+			if l.Op != Insert {
+				hunk.OldLines++
+			}
+			if l.Op != Delete {
+				hunk.NewLines++
+			}
+		}
+		hunks = append(hunks, hunk)
+		i = stop
+	}
+	return hunks
+}
+
+// Prefix returns the unified diff marker of an operation
+func (op Op) Prefix() string {
+	switch op {
+	case Delete:
+		return "-"
+	case Insert:
+		return "+"
+	}
+	return " "
+}
+
+// Unified formats a unified diff of two texts, or returns "" when they are equal
+func Unified(oldName, newName, a, b string, context int) string {
+	hunks := Hunks(Lines(SplitLines(a), SplitLines(b)), context)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hunks {
+		sb.WriteString(h.Header() + "\n")
+		for _, l := range h.Lines {
+			sb.WriteString(l.Op.Prefix() + l.Text + "\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,91 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLines tests that edit scripts are minimal and reproduce both texts
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		changes int
+	}{
+		{"equal", "a\nb\nc", "a\nb\nc", 0},
+		{"both empty", "", "", 0},
+		{"insert into empty", "", "a\nb", 2},
+		{"delete all", "a\nb", "", 2},
+		{"replace middle", "a\nb\nc", "a\nx\nc", 2},
+		{"insert middle", "a\nc", "a\nb\nc", 1},
+		{"interleaved", "a\nb\nc\na\nb\nb\na", "c\nb\na\nb\na\nc", 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := Lines(SplitLines(tt.a), SplitLines(tt.b))
+
+			var oldText, newText []string
+			changes := 0
+			for _, l := range lines {
+				if l.Op != Insert {
+					oldText = append(oldText, l.Text)
+				}
+				if l.Op != Delete {
+					newText = append(newText, l.Text)
+				}
+				if l.Op != Equal {
+					changes++
+				}
+			}
+
+			if got := strings.Join(oldText, "\n"); got != tt.a {
+				t.Errorf("Old text: got %q, want %q", got, tt.a)
+			}
+			if got := strings.Join(newText, "\n"); got != tt.b {
+				t.Errorf("New text: got %q, want %q", got, tt.b)
+			}
+			if changes != tt.changes {
+				t.Errorf("Changes: got %d, want %d", changes, tt.changes)
+			}
+		})
+	}
+}
+
+// TestUnified tests hunk grouping and headers
+func TestUnified(t *testing.T) {
+	var oldLines, newLines []string
+	for i := 1; i <= 20; i++ {
+		line := string(rune('a' + i - 1))
+		oldLines = append(oldLines, line)
+		switch i {
+		case 2:
+			newLines = append(newLines, "B")
+		case 18:
+			// Deleted
+		default:
+			newLines = append(newLines, line)
+		}
+	}
+
+	got := Unified("old.txt", "new.txt", strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"), 1)
+	want := `--- old.txt
++++ new.txt
+@@ -1,3 +1,3 @@
+ a
+-b
++B
+ c
+@@ -17,3 +17,2 @@
+ q
+-r
+ s
+`
+	if got != want {
+		t.Errorf("Unified:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := Unified("a", "b", "same\n", "same", 3); got != "" {
+		t.Errorf("Expected no diff for equal texts, got %q", got)
+	}
+}
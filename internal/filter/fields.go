@@ -0,0 +1,252 @@
+package filter
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// MessageFields are the fields of message filters
+var MessageFields = Fields{
+	"role":    {Text, "user (prompts), assistant (responses and tool calls) or tool (tool results)"},
+	"type":    {Text, "prompt, assistant_response or tool_result"},
+	"tool":    {Name, "tool called, or whose result this is; globs such as mcp__github__*"},
+	"model":   {Text, "model of the response or tool call"},
+	"text":    {Text, "message content; bare words search it too"},
+	"cost":    {Number, "USD"},
+	"tokens":  {Number, "input, output and cache tokens"},
+	"error":   {Bool, "the tool result failed, or the call's result did"},
+	"branch":  {Text, "git branch"},
+	"version": {Text, "Claude version"},
+	"after":   {After, "sent at or after a date (2026-10-01), a relative time (7d, 12h), today or yesterday"},
+	"before":  {Before, "sent before a date or relative time"},
+}
+
+// SessionFields are the fields of session filters
+var SessionFields = Fields{
+	"project":   {Text, "working directory"},
+	"branch":    {Text, "git branch"},
+	"version":   {Text, "Claude version"},
+	"model":     {Text, "any model used"},
+	"tool":      {Name, "any tool called; globs such as mcp__*"},
+	"text":      {Text, "any user prompt; bare words search it too"},
+	"cost":      {Number, "USD"},
+	"tokens":    {Number, "input, output and cache tokens"},
+	"prompts":   {Number, "user prompts"},
+	"messages":  {Number, "messages"},
+	"errors":    {Number, "failed tool results"},
+	"duration":  {Duration, "time from first to last message (30m, 2h, 1d)"},
+	"sidechain": {Bool, "side-chain conversation"},
+	"after":     {After, "started at or after a date (2026-10-01), a relative time (7d, 12h), today or yesterday"},
+	"before":    {Before, "started before a date or relative time"},
+}
+
+// ParseMessages parses a message filter
+func ParseMessages(src string) (*Expr, error) {
+	return Parse(src, MessageFields)
+}
+
+// ParseSessions parses a session filter
+func ParseSessions(src string) (*Expr, error) {
+	return Parse(src, SessionFields)
+}
+
+// messageRecord exposes a message to message filters. Tool calls and their
+// results share the tool name, model and error state.
+type messageRecord struct {
+	msg  monitor.Message
+	call *monitor.Message // Call of a tool result, or result of a call
+}
+
+func (r messageRecord) Value(field string) any {
+	m := r.msg
+	switch field {
+	case "role":
+		switch m.Type {
+		case "prompt":
+			return "user"
+		case "tool_result":
+			return "tool"
+		}
+		return "assistant"
+	case "type":
+		return m.Type
+	case "tool":
+		if m.Type == "tool_result" && r.call != nil {
+			return r.call.ToolName
+		}
+		return m.ToolName
+	case "model":
+		if m.Model == "" && r.call != nil {
+			return r.call.Model
+		}
+		return m.Model
+	case "text":
+		return m.Content
+	case "cost":
+		if m.Type != "assistant_response" {
+			return 0.0
+		}
+		cost, _ := monitor.MessageCost(m)
+		return cost
+	case "tokens":
+		return m.InputTokens + m.OutputTokens + m.CacheCreation + m.CacheRead
+	case "error":
+		if m.ToolName != "" && r.call != nil {
+			return r.call.IsError
+		}
+		return m.IsError
+	case "branch":
+		return m.GitBranch
+	case "version":
+		return m.Version
+	case "time":
+		return m.Timestamp
+	}
+	return nil
+}
+
+// Messages returns the messages that match, in their order
+func (e *Expr) Messages(msgs []monitor.Message) []monitor.Message {
+	if e.IsEmpty() {
+		return slices.Clone(msgs)
+	}
+
+	// Pair tool calls with their results in both directions
+	calls := make(map[string]*monitor.Message)
+	results := make(map[string]*monitor.Message)
+	for i := range msgs {
+		msg := &msgs[i]
+		if msg.ToolUseID == "" {
+			continue
+		}
+		if msg.ToolName != "" {
+			calls[msg.ToolUseID] = msg
+		} else if msg.Type == "tool_result" {
+			results[msg.ToolUseID] = msg
+		}
+	}
+
+	var matched []monitor.Message
+	for _, msg := range msgs {
+		r := messageRecord{msg: msg}
+		if msg.ToolName != "" {
+			r.call = results[msg.ToolUseID]
+		} else if msg.Type == "tool_result" {
+			r.call = calls[msg.ToolUseID]
+		}
+		if e.Match(r) {
+			matched = append(matched, msg)
+		}
+	}
+	return matched
+}
+
+// Session holds the values session filters test
+type Session struct {
+	Project   string
+	Branch    string
+	Version   string
+	Models    []string
+	Tools     []string
+	Prompts   []string
+	Cost      float64
+	Tokens    int
+	Messages  int
+	Errors    int
+	Sidechain bool
+	Started   time.Time
+	Ended     time.Time
+}
+
+// Value exposes the session to session filters
+func (s Session) Value(field string) any {
+	switch field {
+	case "project":
+		return s.Project
+	case "branch":
+		return s.Branch
+	case "version":
+		return s.Version
+	case "model":
+		return s.Models
+	case "tool":
+		return s.Tools
+	case "text":
+		return s.Prompts
+	case "cost":
+		return s.Cost
+	case "tokens":
+		return s.Tokens
+	case "prompts":
+		return len(s.Prompts)
+	case "messages":
+		return s.Messages
+	case "errors":
+		return s.Errors
+	case "duration":
+		return s.Ended.Sub(s.Started)
+	case "sidechain":
+		return s.Sidechain
+	case "time":
+		return s.Started
+	}
+	return nil
+}
+
+// SessionFromStats collects the filterable values of a parsed session
+func SessionFromStats(stats *monitor.SessionStats) Session {
+	s := Session{
+		Version:  stats.ClaudeVersion,
+		Messages: len(stats.MessageHistory),
+		Started:  stats.CreatedAt,
+		Ended:    stats.LastActivity,
+	}
+	models := make(map[string]bool)
+	tools := make(map[string]bool)
+	seenUsage := make(monitor.UsageSeen)
+	for _, msg := range stats.MessageHistory {
+		if s.Project == "" {
+			s.Project = msg.WorkingDir
+		}
+		if s.Branch == "" {
+			s.Branch = msg.GitBranch
+		}
+		s.Sidechain = s.Sidechain || msg.IsSidechain
+		switch {
+		case msg.Type == "prompt":
+			s.Prompts = append(s.Prompts, msg.Content)
+		case msg.Type == "tool_result" && msg.IsError:
+			s.Errors++
+		}
+		if msg.Model != "" {
+			models[msg.Model] = true
+		}
+		if msg.ToolName != "" {
+			tools[msg.ToolName] = true
+		}
+		if !seenUsage.First(msg) {
+			continue
+		}
+		if msg.Type == "assistant_response" {
+			cost, _ := monitor.MessageCost(msg)
+			s.Cost += cost
+		}
+		s.Tokens += msg.InputTokens + msg.OutputTokens + msg.CacheCreation + msg.CacheRead
+	}
+	s.Models = sortedKeys(models)
+	s.Tools = sortedKeys(tools)
+	return s
+}
+
+// sortedKeys returns the keys of a set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,576 @@
+// Package filter parses and evaluates filter expressions such as
+//
+//	role:assistant tool:Bash cost>0.05 after:2026-10-01 text~"migration"
+//
+// Terms are ANDed; OR, parentheses and a leading - (or NOT) combine them. A
+// term is either field, operator and value, or a bare word searched for in
+// the text. Which fields exist depends on what is filtered (see Fields).
+package filter
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Kind is the type of a field's values, which decides its operators
+type Kind int
+
+const (
+	Text     Kind = iota // Strings: ":" contains, "=" and "!=" equal, "~" regexp, all ignoring case
+	Name                 // Like Text, but ":" matches a glob such as mcp__github__*
+	Number               // ":" or "=", "!=", ">", ">=", "<", "<="
+	Duration             // Like Number with values such as 90s, 30m, 2h or 1d
+	Bool                 // ":" with true/false or yes/no
+	After                // ":" with a date or a relative time; matches at or after it
+	Before               // Like After; matches before it
+)
+
+// Field describes a field a filter can test
+type Field struct {
+	Kind Kind
+	Help string
+}
+
+// Fields maps field names to their description
+type Fields map[string]Field
+
+// Names returns the field names in alphabetical order
+func (f Fields) Names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Record is a filtered item. Value returns a field's value: a string or
+// []string (any element may match), float64, int, time.Duration, bool or
+// time.Time. After and Before fields read the value "time", and bare words
+// the value "text".
+type Record interface {
+	Value(field string) any
+}
+
+// Expr is a parsed filter expression. The zero value and nil match everything.
+type Expr struct {
+	src  string
+	root node
+}
+
+// node is a part of the expression tree
+type node interface {
+	match(r Record) bool
+}
+
+type andNode []node
+type orNode []node
+type notNode struct{ n node }
+type testNode struct {
+	field string
+	test  func(v any) bool
+}
+
+func (a andNode) match(r Record) bool {
+	for _, n := range a {
+		if !n.match(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (o orNode) match(r Record) bool {
+	for _, n := range o {
+		if n.match(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n notNode) match(r Record) bool { return !n.n.match(r) }
+
+func (t testNode) match(r Record) bool { return t.test(r.Value(t.field)) }
+
+// Parse parses an expression against the given fields. An empty
+// expression matches everything.
+func Parse(src string, fields Fields) (*Expr, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, fields: fields}
+	expr := &Expr{src: strings.TrimSpace(src)}
+	if len(tokens) == 0 {
+		return expr, nil
+	}
+	if expr.root, err = p.or(); err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+// String returns the expression as it was written
+func (e *Expr) String() string {
+	if e == nil {
+		return ""
+	}
+	return e.src
+}
+
+// IsEmpty reports whether the expression matches everything
+func (e *Expr) IsEmpty() bool {
+	return e == nil || e.root == nil
+}
+
+// Match reports whether a record passes the filter
+func (e *Expr) Match(r Record) bool {
+	return e.IsEmpty() || e.root.match(r)
+}
+
+// tokenKind identifies lexer tokens
+type tokenKind int
+
+const (
+	tokWord  tokenKind = iota // Bare word or quoted string
+	tokTerm                   // field, operator and value
+	tokOpen                   // (
+	tokClose                  // )
+	tokNot                    // - or NOT
+	tokOr                     // OR
+)
+
+// token is a lexed part of an expression
+type token struct {
+	kind   tokenKind
+	text   string // As written, for error messages
+	field  string
+	op     string
+	value  string
+	quoted bool // The word or value was in quotes
+}
+
+// operators lists the comparison operators, longest first
+var operators = []string{">=", "<=", "!=", ":", "~", "=", ">", "<"}
+
+// lex splits an expression into tokens
+func lex(src string) ([]token, error) {
+	var tokens []token
+	s := []rune(src)
+	i := 0
+	for i < len(s) {
+		switch {
+		case unicode.IsSpace(s[i]):
+			i++
+		case s[i] == '(':
+			tokens = append(tokens, token{kind: tokOpen, text: "("})
+			i++
+		case s[i] == ')':
+			tokens = append(tokens, token{kind: tokClose, text: ")"})
+			i++
+		case s[i] == '-' && i+1 < len(s) && !unicode.IsSpace(s[i+1]):
+			tokens = append(tokens, token{kind: tokNot, text: "-"})
+			i++
+		case s[i] == '"':
+			word, next, err := lexQuoted(s, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokWord, text: string(s[i:next]), value: word, quoted: true})
+			i = next
+		default:
+			start := i
+			for i < len(s) && (unicode.IsLetter(s[i]) || s[i] == '_') {
+				i++
+			}
+			if op := operatorAt(s, i); i > start && op != "" {
+				field := strings.ToLower(string(s[start:i]))
+				i += len(op)
+				tok := token{kind: tokTerm, field: field, op: op}
+				if i < len(s) && s[i] == '"' {
+					value, next, err := lexQuoted(s, i)
+					if err != nil {
+						return nil, err
+					}
+					tok.value, tok.quoted, i = value, true, next
+				} else {
+					valueStart := i
+					for i < len(s) && !unicode.IsSpace(s[i]) && s[i] != ')' {
+						i++
+					}
+					tok.value = string(s[valueStart:i])
+				}
+				tok.text = string(s[start:i])
+				tokens = append(tokens, tok)
+				continue
+			}
+
+			i = start
+			for i < len(s) && !unicode.IsSpace(s[i]) && s[i] != '(' && s[i] != ')' {
+				i++
+			}
+			word := string(s[start:i])
+			switch word {
+			case "OR":
+				tokens = append(tokens, token{kind: tokOr, text: word})
+			case "NOT":
+				tokens = append(tokens, token{kind: tokNot, text: word})
+			case "AND":
+				// Terms are ANDed anyway
+			default:
+				tokens = append(tokens, token{kind: tokWord, text: word, value: word})
+			}
+		}
+	}
+	return tokens, nil
+}
+
+// operatorAt returns the operator starting at s[i], if any
+func operatorAt(s []rune, i int) string {
+	for _, op := range operators {
+		if strings.HasPrefix(string(s[i:min(i+2, len(s))]), op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// lexQuoted reads the quoted string starting at s[i], where \" and \\ are
+// escapes, and returns it with the index after the closing quote
+func lexQuoted(s []rune, i int) (string, int, error) {
+	var b strings.Builder
+	for j := i + 1; j < len(s); j++ {
+		switch {
+		case s[j] == '\\' && j+1 < len(s) && (s[j+1] == '"' || s[j+1] == '\\'):
+			j++
+			b.WriteRune(s[j])
+		case s[j] == '"':
+			return b.String(), j + 1, nil
+		default:
+			b.WriteRune(s[j])
+		}
+	}
+	return "", 0, fmt.Errorf("missing closing quote after %s", string(s[i:]))
+}
+
+// parser builds the expression tree from tokens:
+//
+//	or   = and { "OR" and }
+//	and  = unary { unary }
+//	unary = ( "-" | "NOT" ) unary | "(" or ")" | term | word
+type parser struct {
+	tokens []token
+	pos    int
+	fields Fields
+}
+
+// now is the clock relative times count back from when an expression is
+// matched, so a filter kept open stays relative; tests replace it
+var now = time.Now
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) or() (node, error) {
+	var alternatives orNode
+	for {
+		n, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, n)
+		if tok, ok := p.peek(); !ok || tok.kind != tokOr {
+			break
+		}
+		p.pos++
+	}
+	if len(alternatives) == 1 {
+		return alternatives[0], nil
+	}
+	return alternatives, nil
+}
+
+func (p *parser) and() (node, error) {
+	var terms andNode
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind == tokOr || tok.kind == tokClose {
+			break
+		}
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, n)
+	}
+	switch len(terms) {
+	case 0:
+		if tok, ok := p.peek(); ok {
+			return nil, fmt.Errorf("expected a term before %q", tok.text)
+		}
+		return nil, fmt.Errorf("expected a term after OR")
+	case 1:
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *parser) unary() (node, error) {
+	tok, _ := p.peek()
+	p.pos++
+	switch tok.kind {
+	case tokNot:
+		if _, ok := p.peek(); !ok {
+			return nil, fmt.Errorf("expected a term after %q", tok.text)
+		}
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{n}, nil
+	case tokOpen:
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if tok, ok := p.peek(); !ok || tok.kind != tokClose {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return n, nil
+	case tokClose:
+		return nil, fmt.Errorf("unexpected )")
+	case tokWord:
+		if _, ok := p.fields["text"]; !ok {
+			return nil, fmt.Errorf("%q: free text is not searchable here; use field:value", tok.text)
+		}
+		return p.term(token{field: "text", op: ":", value: tok.value, quoted: true, text: tok.text})
+	}
+	return p.term(tok)
+}
+
+// term compiles a field comparison
+func (p *parser) term(tok token) (node, error) {
+	field, ok := p.fields[tok.field]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q (fields: %s)", tok.field, strings.Join(p.fields.Names(), ", "))
+	}
+	if tok.value == "" && !tok.quoted {
+		return nil, fmt.Errorf("%s: missing value", tok.text)
+	}
+
+	// An unquoted a,b,c matches any of the values
+	values := []string{tok.value}
+	if !tok.quoted && tok.op != "~" && strings.Contains(tok.value, ",") {
+		values = strings.Split(tok.value, ",")
+	}
+	var alternatives orNode
+	for _, value := range values {
+		test, err := p.compile(tok.field, field.Kind, tok.op, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", tok.text, err)
+		}
+		key := tok.field
+		if field.Kind == After || field.Kind == Before {
+			key = "time"
+		}
+		alternatives = append(alternatives, testNode{field: key, test: test})
+	}
+	if len(alternatives) == 1 {
+		return alternatives[0], nil
+	}
+	return alternatives, nil
+}
+
+// compile returns the test of one field comparison
+func (p *parser) compile(name string, kind Kind, op, value string) (func(any) bool, error) {
+	badOp := fmt.Errorf("operator %s does not apply to %s", op, name)
+	switch kind {
+	case Text, Name:
+		lower := strings.ToLower(value)
+		var match func(s string) bool
+		switch op {
+		case ":":
+			match = func(s string) bool { return strings.Contains(strings.ToLower(s), lower) }
+			if kind == Name {
+				if _, err := path.Match(lower, ""); err != nil {
+					return nil, fmt.Errorf("invalid pattern %q", value)
+				}
+				match = func(s string) bool {
+					ok, _ := path.Match(lower, strings.ToLower(s))
+					return ok
+				}
+			}
+		case "=":
+			match = func(s string) bool { return strings.EqualFold(s, value) }
+		case "!=":
+			return func(v any) bool { return !anyString(v, func(s string) bool { return strings.EqualFold(s, value) }) }, nil
+		case "~":
+			re, err := regexp.Compile("(?i)" + value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression: %w", err)
+			}
+			match = re.MatchString
+		default:
+			return nil, badOp
+		}
+		return func(v any) bool { return anyString(v, match) }, nil
+
+	case Number, Duration:
+		var want float64
+		if kind == Duration {
+			d, err := parseDuration(value)
+			if err != nil {
+				return nil, err
+			}
+			want = float64(d)
+		} else {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a number", value)
+			}
+			want = n
+		}
+		var cmp func(a float64) bool
+		switch op {
+		case ":", "=":
+			cmp = func(a float64) bool { return a == want }
+		case "!=":
+			cmp = func(a float64) bool { return a != want }
+		case ">":
+			cmp = func(a float64) bool { return a > want }
+		case ">=":
+			cmp = func(a float64) bool { return a >= want }
+		case "<":
+			cmp = func(a float64) bool { return a < want }
+		case "<=":
+			cmp = func(a float64) bool { return a <= want }
+		default:
+			return nil, badOp
+		}
+		return func(v any) bool {
+			n, ok := number(v)
+			return ok && cmp(n)
+		}, nil
+
+	case Bool:
+		if op != ":" && op != "=" {
+			return nil, badOp
+		}
+		var want bool
+		switch strings.ToLower(value) {
+		case "true", "yes", "1":
+			want = true
+		case "false", "no", "0":
+		default:
+			return nil, fmt.Errorf("%q is not true or false", value)
+		}
+		return func(v any) bool {
+			b, ok := v.(bool)
+			return ok && b == want
+		}, nil
+
+	case After, Before:
+		if op != ":" {
+			return nil, badOp
+		}
+		bound, err := parseTime(value)
+		if err != nil {
+			return nil, err
+		}
+		return func(v any) bool {
+			at, ok := v.(time.Time)
+			if !ok || at.IsZero() {
+				return false
+			}
+			t := bound(now())
+			if kind == After {
+				return !at.Before(t)
+			}
+			return at.Before(t)
+		}, nil
+	}
+	return nil, badOp
+}
+
+// anyString reports whether a string value, or any element of a list, matches
+func anyString(v any, match func(string) bool) bool {
+	switch s := v.(type) {
+	case string:
+		return match(s)
+	case []string:
+		for _, item := range s {
+			if match(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// number converts a numeric value to float64
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case time.Duration:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// parseDuration parses a Go duration, also accepting days such as 2d
+func parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.ParseFloat(days, 64); err == nil {
+			return time.Duration(n * float64(24*time.Hour)), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a duration such as 30m, 2h or 1d", s)
+	}
+	return d, nil
+}
+
+// timeBound returns the instant a date or relative time stands for at now
+type timeBound func(now time.Time) time.Time
+
+// parseTime parses an absolute date (YYYY-MM-DD, optionally with THH:MM), a
+// relative time counted back from now (7d, 12h) or today/yesterday
+func parseTime(s string) (timeBound, error) {
+	midnight := func(now time.Time, days int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day()+days, 0, 0, 0, 0, now.Location())
+	}
+	switch strings.ToLower(s) {
+	case "today":
+		return func(now time.Time) time.Time { return midnight(now, 0) }, nil
+	case "yesterday":
+		return func(now time.Time) time.Time { return midnight(now, -1) }, nil
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04", "2006-01-02T15:04:05"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return func(time.Time) time.Time { return t }, nil
+		}
+	}
+	if d, err := parseDuration(s); err == nil {
+		return func(now time.Time) time.Time { return now.Add(-d) }, nil
+	}
+	return nil, fmt.Errorf("%q is not a date (YYYY-MM-DD), a relative time (7d, 12h), today or yesterday", s)
+}
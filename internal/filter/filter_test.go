@@ -0,0 +1,177 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// testMessages is a prompt, a Bash call that failed and an Edit call
+func testMessages() []monitor.Message {
+	at := func(h int) time.Time { return time.Date(2026, 10, 2, h, 0, 0, 0, time.Local) }
+	return []monitor.Message{
+		{Type: "prompt", Role: "user", Content: "Write the migration", Timestamp: at(9)},
+		{Type: "assistant_response", Role: "assistant", Content: "Running it", ToolName: "Bash", ToolUseID: "t1",
+			Model: "claude-opus-4", InputTokens: 1000, OutputTokens: 5000, Timestamp: at(10)},
+		{Type: "tool_result", Role: "user", Content: "exit 1", ToolUseID: "t1", IsError: true, Timestamp: at(10)},
+		{Type: "assistant_response", Role: "assistant", Content: "Fixing", ToolName: "Edit", ToolUseID: "t2",
+			Model: "claude-sonnet-4", OutputTokens: 10, Timestamp: at(11)},
+		{Type: "tool_result", Role: "user", Content: "ok", ToolUseID: "t2", Timestamp: at(11)},
+	}
+}
+
+// TestMessageFilters tests fields, operators and combinators on messages
+func TestMessageFilters(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string // Contents of the matching messages
+	}{
+		{"", []string{"Write the migration", "Running it", "exit 1", "Fixing", "ok"}},
+		{"role:user", []string{"Write the migration"}},
+		{"role:assistant,tool", []string{"Running it", "exit 1", "Fixing", "ok"}},
+		{"tool:Bash", []string{"Running it", "exit 1"}},
+		{"tool:bash error:true", []string{"Running it", "exit 1"}},
+		{"tool:Bash role:tool", []string{"exit 1"}},
+		{"model:opus", []string{"Running it", "exit 1"}},
+		{"cost>0.05", []string{"Running it"}},
+		{"tokens<=10 role:assistant", []string{"Fixing"}},
+		{`text~"migr.tion"`, []string{"Write the migration"}},
+		{"MIGRATION", []string{"Write the migration"}},
+		{`"the migration"`, []string{"Write the migration"}},
+		{"after:2026-10-02T11:00", []string{"Fixing", "ok"}},
+		{"before:2026-10-02T10:00", []string{"Write the migration"}},
+		{"tool:Edit OR role:user", []string{"Write the migration", "Fixing", "ok"}},
+		{"-role:tool -(tool:Edit OR role:user)", []string{"Running it"}},
+		{"NOT error:true AND role:tool", []string{"ok"}},
+		{"tool:Ed*", []string{"Fixing", "ok"}},
+	}
+
+	for _, tt := range tests {
+		expr, err := ParseMessages(tt.expr)
+		if err != nil {
+			t.Errorf("ParseMessages(%q) failed: %v", tt.expr, err)
+			continue
+		}
+		var got []string
+		for _, msg := range expr.Messages(testMessages()) {
+			got = append(got, msg.Content)
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%q matched %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+// TestParseErrors tests that invalid expressions are rejected with a reason
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"cots>1", `unknown field "cots"`},
+		{"cost>much", `"much" is not a number`},
+		{"cost~1", "operator ~ does not apply to cost"},
+		{"after:someday", `"someday" is not a date`},
+		{`text~"("`, "invalid regular expression"},
+		{`text:"open`, "missing closing quote"},
+		{"(role:user", "missing )"},
+		{"role:user OR", "expected a term after OR"},
+		{"error:maybe", `"maybe" is not true or false`},
+		{"tool:", "missing value"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseMessages(tt.expr); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseMessages(%q) error = %v, want %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+// TestSessionFilters tests session values collected from a parsed session
+func TestSessionFilters(t *testing.T) {
+	stats := &monitor.SessionStats{
+		CreatedAt:      time.Date(2026, 10, 2, 9, 0, 0, 0, time.Local),
+		LastActivity:   time.Date(2026, 10, 2, 11, 0, 0, 0, time.Local),
+		MessageHistory: testMessages(),
+	}
+	stats.MessageHistory[0].WorkingDir = "/work/api"
+	session := SessionFromStats(stats)
+
+	for expr, want := range map[string]bool{
+		"project:api":          true,
+		"tool:Bash errors>=1":  true,
+		"model:sonnet":         true,
+		"duration>1h":          true,
+		"duration>3h":          false,
+		"migration":            true,
+		"prompts=1 messages=5": true,
+		"after:2026-10-03":     false,
+	} {
+		parsed, err := ParseSessions(expr)
+		if err != nil {
+			t.Errorf("ParseSessions(%q) failed: %v", expr, err)
+			continue
+		}
+		if got := parsed.Match(session); got != want {
+			t.Errorf("%q matched %v, want %v", expr, got, want)
+		}
+	}
+
+	if _, err := ParseSessions("role:user"); err == nil {
+		t.Error("Message field accepted in a session filter")
+	}
+}
+
+// TestSessionUsageOnce tests that a response written as several entries
+// repeating its usage adds its tokens and cost once
+func TestSessionUsageOnce(t *testing.T) {
+	history := testMessages()[:2]
+	history[1].MessageID = "msg_1"
+	repeat := history[1]
+	repeat.Content, repeat.ToolName = "Running it again", ""
+	session := SessionFromStats(&monitor.SessionStats{MessageHistory: append(history, repeat)})
+
+	if session.Tokens != 6000 {
+		t.Errorf("Tokens = %d, want 6000", session.Tokens)
+	}
+	want, _ := monitor.MessageCost(history[1])
+	if session.Cost != want {
+		t.Errorf("Cost = %v, want %v", session.Cost, want)
+	}
+}
+
+// timeRecord is a record with only a time
+type timeRecord time.Time
+
+func (r timeRecord) Value(field string) any { return time.Time(r) }
+
+// TestRelativeTimes tests that relative times count back from when a filter
+// is matched, not from when it was parsed
+func TestRelativeTimes(t *testing.T) {
+	clock := time.Date(2026, 10, 2, 20, 0, 0, 0, time.Local)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	fields := Fields{"after": {Kind: After}}
+	recent, err := Parse("after:12h", fields)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	today, err := Parse("after:today", fields)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	morning := timeRecord(time.Date(2026, 10, 2, 9, 0, 0, 0, time.Local))
+
+	if !recent.Match(morning) || !today.Match(morning) {
+		t.Errorf("9:00 does not match at 20:00")
+	}
+	clock = clock.Add(6 * time.Hour) // 2:00 the next day
+	if recent.Match(morning) {
+		t.Errorf("after:12h still matches 17 hours later")
+	}
+	if today.Match(morning) {
+		t.Errorf("after:today still matches yesterday after midnight")
+	}
+}
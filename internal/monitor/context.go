@@ -0,0 +1,203 @@
+package monitor
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultContextLimit is the context window of models without an override
+const DefaultContextLimit = 200_000
+
+// extendedContextLimit is assumed once a session uses more than its model's
+// limit, which only happens with the 1M token context beta
+const extendedContextLimit = 1_000_000
+
+// DefaultAutoCompact is the share of the context window at which Claude Code
+// is assumed to compact automatically when a session has not compacted yet
+const DefaultAutoCompact = 0.92
+
+// contextTrend is the number of recent turns the growth per turn is averaged over
+const contextTrend = 10
+
+var (
+	contextMu     sync.RWMutex
+	contextLimits map[string]int
+	autoCompact   = DefaultAutoCompact
+)
+
+// SetAutoCompactThreshold sets the share of the context window used to
+// predict the next auto-compact (see DefaultAutoCompact)
+func SetAutoCompactThreshold(share float64) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	autoCompact = share
+}
+
+// AutoCompactThreshold returns the share of the context window used to
+// predict the next auto-compact
+func AutoCompactThreshold() float64 {
+	contextMu.RLock()
+	defer contextMu.RUnlock()
+	return autoCompact
+}
+
+// SetContextLimits installs per-model context windows keyed by model name prefix
+func SetContextLimits(limits map[string]int) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	contextLimits = limits
+}
+
+// ContextLimitFor returns the context window of model in tokens. The override
+// with the longest matching prefix wins; DefaultContextLimit is used when none matches.
+func ContextLimitFor(model string) int {
+	contextMu.RLock()
+	defer contextMu.RUnlock()
+
+	best := ""
+	limit := DefaultContextLimit
+	for prefix, l := range contextLimits {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best, limit = prefix, l
+		}
+	}
+	return limit
+}
+
+// CompactEvent is a compaction of the conversation
+type CompactEvent struct {
+	Time      time.Time
+	Trigger   string // "auto" or "manual"; empty for older sessions
+	PreTokens int    // Context size before compacting, when recorded
+}
+
+// addCompact records a compaction from a compact entry or a compact_boundary
+// system entry with its compactMetadata
+func (s *SessionStats) addCompact(t time.Time, metadata interface{}) {
+	event := CompactEvent{Time: t}
+	if meta, ok := metadata.(map[string]interface{}); ok {
+		event.Trigger, _ = meta["trigger"].(string)
+		if pre, ok := meta["preTokens"].(float64); ok {
+			event.PreTokens = int(pre)
+		}
+	}
+	s.CompactCount++
+	s.Compacts = append(s.Compacts, event)
+}
+
+// ContextTokens returns the tokens a message's request put in the context window
+func (m Message) ContextTokens() int {
+	return m.InputTokens + m.CacheRead + m.CacheCreation
+}
+
+// ContextSample is the context window use of one assistant turn
+type ContextSample struct {
+	Time   time.Time
+	Tokens int
+	Model  string
+}
+
+// ContextUsage is the context window use of a session over time
+type ContextUsage struct {
+	Limit    int
+	Samples  []ContextSample
+	Compacts []CompactEvent
+}
+
+// SessionContext returns the context window use of each assistant turn of the
+// main conversation. Side-chain turns run in their own context and are skipped.
+func SessionContext(stats *SessionStats) ContextUsage {
+	usage := ContextUsage{Compacts: stats.Compacts}
+	var last Message
+	for _, msg := range stats.MessageHistory {
+		if msg.Role != "assistant" || msg.IsSidechain || msg.ContextTokens() == 0 {
+			continue
+		}
+		// The content blocks of one response are separate entries with the same usage
+		if msg.InputTokens == last.InputTokens && msg.CacheRead == last.CacheRead && msg.CacheCreation == last.CacheCreation {
+			continue
+		}
+		last = msg
+		usage.Samples = append(usage.Samples, ContextSample{Time: msg.Timestamp, Tokens: msg.ContextTokens(), Model: msg.Model})
+	}
+
+	if n := len(usage.Samples); n > 0 {
+		usage.Limit = ContextLimitFor(usage.Samples[n-1].Model)
+	} else {
+		usage.Limit = DefaultContextLimit
+	}
+	for _, sample := range usage.Samples {
+		if sample.Tokens > usage.Limit {
+			usage.Limit = max(usage.Limit, extendedContextLimit)
+		}
+	}
+	return usage
+}
+
+// Current returns the context size of the latest turn
+func (u ContextUsage) Current() int {
+	if len(u.Samples) == 0 {
+		return 0
+	}
+	return u.Samples[len(u.Samples)-1].Tokens
+}
+
+// Percent returns the share of the context window the latest turn used, 0-100
+func (u ContextUsage) Percent() float64 {
+	if u.Limit == 0 {
+		return 0
+	}
+	return float64(u.Current()) * 100 / float64(u.Limit)
+}
+
+// CompactAt returns the context size expected to trigger the next auto-compact:
+// the size before the session's last auto-compact, or AutoCompactThreshold of the limit
+func (u ContextUsage) CompactAt() int {
+	for i := len(u.Compacts) - 1; i >= 0; i-- {
+		if c := u.Compacts[i]; c.Trigger == "auto" && c.PreTokens > 0 {
+			return c.PreTokens
+		}
+	}
+	return int(float64(u.Limit) * AutoCompactThreshold())
+}
+
+// CompactIndexes returns for each compaction the index of the first sample
+// after it (len(Samples) when no turn followed)
+func (u ContextUsage) CompactIndexes() []int {
+	indexes := make([]int, len(u.Compacts))
+	for i, c := range u.Compacts {
+		idx := 0
+		for idx < len(u.Samples) && u.Samples[idx].Time.Before(c.Time) {
+			idx++
+		}
+		indexes[i] = idx
+	}
+	return indexes
+}
+
+// TurnsUntilCompact predicts how many more turns fit before the next
+// auto-compact from the average growth of the recent turns since the last
+// compaction. It returns -1 when the context is not growing or there are too
+// few turns to tell.
+func (u ContextUsage) TurnsUntilCompact() int {
+	start := 0
+	if indexes := u.CompactIndexes(); len(indexes) > 0 {
+		start = indexes[len(indexes)-1]
+	}
+	start = max(start, len(u.Samples)-contextTrend)
+	recent := u.Samples[min(start, len(u.Samples)):]
+	if len(recent) < 2 {
+		return -1
+	}
+
+	growth := float64(recent[len(recent)-1].Tokens-recent[0].Tokens) / float64(len(recent)-1)
+	if growth <= 0 {
+		return -1
+	}
+	left := u.CompactAt() - u.Current()
+	if left <= 0 {
+		return 0
+	}
+	return int((float64(left) + growth - 1) / growth)
+}
@@ -0,0 +1,77 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSessionContext tests context samples, compact placement and the auto-compact prediction
+func TestSessionContext(t *testing.T) {
+	dir := t.TempDir()
+	sessionFile := filepath.Join(dir, "context.jsonl")
+
+	data := `{"type":"assistant","timestamp":"2026-01-09T14:00:00.000Z","message":{"role":"assistant","model":"claude-opus-4","content":[{"type":"text","text":"a"}],"usage":{"input_tokens":10,"cache_read_input_tokens":60000,"cache_creation_input_tokens":0,"output_tokens":5}}}
+{"type":"assistant","timestamp":"2026-01-09T14:00:01.000Z","message":{"role":"assistant","model":"claude-opus-4","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{}}],"usage":{"input_tokens":10,"cache_read_input_tokens":60000,"cache_creation_input_tokens":0,"output_tokens":5}}}
+{"type":"assistant","timestamp":"2026-01-09T14:01:00.000Z","message":{"role":"assistant","model":"claude-opus-4","content":[{"type":"text","text":"b"}],"usage":{"input_tokens":10,"cache_read_input_tokens":150000,"cache_creation_input_tokens":20000,"output_tokens":5}}}
+{"type":"system","subtype":"compact_boundary","timestamp":"2026-01-09T14:02:00.000Z","compactMetadata":{"trigger":"auto","preTokens":170010}}
+{"type":"assistant","timestamp":"2026-01-09T14:03:00.000Z","message":{"role":"assistant","model":"claude-opus-4","content":[{"type":"text","text":"c"}],"usage":{"input_tokens":10,"cache_read_input_tokens":0,"cache_creation_input_tokens":30000,"output_tokens":5}}}
+{"type":"assistant","timestamp":"2026-01-09T14:03:30.000Z","isSidechain":true,"message":{"role":"assistant","model":"claude-opus-4","content":[{"type":"text","text":"agent"}],"usage":{"input_tokens":10,"cache_read_input_tokens":5000,"cache_creation_input_tokens":0,"output_tokens":5}}}
+{"type":"assistant","timestamp":"2026-01-09T14:04:00.000Z","message":{"role":"assistant","model":"claude-opus-4","content":[{"type":"text","text":"d"}],"usage":{"input_tokens":10,"cache_read_input_tokens":30000,"cache_creation_input_tokens":10000,"output_tokens":5}}}
+`
+	if err := os.WriteFile(sessionFile, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	stats, err := ParseSessionFile(sessionFile)
+	if err != nil {
+		t.Fatalf("ParseSessionFile failed: %v", err)
+	}
+	usage := SessionContext(stats)
+
+	tests := []struct {
+		name     string
+		got      interface{}
+		expected interface{}
+	}{
+		{"Samples", len(usage.Samples), 4}, // Repeated usage and side-chain skipped
+		{"Limit", usage.Limit, DefaultContextLimit},
+		{"Current", usage.Current(), 40010},
+		{"Percent", usage.Percent(), 20.005},
+		{"Compact count", stats.CompactCount, 1},
+		{"Compact trigger", stats.Compacts[0].Trigger, "auto"},
+		{"Compact index", usage.CompactIndexes()[0], 2},
+		{"Compact at", usage.CompactAt(), 170010},
+		{"Turns until compact", usage.TurnsUntilCompact(), 13}, // 130000 left at 10000 per turn
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.expected)
+		}
+	}
+
+	if turns := (ContextUsage{Limit: DefaultContextLimit, Samples: usage.Samples[:1]}).TurnsUntilCompact(); turns != -1 {
+		t.Errorf("One turn: got %d turns, want -1", turns)
+	}
+
+	live, err := ReadLiveSession(NewestSession(dir))
+	if err != nil {
+		t.Fatalf("ReadLiveSession failed: %v", err)
+	}
+	if live.ContextPercent != 20.005 {
+		t.Errorf("Live context: got %v, want 20.005 (side-chain turn skipped)", live.ContextPercent)
+	}
+
+	// Usage beyond the model's window means the 1M context
+	stats.MessageHistory = []Message{{Role: "assistant", CacheRead: 300_000}}
+	if limit := SessionContext(stats).Limit; limit != extendedContextLimit {
+		t.Errorf("Extended limit: got %d, want %d", limit, extendedContextLimit)
+	}
+
+	SetContextLimits(map[string]int{"claude-opus": 500_000})
+	defer SetContextLimits(nil)
+	if limit := ContextLimitFor("claude-opus-4"); limit != 500_000 {
+		t.Errorf("Override: got %d, want 500000", limit)
+	}
+}
@@ -0,0 +1,205 @@
+package monitor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/thieso2/promptwatch/internal/types"
+)
+
+// Session event kinds
+const (
+	EventTurnDone   = "turn_done"  // Claude replied and waits for the next prompt
+	EventPermission = "permission" // A tool call has been pending, likely on a permission prompt
+	EventError      = "error"      // The API returned an error
+	EventCost       = "cost"       // The session cost passed another multiple of the cost step
+	EventAlert      = "alert"      // A health rule started firing
+)
+
+// EventKinds lists every session event kind
+var EventKinds = []string{EventTurnDone, EventPermission, EventError, EventCost, EventAlert}
+
+// EventRules holds the timings and limits of session events
+type EventRules struct {
+	TurnSettle     time.Duration // Quiet time after a reply before the turn counts as finished
+	PermissionWait time.Duration // Pending time before a tool call counts as waiting for permission
+	ToolCPU        float64       // CPU percent of the process tree at which a pending tool call counts as running; 0 ignores CPU
+	CostStep       float64       // USD; 0 disables cost events
+}
+
+// DefaultEventRules are the event rules used without configuration
+var DefaultEventRules = EventRules{
+	TurnSettle:     3 * time.Second,
+	PermissionWait: 10 * time.Second,
+	ToolCPU:        5,
+	CostStep:       5,
+}
+
+// awaitsPermission reports whether a tool call has been pending long enough
+// to be waiting on a permission prompt. A long approved command (a build, a
+// test run) writes nothing until it finishes either, so a call counts as
+// pending only while the process tree is idle.
+func (r EventRules) awaitsPermission(live LiveSession, now time.Time, treeCPU float64) bool {
+	if live.PendingTool == "" || now.Sub(live.LastWrite) < r.PermissionWait {
+		return false
+	}
+	return r.ToolCPU == 0 || treeCPU < r.ToolCPU
+}
+
+// SessionEvent is something in a running session worth telling the user about
+type SessionEvent struct {
+	Kind        string // One of EventKinds
+	PID         int32
+	WorkingDir  string
+	SessionFile string
+	Text        string
+}
+
+// Title names the project the event happened in
+func (e SessionEvent) Title() string {
+	return "promptwatch: " + filepath.Base(e.WorkingDir)
+}
+
+// sessionMarks remembers which state of a session was already reported
+type sessionMarks struct {
+	turn      string // Entry whose turn end was reported
+	pending   string // Entry whose pending tool call was reported
+	apiError  string // Entry whose API error was reported
+	costSteps int    // Cost steps reported
+}
+
+// EventWatcher turns the live state of running sessions into events, each
+// reported once. Sessions and processes seen for the first time only set the
+// baseline, so starting promptwatch does not replay old events.
+type EventWatcher struct {
+	mu       sync.Mutex
+	rules    EventRules
+	sessions map[string]*sessionMarks
+	alerts   map[int32]map[string]bool
+}
+
+// NewEventWatcher creates a watcher with the given rules
+func NewEventWatcher(rules EventRules) *EventWatcher {
+	return &EventWatcher{
+		rules:    rules,
+		sessions: make(map[string]*sessionMarks),
+		alerts:   make(map[int32]map[string]bool),
+	}
+}
+
+// SetRules replaces the rules, e.g. after a config reload
+func (w *EventWatcher) SetRules(rules EventRules) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.rules = rules
+}
+
+// Check compares the processes' sessions and health alerts with the previous
+// check and returns what is new
+func (w *EventWatcher) Check(processes []types.ClaudeProcess, alerts map[int32][]Alert, now time.Time) []SessionEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var events []SessionEvent
+	checked := make(map[string]bool)
+	alive := make(map[int32]bool)
+	for _, proc := range processes {
+		alive[proc.PID] = true
+		event := SessionEvent{PID: proc.PID, WorkingDir: proc.WorkingDir, SessionFile: proc.SessionFile}
+
+		// Health alerts
+		fired := make(map[string]bool)
+		previous, known := w.alerts[proc.PID]
+		for _, alert := range alerts[proc.PID] {
+			fired[alert.Rule] = true
+			if known && !previous[alert.Rule] {
+				event.Kind, event.Text = EventAlert, alert.Rule+": "+alert.Rationale
+				events = append(events, event)
+			}
+		}
+		w.alerts[proc.PID] = fired
+
+		// Several processes can share a session; report it once
+		if proc.SessionFile == "" || checked[proc.SessionFile] {
+			continue
+		}
+		checked[proc.SessionFile] = true
+		live, err := ReadLiveSession(proc.SessionFile)
+		if err != nil {
+			continue
+		}
+		for _, e := range w.sessionEvents(live, now, proc.TreeCPU) {
+			e.PID, e.WorkingDir, e.SessionFile = event.PID, event.WorkingDir, event.SessionFile
+			events = append(events, e)
+		}
+	}
+
+	for pid := range w.alerts {
+		if !alive[pid] {
+			delete(w.alerts, pid)
+		}
+	}
+	return events
+}
+
+// sessionEvents returns the new events of a session with their kind and text
+// set. treeCPU is the CPU use of the process running the session.
+func (w *EventWatcher) sessionEvents(live LiveSession, now time.Time, treeCPU float64) []SessionEvent {
+	r := w.rules
+	quiet := now.Sub(live.LastWrite)
+
+	turn := ""
+	if live.TurnEnded && quiet >= r.TurnSettle {
+		turn = live.LastUUID
+	}
+	pending := ""
+	if r.awaitsPermission(live, now, treeCPU) {
+		pending = live.LastUUID
+	}
+	apiError := ""
+	if live.APIError != "" {
+		apiError = live.LastUUID
+	}
+	costSteps := 0
+	if r.CostStep > 0 {
+		costSteps = int(live.Cost / r.CostStep)
+	}
+
+	marks, known := w.sessions[live.Path]
+	if !known {
+		w.sessions[live.Path] = &sessionMarks{turn: turn, pending: pending, apiError: apiError, costSteps: costSteps}
+		return nil
+	}
+
+	var events []SessionEvent
+	if turn != "" && turn != marks.turn {
+		events = append(events, SessionEvent{Kind: EventTurnDone, Text: "Turn finished, waiting for your next prompt"})
+		marks.turn = turn
+	}
+	if pending != "" && pending != marks.pending {
+		events = append(events, SessionEvent{Kind: EventPermission, Text: fmt.Sprintf(
+			"%s call pending for %s, probably waiting for permission", live.PendingTool, formatDuration(quiet))})
+		marks.pending = pending
+	}
+	if apiError != "" && apiError != marks.apiError {
+		events = append(events, SessionEvent{Kind: EventError, Text: "API error: " + truncateText(live.APIError, 120)})
+		marks.apiError = apiError
+	}
+	if costSteps > marks.costSteps {
+		events = append(events, SessionEvent{Kind: EventCost, Text: fmt.Sprintf(
+			"Session cost passed $%.2f", float64(costSteps)*r.CostStep)})
+	}
+	marks.costSteps = costSteps
+	return events
+}
+
+// truncateText shortens s to n runes, marking the cut with "…"
+func truncateText(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thieso2/promptwatch/internal/types"
+)
+
+// TestEventWatcher tests reporting turn ends, permission waits, API errors,
+// cost steps and new alerts once each
+func TestEventWatcher(t *testing.T) {
+	dir := t.TempDir()
+	sessionFile := filepath.Join(dir, "events.jsonl")
+	base := time.Now()
+
+	appendLines := func(lines ...string) {
+		f, err := os.OpenFile(sessionFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatalf("Failed to open test file: %v", err)
+		}
+		f.WriteString(strings.Join(lines, "\n") + "\n")
+		f.Close()
+		if err := os.Chtimes(sessionFile, base, base); err != nil {
+			t.Fatalf("Failed to set test file time: %v", err)
+		}
+	}
+
+	watcher := NewEventWatcher(DefaultEventRules)
+	processes := []types.ClaudeProcess{{PID: 7, WorkingDir: "/work/api", SessionFile: sessionFile}}
+	check := func(after time.Duration, alerts map[int32][]Alert) string {
+		var kinds []string
+		for _, event := range watcher.Check(processes, alerts, base.Add(after)) {
+			if event.PID != 7 || event.Title() != "promptwatch: api" {
+				t.Errorf("Unexpected event source %+v", event)
+			}
+			kinds = append(kinds, event.Kind)
+		}
+		return strings.Join(kinds, ",")
+	}
+
+	appendLines(`{"type":"user","uuid":"p1","message":{"role":"user","content":"hello"}}`,
+		`{"type":"assistant","uuid":"a1","message":{"role":"assistant","stop_reason":"end_turn","content":[{"type":"text","text":"hi"}]}}`)
+
+	steps := []struct {
+		name     string
+		lines    []string
+		after    time.Duration
+		alerts   map[int32][]Alert
+		treeCPU  float64
+		expected string
+	}{
+		{"Baseline", nil, time.Minute, nil, 0, ""},
+		{"Next turn still running", []string{
+			`{"type":"user","uuid":"p2","message":{"role":"user","content":"more"}}`,
+			`{"type":"assistant","uuid":"a2","message":{"role":"assistant","content":[{"type":"text","text":"ok"}]}}`,
+		}, time.Second, nil, 0, ""},
+		{"Turn finished", nil, 5 * time.Second, nil, 0, EventTurnDone},
+		{"Reported once", nil, 6 * time.Second, nil, 0, ""},
+		{"Tool running on a busy process", []string{
+			`{"type":"user","uuid":"p3","message":{"role":"user","content":"build"}}`,
+			`{"type":"assistant","uuid":"a3","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{}}]}}`,
+		}, 20 * time.Second, nil, 90, ""},
+		{"Pending tool call", nil, 20 * time.Second, nil, 0, EventPermission},
+		{"API error and cost", []string{
+			`{"type":"user","uuid":"r1","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`,
+			`{"type":"assistant","uuid":"a4","isApiErrorMessage":true,"message":{"role":"assistant","id":"m4","content":[{"type":"text","text":"API Error: overloaded"}],"usage":{"output_tokens":400000}}}`,
+		}, 21 * time.Second, nil, 0, EventError + "," + EventCost},
+		{"New alert", nil, 22 * time.Second, map[int32][]Alert{7: {{Rule: RuleRepeat, Rationale: "looping"}}}, 0, EventAlert},
+		{"Alert still firing", nil, 23 * time.Second, map[int32][]Alert{7: {{Rule: RuleRepeat, Rationale: "looping"}}}, 0, ""},
+	}
+
+	for _, step := range steps {
+		if step.lines != nil {
+			appendLines(step.lines...)
+		}
+		processes[0].TreeCPU = step.treeCPU
+		if got := check(step.after, step.alerts); got != step.expected {
+			t.Errorf("%s: got events %q, want %q", step.name, got, step.expected)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thieso2/promptwatch/internal/diff"
+)
+
+// FileActivity summarises the Read, Edit, MultiEdit and Write calls on one file
+type FileActivity struct {
+	Path         string
+	Reads        int
+	Edits        int // Edit, MultiEdit and Write calls
+	LinesAdded   int
+	LinesRemoved int
+	LastTouch    time.Time
+	Sessions     int // Sessions that touched the file
+}
+
+// Churn returns the number of lines added and removed
+func (a FileActivity) Churn() int {
+	return a.LinesAdded + a.LinesRemoved
+}
+
+// FileActivityReport is the file activity of one or more sessions
+type FileActivityReport struct {
+	WorkingDir string // Working directory of the sessions, used for relative paths
+	Sessions   int
+	Files      []FileActivity
+}
+
+// FileSortKeys lists the orders a file activity report can be sorted in
+var FileSortKeys = []string{"churn", "edits", "reads", "recent", "path"}
+
+// SessionFileActivity collects the file activity of one parsed session
+func SessionFileActivity(stats *SessionStats) FileActivityReport {
+	return CollectFileActivity([]*SessionStats{stats})
+}
+
+// CollectFileActivity merges the file activity of several sessions
+func CollectFileActivity(sessions []*SessionStats) FileActivityReport {
+	report := FileActivityReport{Sessions: len(sessions)}
+	files := make(map[string]*FileActivity)
+
+	for _, stats := range sessions {
+		touched := make(map[string]bool)
+		failed := failedToolCalls(stats.MessageHistory)
+		for _, msg := range stats.MessageHistory {
+			if report.WorkingDir == "" {
+				report.WorkingDir = msg.WorkingDir
+			}
+			if failed[msg.ToolUseID] {
+				continue // The file was not read or changed
+			}
+			path, ok := recordToolCall(files, msg)
+			if ok && !touched[path] {
+				touched[path] = true
+				files[path].Sessions++
+			}
+		}
+	}
+
+	for _, activity := range files {
+		report.Files = append(report.Files, *activity)
+	}
+	SortFileActivity(report.Files, "churn")
+	return report
+}
+
+// failedToolCalls returns the IDs of the tool calls whose result was an error
+func failedToolCalls(history []Message) map[string]bool {
+	failed := make(map[string]bool)
+	for _, msg := range history {
+		if msg.Type == "tool_result" && msg.IsError && msg.ToolUseID != "" {
+			failed[msg.ToolUseID] = true
+		}
+	}
+	return failed
+}
+
+// LoadFileActivity parses session files and merges their file activity
+func LoadFileActivity(sessionFiles []string) (FileActivityReport, error) {
+	var sessions []*SessionStats
+	for _, path := range sessionFiles {
+		stats, err := ParseSessionFile(path)
+		if err != nil {
+			return FileActivityReport{}, fmt.Errorf("cannot parse %s: %w", path, err)
+		}
+		sessions = append(sessions, stats)
+	}
+	return CollectFileActivity(sessions), nil
+}
+
+// recordToolCall adds a file tool call to files, returning the file it touched
+func recordToolCall(files map[string]*FileActivity, msg Message) (string, bool) {
+	switch msg.ToolName {
+	case "Read", "Edit", "MultiEdit", "Write":
+	default:
+		return "", false
+	}
+
+	var input struct {
+		FilePath  string `json:"file_path"`
+		OldString string `json:"old_string"`
+		NewString string `json:"new_string"`
+		Content   string `json:"content"`
+		Edits     []struct {
+			OldString string `json:"old_string"`
+			NewString string `json:"new_string"`
+		} `json:"edits"`
+	}
+	if err := json.Unmarshal([]byte(msg.ToolInput), &input); err != nil || input.FilePath == "" {
+		return "", false
+	}
+
+	activity, ok := files[input.FilePath]
+	if !ok {
+		activity = &FileActivity{Path: input.FilePath}
+		files[input.FilePath] = activity
+	}
+	if msg.Timestamp.After(activity.LastTouch) {
+		activity.LastTouch = msg.Timestamp
+	}
+
+	countLines := func(oldText, newText string) {
+		for _, l := range diff.Lines(diff.SplitLines(oldText), diff.SplitLines(newText)) {
+			switch l.Op {
+			case diff.Insert:
+				activity.LinesAdded++
+			case diff.Delete:
+				activity.LinesRemoved++
+			}
+		}
+	}
+
+	switch msg.ToolName {
+	case "Read":
+		activity.Reads++
+	case "Edit":
+		activity.Edits++
+		countLines(input.OldString, input.NewString)
+	case "MultiEdit":
+		activity.Edits++
+		for _, e := range input.Edits {
+			countLines(e.OldString, e.NewString)
+		}
+	case "Write":
+		// The previous content is unknown, so every line counts as added
+		activity.Edits++
+		countLines("", input.Content)
+	}
+	return input.FilePath, true
+}
+
+// SortFileActivity orders files by one of FileSortKeys, largest or newest first
+func SortFileActivity(files []FileActivity, by string) {
+	less := func(a, b FileActivity) bool {
+		switch by {
+		case "edits":
+			if a.Edits != b.Edits {
+				return a.Edits > b.Edits
+			}
+		case "reads":
+			if a.Reads != b.Reads {
+				return a.Reads > b.Reads
+			}
+		case "recent":
+			if !a.LastTouch.Equal(b.LastTouch) {
+				return a.LastTouch.After(b.LastTouch)
+			}
+		case "path":
+		default:
+			if a.Churn() != b.Churn() {
+				return a.Churn() > b.Churn()
+			}
+			if a.Edits != b.Edits {
+				return a.Edits > b.Edits
+			}
+		}
+		return a.Path < b.Path
+	}
+	sort.SliceStable(files, func(i, j int) bool { return less(files[i], files[j]) })
+}
+
+// RelativePath returns path relative to the sessions' working directory when it lies inside it
+func (r FileActivityReport) RelativePath(path string) string {
+	if r.WorkingDir == "" {
+		return path
+	}
+	rel, err := filepath.Rel(r.WorkingDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return path
+	}
+	return rel
+}
+
+// ByDirectory sums the activity per directory, keeping depth path components
+// of each relative path (e.g. depth 2 groups "services/api/..." together)
+func (r FileActivityReport) ByDirectory(depth int) []FileActivity {
+	dirs := make(map[string]*FileActivity)
+	for _, file := range r.Files {
+		parts := strings.Split(filepath.Dir(r.RelativePath(file.Path)), "/")
+		limit := depth
+		if parts[0] == "" {
+			limit++ // Absolute path outside the working directory
+		}
+		if len(parts) > limit {
+			parts = parts[:limit]
+		}
+		dir := strings.TrimSuffix(strings.Join(parts, "/"), "/") + "/"
+
+		activity, ok := dirs[dir]
+		if !ok {
+			activity = &FileActivity{Path: dir}
+			dirs[dir] = activity
+		}
+		activity.Reads += file.Reads
+		activity.Edits += file.Edits
+		activity.LinesAdded += file.LinesAdded
+		activity.LinesRemoved += file.LinesRemoved
+		// Per-file counts cannot tell how many sessions overlap; use the busiest file's
+		activity.Sessions = max(activity.Sessions, file.Sessions)
+		if file.LastTouch.After(activity.LastTouch) {
+			activity.LastTouch = file.LastTouch
+		}
+	}
+
+	var grouped []FileActivity
+	for _, activity := range dirs {
+		grouped = append(grouped, *activity)
+	}
+	SortFileActivity(grouped, "churn")
+	return grouped
+}
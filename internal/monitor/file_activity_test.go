@@ -0,0 +1,79 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestFileActivity tests per-file counts across tool calls and sessions
+func TestFileActivity(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.jsonl")
+	second := filepath.Join(dir, "second.jsonl")
+
+	firstData := `{"type":"assistant","timestamp":"2026-01-09T14:00:00.000Z","cwd":"/repo","message":{"role":"assistant","content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/repo/api/main.go"}}]}}
+{"type":"assistant","timestamp":"2026-01-09T14:01:00.000Z","cwd":"/repo","message":{"role":"assistant","content":[{"type":"tool_use","id":"t2","name":"Edit","input":{"file_path":"/repo/api/main.go","old_string":"a\nb","new_string":"a\nc\nd"}}]}}
+{"type":"assistant","timestamp":"2026-01-09T14:02:00.000Z","cwd":"/repo","message":{"role":"assistant","content":[{"type":"tool_use","id":"t3","name":"Write","input":{"file_path":"/repo/README.md","content":"one\ntwo\n"}}]}}
+{"type":"assistant","timestamp":"2026-01-09T14:03:00.000Z","cwd":"/repo","message":{"role":"assistant","content":[{"type":"tool_use","id":"t4","name":"Bash","input":{"command":"ls"}}]}}
+`
+	secondData := `{"type":"assistant","timestamp":"2026-01-10T09:00:00.000Z","cwd":"/repo","message":{"role":"assistant","content":[{"type":"tool_use","id":"t5","name":"MultiEdit","input":{"file_path":"/repo/api/main.go","edits":[{"old_string":"x","new_string":"y"},{"old_string":"p","new_string":""}]}}]}}
+{"type":"assistant","timestamp":"2026-01-10T09:01:00.000Z","cwd":"/repo","message":{"role":"assistant","content":[{"type":"tool_use","id":"t6","name":"Read","input":{"file_path":"/etc/hosts"}}]}}
+{"type":"assistant","timestamp":"2026-01-10T09:02:00.000Z","cwd":"/repo","message":{"role":"assistant","content":[{"type":"tool_use","id":"t7","name":"Edit","input":{"file_path":"/repo/api/main.go","old_string":"q","new_string":"r\ns"}}]}}
+{"type":"user","timestamp":"2026-01-10T09:02:01.000Z","cwd":"/repo","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t7","content":"String to replace not found in file.","is_error":true}]}}
+`
+	for path, data := range map[string]string{first: firstData, second: secondData} {
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	report, err := LoadFileActivity([]string{first, second})
+	if err != nil {
+		t.Fatalf("LoadFileActivity failed: %v", err)
+	}
+	if len(report.Files) != 3 {
+		t.Fatalf("Expected 3 files, got %d: %+v", len(report.Files), report.Files)
+	}
+
+	main := report.Files[0] // Most churn first
+	tests := []struct {
+		name     string
+		got      interface{}
+		expected interface{}
+	}{
+		{"Path", main.Path, "/repo/api/main.go"},
+		{"Reads", main.Reads, 1},
+		{"Edits", main.Edits, 2},           // The failed Edit does not count
+		{"LinesAdded", main.LinesAdded, 3}, // c, d, y
+		{"LinesRemoved", main.LinesRemoved, 3},
+		{"Sessions", main.Sessions, 2},
+		{"LastTouch", main.LastTouch, time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)},
+		{"RelativePath", report.RelativePath(main.Path), "api/main.go"},
+		{"OutsidePath", report.RelativePath("/etc/hosts"), "/etc/hosts"},
+		{"WorkingDir", report.WorkingDir, "/repo"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.expected)
+		}
+	}
+
+	SortFileActivity(report.Files, "recent")
+	if report.Files[0].Path != "/etc/hosts" {
+		t.Errorf("Expected /etc/hosts first by recency, got %s", report.Files[0].Path)
+	}
+
+	dirs := make(map[string]int)
+	for _, d := range report.ByDirectory(1) {
+		dirs[d.Path] = d.Churn()
+	}
+	want := map[string]int{"api/": 6, "./": 2, "/etc/": 0}
+	for path, churn := range want {
+		if got, ok := dirs[path]; !ok || got != churn {
+			t.Errorf("ByDirectory %s: got %d (present %v), want %d", path, got, ok, churn)
+		}
+	}
+}
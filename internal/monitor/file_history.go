@@ -0,0 +1,234 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// File history snapshots
+// ======================
+// Before each user prompt Claude Code records a checkpoint of the files it
+// has touched so far, so the prompt can be rewound:
+//
+// {"type":"file-history-snapshot","messageId":"<uuid>","isSnapshotUpdate":false,
+//  "snapshot":{"messageId":"<prompt uuid>","timestamp":"...",
+//    "trackedFileBackups":{"README.md":{"backupFileName":"3f2a…@v2","version":2,"backupTime":"..."}}}}
+//
+// Updates (isSnapshotUpdate) add files to the checkpoint of snapshot.messageId
+// when they are first edited during that turn. A backup is a copy of the file
+// as it was at the checkpoint, stored in <root>/file-history/<sessionId>/<backupFileName>;
+// a null backupFileName means the file did not exist yet.
+
+// FileBackup is the state of one tracked file at a checkpoint
+type FileBackup struct {
+	Path           string // As recorded: absolute or relative to the working directory
+	BackupFileName string // Empty when the file did not exist at the checkpoint
+	Version        int
+	BackupTime     time.Time
+}
+
+// Exists reports whether the file existed at the checkpoint
+func (b FileBackup) Exists() bool {
+	return b.BackupFileName != ""
+}
+
+// FileCheckpoint is a file-history snapshot, taken before the user prompt MessageID
+type FileCheckpoint struct {
+	MessageID string
+	Timestamp time.Time
+	Backups   map[string]FileBackup // By recorded path
+}
+
+// snapshotEntry is the JSON layout of a file-history-snapshot line
+type snapshotEntry struct {
+	IsSnapshotUpdate bool `json:"isSnapshotUpdate"`
+	Snapshot         struct {
+		MessageID          string `json:"messageId"`
+		Timestamp          string `json:"timestamp"`
+		TrackedFileBackups map[string]struct {
+			BackupFileName *string `json:"backupFileName"`
+			Version        int     `json:"version"`
+			BackupTime     string  `json:"backupTime"`
+		} `json:"trackedFileBackups"`
+	} `json:"snapshot"`
+}
+
+// addSnapshot records a file-history-snapshot line in stats.Checkpoints
+func (s *SessionStats) addSnapshot(line []byte) {
+	var entry snapshotEntry
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return
+	}
+
+	var checkpoint *FileCheckpoint
+	if entry.IsSnapshotUpdate {
+		for i := range s.Checkpoints {
+			if s.Checkpoints[i].MessageID == entry.Snapshot.MessageID {
+				checkpoint = &s.Checkpoints[i]
+			}
+		}
+	}
+	if checkpoint == nil {
+		s.Checkpoints = append(s.Checkpoints, FileCheckpoint{
+			MessageID: entry.Snapshot.MessageID,
+			Timestamp: parseTimestamp(entry.Snapshot.Timestamp),
+			Backups:   make(map[string]FileBackup),
+		})
+		checkpoint = &s.Checkpoints[len(s.Checkpoints)-1]
+	}
+
+	for path, b := range entry.Snapshot.TrackedFileBackups {
+		backup := FileBackup{Path: path, Version: b.Version, BackupTime: parseTimestamp(b.BackupTime)}
+		if b.BackupFileName != nil {
+			backup.BackupFileName = *b.BackupFileName
+		}
+		checkpoint.Backups[path] = backup
+	}
+}
+
+// parseTimestamp parses an RFC 3339 timestamp, returning the zero time when invalid
+func parseTimestamp(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// FileHistory gives access to the checkpointed file states of a session
+type FileHistory struct {
+	SessionID   string
+	Dir         string // <root>/file-history/<sessionId>; empty when no backups were found
+	WorkingDir  string
+	Checkpoints []FileCheckpoint
+	Prompts     []string // User prompt of each checkpoint, when known
+}
+
+// FileVersion is the state of a file at one checkpoint
+type FileVersion struct {
+	Checkpoint int // Index into FileHistory.Checkpoints
+	Backup     FileBackup
+}
+
+// NewFileHistory prepares the file history of a parsed session, locating its
+// backup directory in the data root the session file belongs to
+func NewFileHistory(stats *SessionStats) *FileHistory {
+	h := &FileHistory{
+		SessionID:   strings.TrimSuffix(filepath.Base(stats.FilePath), ".jsonl"),
+		Checkpoints: stats.Checkpoints,
+		Prompts:     make([]string, len(stats.Checkpoints)),
+	}
+
+	prompts := make(map[string]string)
+	for _, msg := range stats.MessageHistory {
+		if h.WorkingDir == "" {
+			h.WorkingDir = msg.WorkingDir
+		}
+		if msg.SessionID != "" {
+			h.SessionID = msg.SessionID
+		}
+		if msg.Type == "prompt" && msg.UUID != "" {
+			prompts[msg.UUID] = msg.Content
+		}
+	}
+	for i, c := range h.Checkpoints {
+		h.Prompts[i] = prompts[c.MessageID]
+	}
+
+	candidates := DataRoots()
+	if root, ok := RootForPath(stats.FilePath); ok {
+		candidates = appendRoot([]DataRoot{root}, candidates...)
+	}
+	for _, root := range candidates {
+		dir := filepath.Join(root.Dir, "file-history", h.SessionID)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			h.Dir = dir
+			break
+		}
+	}
+	return h
+}
+
+// Files returns the recorded paths of every tracked file, sorted
+func (h *FileHistory) Files() []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, c := range h.Checkpoints {
+		for path := range c.Backups {
+			if !seen[path] {
+				seen[path] = true
+				files = append(files, path)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// AbsPath resolves a recorded path against the session's working directory
+func (h *FileHistory) AbsPath(path string) string {
+	if filepath.IsAbs(path) || h.WorkingDir == "" {
+		return path
+	}
+	return filepath.Join(h.WorkingDir, path)
+}
+
+// Versions returns the state of path at each checkpoint from the one that
+// first tracked it. A checkpoint that does not list the file keeps the
+// previous state.
+func (h *FileHistory) Versions(path string) []FileVersion {
+	var versions []FileVersion
+	var current *FileBackup
+	for i, c := range h.Checkpoints {
+		if backup, ok := c.Backups[path]; ok {
+			current = &backup
+		}
+		if current != nil {
+			versions = append(versions, FileVersion{Checkpoint: i, Backup: *current})
+		}
+	}
+	return versions
+}
+
+// Content returns the content of path at a checkpoint. exists is false when
+// the file did not exist then; an error means the state is unknown.
+func (h *FileHistory) Content(path string, checkpoint int) (content string, exists bool, err error) {
+	if checkpoint < 0 || checkpoint >= len(h.Checkpoints) {
+		return "", false, fmt.Errorf("no checkpoint %d (session has %d)", checkpoint+1, len(h.Checkpoints))
+	}
+
+	for _, v := range h.Versions(path) {
+		if v.Checkpoint != checkpoint {
+			continue
+		}
+		if !v.Backup.Exists() {
+			return "", false, nil
+		}
+		if h.Dir == "" {
+			return "", false, fmt.Errorf("no file-history directory for session %s", h.SessionID)
+		}
+		data, err := os.ReadFile(filepath.Join(h.Dir, v.Backup.BackupFileName))
+		if err != nil {
+			return "", false, fmt.Errorf("cannot read backup of %s: %w", path, err)
+		}
+		return string(data), true, nil
+	}
+	return "", false, fmt.Errorf("%s is not tracked at checkpoint %d", path, checkpoint+1)
+}
+
+// CurrentContent returns the content of path in the working tree
+func (h *FileHistory) CurrentContent(path string) (content string, exists bool, err error) {
+	data, err := os.ReadFile(h.AbsPath(path))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), true, nil
+}
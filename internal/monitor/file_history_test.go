@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestFileHistory tests parsing snapshots and reading file states at checkpoints
+func TestFileHistory(t *testing.T) {
+	root := t.TempDir()
+	SetDataRoots([]DataRoot{NewDataRoot("test", root)})
+	defer SetDataRoots(nil)
+
+	projectDir := filepath.Join(root, "projects", "-repo")
+	backupDir := filepath.Join(root, "file-history", "sess-1")
+	for _, dir := range []string{projectDir, backupDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+	backups := map[string]string{"aaa@v1": "one\n", "aaa@v2": "one\ntwo\n"}
+	for name, content := range backups {
+		if err := os.WriteFile(filepath.Join(backupDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write backup: %v", err)
+		}
+	}
+
+	// Checkpoint 1 is updated when main.go is first edited; checkpoint 2
+	// records new.go before it was created; checkpoint 3 omits new.go
+	sessionFile := filepath.Join(projectDir, "sess-1.jsonl")
+	data := `{"type":"user","timestamp":"2026-01-09T14:00:00.000Z","uuid":"p1","sessionId":"sess-1","cwd":"/repo","message":{"role":"user","content":"first"}}
+{"type":"file-history-snapshot","messageId":"p1","snapshot":{"messageId":"p1","trackedFileBackups":{},"timestamp":"2026-01-09T14:00:00.000Z"},"isSnapshotUpdate":false}
+{"type":"file-history-snapshot","messageId":"x1","snapshot":{"messageId":"p1","trackedFileBackups":{"main.go":{"backupFileName":"aaa@v1","version":1,"backupTime":"2026-01-09T14:00:05.000Z"}},"timestamp":"2026-01-09T14:00:00.000Z"},"isSnapshotUpdate":true}
+{"type":"user","timestamp":"2026-01-09T14:01:00.000Z","uuid":"p2","sessionId":"sess-1","cwd":"/repo","message":{"role":"user","content":"second"}}
+{"type":"file-history-snapshot","messageId":"p2","snapshot":{"messageId":"p2","trackedFileBackups":{"main.go":{"backupFileName":"aaa@v2","version":2,"backupTime":"2026-01-09T14:01:00.000Z"},"new.go":{"backupFileName":null,"version":1,"backupTime":"2026-01-09T14:01:00.000Z"}},"timestamp":"2026-01-09T14:01:00.000Z"},"isSnapshotUpdate":false}
+{"type":"file-history-snapshot","messageId":"p3","snapshot":{"messageId":"p3","trackedFileBackups":{"main.go":{"backupFileName":"aaa@v2","version":2,"backupTime":"2026-01-09T14:01:00.000Z"}},"timestamp":"2026-01-09T14:02:00.000Z"},"isSnapshotUpdate":false}
+`
+	if err := os.WriteFile(sessionFile, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	stats, err := ParseSessionFile(sessionFile)
+	if err != nil {
+		t.Fatalf("ParseSessionFile failed: %v", err)
+	}
+	if stats.FileSnapshots != 4 || len(stats.Checkpoints) != 3 {
+		t.Fatalf("Expected 4 snapshots in 3 checkpoints, got %d in %d", stats.FileSnapshots, len(stats.Checkpoints))
+	}
+
+	h := NewFileHistory(stats)
+	if h.Dir != backupDir {
+		t.Errorf("Dir: got %q, want %q", h.Dir, backupDir)
+	}
+	if h.Prompts[1] != "second" {
+		t.Errorf("Prompt of checkpoint 2: got %q, want %q", h.Prompts[1], "second")
+	}
+	if files := h.Files(); len(files) != 2 || files[0] != "main.go" || files[1] != "new.go" {
+		t.Errorf("Files: got %v", files)
+	}
+	if got := h.AbsPath("main.go"); got != "/repo/main.go" {
+		t.Errorf("AbsPath: got %q", got)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		checkpoint int
+		content    string
+		exists     bool
+		wantErr    bool
+	}{
+		{"first backup", "main.go", 0, "one\n", true, false},
+		{"second backup", "main.go", 1, "one\ntwo\n", true, false},
+		{"not yet created", "new.go", 1, "", false, false},
+		{"carried forward", "new.go", 2, "", false, false},
+		{"not yet tracked", "new.go", 0, "", false, true},
+		{"out of range", "main.go", 3, "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, exists, err := h.Content(tt.path, tt.checkpoint)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Content error: got %v, want error %v", err, tt.wantErr)
+			}
+			if content != tt.content || exists != tt.exists {
+				t.Errorf("Content: got %q (exists %v), want %q (exists %v)", content, exists, tt.content, tt.exists)
+			}
+		})
+	}
+}
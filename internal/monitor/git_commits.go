@@ -0,0 +1,189 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CommitGrace extends a session's time window when looking for its commits,
+// so a commit made right after the last message still counts
+var CommitGrace = 10 * time.Minute
+
+// Commit is a git commit made while a session ran
+type Commit struct {
+	Hash    string
+	Author  string
+	Time    time.Time
+	Subject string
+	Files   []string // Absolute paths of the changed files
+	Edited  []string // Files also changed by the session's Edit, MultiEdit or Write calls
+}
+
+// ShortHash returns the abbreviated commit hash
+func (c Commit) ShortHash() string {
+	if len(c.Hash) > 8 {
+		return c.Hash[:8]
+	}
+	return c.Hash
+}
+
+// IsEdited reports whether the session edited file, an absolute path
+func (c Commit) IsEdited(file string) bool {
+	for _, f := range c.Edited {
+		if f == file {
+			return true
+		}
+	}
+	return false
+}
+
+// GitLog lists the commits of the repository containing dir made between
+// since and until on branch, newest first. An empty or unknown branch
+// searches all branches.
+func GitLog(dir, branch string, since, until time.Time) ([]Commit, error) {
+	top, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	top = strings.TrimSpace(top)
+
+	args := []string{"log", "--name-only", "--format=%x1e%H%x1f%an%x1f%cI%x1f%s",
+		"--since=" + since.Format(time.RFC3339), "--until=" + until.Format(time.RFC3339)}
+	ref := "--all"
+	if branch != "" {
+		if _, err := runGit(dir, "rev-parse", "--verify", "--quiet", branch+"^{commit}"); err == nil {
+			ref = branch
+		}
+	}
+	out, err := runGit(dir, append(args, ref)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(out, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		fields := strings.Split(lines[0], "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		commit := Commit{Hash: fields[0], Author: fields[1], Time: parseTimestamp(fields[2]), Subject: fields[3]}
+		for _, file := range lines[1:] {
+			if file = strings.TrimSpace(file); file != "" {
+				commit.Files = append(commit.Files, filepath.Join(top, file))
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// runGit runs a git command in dir and returns its output
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+// SessionCommits lists the commits made in the session's working directory
+// on its git branch while it ran, marking the files the session edited
+func SessionCommits(stats *SessionStats) ([]Commit, error) {
+	var workingDir, branch string
+	for _, msg := range stats.MessageHistory {
+		if workingDir == "" {
+			workingDir = msg.WorkingDir
+		}
+		if msg.GitBranch != "" {
+			branch = msg.GitBranch // The last branch wins if the session switched
+		}
+	}
+	if workingDir == "" || stats.CreatedAt.IsZero() {
+		return nil, fmt.Errorf("session has no working directory")
+	}
+
+	commits, err := GitLog(workingDir, branch, stats.CreatedAt, stats.LastActivity.Add(CommitGrace))
+	if err != nil {
+		return nil, err
+	}
+
+	edited := make(map[string]bool)
+	for _, file := range SessionFileActivity(stats).Files {
+		if file.Edits > 0 {
+			edited[canonicalPath(file.Path)] = true
+		}
+	}
+	for i := range commits {
+		for _, file := range commits[i].Files {
+			if edited[canonicalPath(file)] {
+				commits[i].Edited = append(commits[i].Edited, file)
+			}
+		}
+		sort.Strings(commits[i].Edited)
+	}
+	return commits, nil
+}
+
+// commitsEntry caches the commits of a session file with the modification
+// time they were looked up for
+type commitsEntry struct {
+	modTime time.Time
+	commits []Commit
+	err     error
+}
+
+var (
+	commitsMu    sync.Mutex
+	commitsCache = make(map[string]commitsEntry)
+)
+
+// SessionFileCommits parses a session file and lists its commits like
+// SessionCommits. The result is reused until the file changes, except while
+// a commit made after the last message could still fall in the window.
+func SessionFileCommits(path string) ([]Commit, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	commitsMu.Lock()
+	cached, ok := commitsCache[path]
+	commitsMu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) {
+		return cached.commits, cached.err
+	}
+
+	stats, err := ParseSessionFile(path)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := SessionCommits(stats)
+	if time.Since(stats.LastActivity) > CommitGrace {
+		commitsMu.Lock()
+		commitsCache[path] = commitsEntry{modTime: info.ModTime(), commits: commits, err: err}
+		commitsMu.Unlock()
+	}
+	return commits, err
+}
+
+// canonicalPath resolves symlinks so paths from git and from tool calls
+// compare equal (e.g. /tmp and /private/tmp on macOS)
+func canonicalPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
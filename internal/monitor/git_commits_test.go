@@ -0,0 +1,91 @@
+package monitor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSessionCommits tests finding the commits made during a session
+func TestSessionCommits(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+
+	git := func(date string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+			"GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("Failed to run git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(date, subject string, files ...string) {
+		for _, f := range files {
+			if err := os.WriteFile(filepath.Join(repo, f), []byte(subject+"\n"), 0644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+		}
+		git(date, append([]string{"add"}, files...)...)
+		git(date, "commit", "-q", "-m", subject)
+	}
+
+	git("2026-01-09T10:00:00Z", "init", "-q", "-b", "main")
+	commit("2026-01-09T10:00:00Z", "Before the session", "a.go")
+	commit("2026-01-09T14:10:00Z", "Agent change", "a.go", "b.go")
+	commit("2026-01-09T14:35:00Z", "Follow-up right after", "c.go")
+	commit("2026-01-09T18:00:00Z", "Much later", "a.go")
+
+	at := func(clock string) time.Time {
+		ts, _ := time.Parse(time.RFC3339, "2026-01-09T"+clock+"Z")
+		return ts
+	}
+	stats := &SessionStats{
+		CreatedAt:    at("14:00:00"),
+		LastActivity: at("14:30:00"),
+		MessageHistory: []Message{
+			{ToolName: "Edit", ToolInput: `{"file_path":"` + filepath.Join(repo, "a.go") + `","old_string":"x","new_string":"y"}`,
+				WorkingDir: repo, GitBranch: "main", Timestamp: at("14:05:00")},
+			{ToolName: "Read", ToolInput: `{"file_path":"` + filepath.Join(repo, "b.go") + `"}`,
+				WorkingDir: repo, GitBranch: "main", Timestamp: at("14:06:00")},
+		},
+	}
+
+	commits, err := SessionCommits(stats)
+	if err != nil {
+		t.Fatalf("SessionCommits failed: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits, got %d: %+v", len(commits), commits)
+	}
+
+	tests := []struct {
+		name     string
+		got      interface{}
+		expected interface{}
+	}{
+		{"Newest first", commits[0].Subject, "Follow-up right after"},
+		{"Session commit", commits[1].Subject, "Agent change"},
+		{"Files", len(commits[1].Files), 2},
+		{"Edited file", commits[1].IsEdited(commits[1].Files[0]), true},
+		{"Only read", commits[1].IsEdited(filepath.Join(filepath.Dir(commits[1].Files[0]), "b.go")), false},
+		{"Edited count", len(commits[1].Edited), 1},
+		{"Author", commits[1].Author, "Test"},
+		{"ShortHash", len(commits[1].ShortHash()), 8},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.expected)
+		}
+	}
+
+	if _, err := GitLog(t.TempDir(), "", at("14:00:00"), at("15:00:00")); err == nil {
+		t.Errorf("Expected an error outside a git repository")
+	}
+}
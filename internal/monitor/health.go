@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thieso2/promptwatch/internal/types"
+)
+
+// HealthRules holds the thresholds of the runaway and stuck-agent heuristics.
+// A zero threshold disables its rule.
+type HealthRules struct {
+	RepeatCalls int           // Identical tool calls in a row
+	ErrorStreak int           // Failed tool results in a row
+	BusyCPU     float64       // CPU percent that counts as busy
+	BusySilence time.Duration // Busy this long without new session lines
+	IdleAfter   time.Duration // No new session lines this long while the process runs
+}
+
+// DefaultHealthRules are the thresholds used without configuration
+var DefaultHealthRules = HealthRules{
+	RepeatCalls: 5,
+	ErrorStreak: 4,
+	BusyCPU:     80,
+	BusySilence: 3 * time.Minute,
+	IdleAfter:   30 * time.Minute,
+}
+
+// Health rule names
+const (
+	RuleRepeat = "loop"
+	RuleErrors = "errors"
+	RuleBusy   = "busy"
+	RuleIdle   = "idle"
+)
+
+// Alert is a heuristic that fired for a running session
+type Alert struct {
+	Rule      string // One of the Rule constants
+	Rationale string // Why the rule fired, for display
+}
+
+// Watchdog applies the health rules to running processes. It remembers since
+// when each process has been busy, so it must see every refresh.
+type Watchdog struct {
+	mu        sync.Mutex
+	rules     HealthRules
+	busySince map[int32]time.Time
+}
+
+// NewWatchdog creates a watchdog with the given rules
+func NewWatchdog(rules HealthRules) *Watchdog {
+	return &Watchdog{rules: rules, busySince: make(map[int32]time.Time)}
+}
+
+// SetRules replaces the thresholds, e.g. after a config reload
+func (w *Watchdog) SetRules(rules HealthRules) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.rules = rules
+}
+
+// Check returns the alerts of each process that has any, keyed by PID
+func (w *Watchdog) Check(processes []types.ClaudeProcess, now time.Time) map[int32][]Alert {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	alerts := make(map[int32][]Alert)
+	seen := make(map[int32]bool)
+	for _, proc := range processes {
+		seen[proc.PID] = true
+		if w.rules.BusyCPU > 0 && proc.CPUPercent >= w.rules.BusyCPU {
+			if _, ok := w.busySince[proc.PID]; !ok {
+				w.busySince[proc.PID] = now
+			}
+		} else {
+			delete(w.busySince, proc.PID)
+		}
+
+		if proc.SessionFile == "" {
+			continue
+		}
+		live, err := ReadLiveSession(proc.SessionFile)
+		if err != nil {
+			continue
+		}
+		if found := w.rulesFor(proc, live, now); len(found) > 0 {
+			alerts[proc.PID] = found
+		}
+	}
+
+	// Processes that exited start over if their PID is reused
+	for pid := range w.busySince {
+		if !seen[pid] {
+			delete(w.busySince, pid)
+		}
+	}
+	return alerts
+}
+
+// rulesFor evaluates every rule for one process and its live session
+func (w *Watchdog) rulesFor(proc types.ClaudeProcess, live LiveSession, now time.Time) []Alert {
+	var alerts []Alert
+	r := w.rules
+	silent := now.Sub(live.LastWrite)
+
+	if r.RepeatCalls > 0 && live.RepeatCount >= r.RepeatCalls {
+		alerts = append(alerts, Alert{RuleRepeat, fmt.Sprintf(
+			"%s called %d times in a row with identical input", live.RepeatTool, live.RepeatCount)})
+	}
+	if r.ErrorStreak > 0 && live.ErrorStreak >= r.ErrorStreak {
+		alerts = append(alerts, Alert{RuleErrors, fmt.Sprintf(
+			"last %d tool calls failed", live.ErrorStreak)})
+	}
+	if since, busy := w.busySince[proc.PID]; busy && r.BusySilence > 0 &&
+		now.Sub(since) >= r.BusySilence && silent >= r.BusySilence {
+		alerts = append(alerts, Alert{RuleBusy, fmt.Sprintf(
+			"CPU at %.0f%% for %s without new session lines", proc.CPUPercent, formatDuration(now.Sub(since)))})
+	}
+	if r.IdleAfter > 0 && silent >= r.IdleAfter && (r.BusyCPU == 0 || proc.CPUPercent < r.BusyCPU) {
+		alerts = append(alerts, Alert{RuleIdle, fmt.Sprintf(
+			"no session activity for %s while the process is running", formatDuration(silent))})
+	}
+	return alerts
+}
@@ -0,0 +1,87 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thieso2/promptwatch/internal/types"
+)
+
+// TestWatchdog tests the loop, error streak, busy and idle rules
+func TestWatchdog(t *testing.T) {
+	dir := t.TempDir()
+
+	call := `{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"t%d","name":"Bash","input":{"command":"make"}}]}}`
+	result := `{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t%d","content":"failed","is_error":%t}]}}`
+	prompt := `{"type":"user","message":{"role":"user","content":"try again"}}`
+
+	write := func(name string, lines ...string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+		return path
+	}
+	var looping []string
+	for i := 0; i < 5; i++ {
+		looping = append(looping, fmt.Sprintf(call, i), fmt.Sprintf(result, i, i > 0))
+	}
+	loopFile := write("loop.jsonl", looping...)
+	promptFile := write("prompt.jsonl", append(looping, prompt)...)
+
+	now := time.Now()
+	idleFile := write("idle.jsonl", prompt)
+	if err := os.Chtimes(idleFile, now.Add(-time.Hour), now.Add(-time.Hour)); err != nil {
+		t.Fatalf("Failed to age test file: %v", err)
+	}
+
+	watchdog := NewWatchdog(DefaultHealthRules)
+	processes := []types.ClaudeProcess{
+		{PID: 1, SessionFile: loopFile},
+		{PID: 2, SessionFile: promptFile},
+		{PID: 3, SessionFile: idleFile, CPUPercent: 95},
+	}
+	watchdog.Check(processes, now)
+	alerts := watchdog.Check(processes, now.Add(4*time.Minute))
+
+	rules := func(pid int32) string {
+		var names []string
+		for _, alert := range alerts[pid] {
+			names = append(names, alert.Rule)
+		}
+		return strings.Join(names, ",")
+	}
+
+	tests := []struct {
+		name     string
+		pid      int32
+		expected string
+	}{
+		{"Loop and errors", 1, "loop,errors"},
+		{"Prompt resets loop", 2, "errors"},
+		{"Busy without output", 3, "busy"}, // Busy processes are not idle
+	}
+	for _, tt := range tests {
+		if got := rules(tt.pid); got != tt.expected {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+	if rationale := alerts[1][0].Rationale; rationale != "Bash called 5 times in a row with identical input" {
+		t.Errorf("Unexpected rationale %q", rationale)
+	}
+
+	processes[2].CPUPercent = 0
+	alerts = watchdog.Check(processes, now.Add(5*time.Minute))
+	if got := rules(3); got != "idle" {
+		t.Errorf("Idle process: got %q, want \"idle\"", got)
+	}
+
+	watchdog.SetRules(HealthRules{})
+	if alerts = watchdog.Check(processes, now.Add(6*time.Minute)); len(alerts) != 0 {
+		t.Errorf("Disabled rules still fired: %v", alerts)
+	}
+}
@@ -0,0 +1,261 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// liveTail is how much of the end of a session file ReadLiveSession reads
+const liveTail = 256 * 1024
+
+// LiveSession summarises the end of a session file that is still being written
+type LiveSession struct {
+	Path           string
+	LastWrite      time.Time // Modification time of the file
+	ContextPercent float64   // Context window use of the latest turn, 0-100 (0 = unknown)
+	RepeatTool     string    // Tool of the trailing run of identical calls
+	RepeatCount    int       // Length of that run
+	ErrorStreak    int       // Trailing tool results that all failed
+
+	LastUUID       string // Last user or assistant entry of the main conversation
+	LastRole       string // Its role
+	TurnEnded      bool   // It is an assistant reply without tool calls
+	PendingTool    string // Tool the last entry called that has no result yet
+	AsksPermission bool   // A permission request followed the last entry
+	APIError       string // Text of the last entry when it is an API error message
+	Cost           float64
+}
+
+// liveEntry caches a LiveSession with the file state it was read from and
+// the progress of the running cost total
+type liveEntry struct {
+	size       int64
+	session    LiveSession
+	costOffset int64  // End of the last complete line counted
+	lastID     string // API message ID counted last; its blocks repeat the usage
+}
+
+var (
+	liveMu    sync.Mutex
+	liveCache = make(map[string]liveEntry)
+)
+
+// NewestSession returns the session file of a project directory written last,
+// or "" when the project has none
+func NewestSession(projectDir string) string {
+	matches, _ := filepath.Glob(filepath.Join(projectDir, "*.jsonl"))
+	var newest string
+	var newestTime time.Time
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if newest == "" || info.ModTime().After(newestTime) {
+			newest, newestTime = path, info.ModTime()
+		}
+	}
+	return newest
+}
+
+// ReadLiveSession reads the tail of a session file. Results are cached until
+// the file changes, so calling it on every refresh is cheap.
+func ReadLiveSession(path string) (LiveSession, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return LiveSession{}, err
+	}
+
+	liveMu.Lock()
+	cached, ok := liveCache[path]
+	liveMu.Unlock()
+	if ok && cached.session.LastWrite.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.session, nil
+	}
+
+	session, err := readLiveTail(path, info.Size())
+	if err != nil {
+		return LiveSession{}, err
+	}
+	session.LastWrite = info.ModTime()
+
+	// The cost covers the whole file, so continue from where the last read
+	// stopped unless the file was replaced
+	entry := liveEntry{size: info.Size()}
+	if ok && cached.size <= info.Size() {
+		entry.costOffset, entry.lastID, session.Cost = cached.costOffset, cached.lastID, cached.session.Cost
+	}
+	if err := addLiveCost(path, &entry, &session); err != nil {
+		return LiveSession{}, err
+	}
+	entry.session = session
+
+	liveMu.Lock()
+	liveCache[path] = entry
+	liveMu.Unlock()
+	return session, nil
+}
+
+// addLiveCost adds the cost of the complete lines written since entry.costOffset
+func addLiveCost(path string, entry *liveEntry, session *LiveSession) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	data := make([]byte, entry.size-entry.costOffset)
+	if _, err := file.ReadAt(data, entry.costOffset); err != nil && err != io.EOF {
+		return err
+	}
+	end := bytes.LastIndexByte(data, '\n') + 1 // A partly written line is counted next time
+	for _, line := range bytes.Split(data[:end], []byte("\n")) {
+		var e struct {
+			Type    string `json:"type"`
+			Message struct {
+				ID    string     `json:"id"`
+				Model string     `json:"model"`
+				Usage TokenUsage `json:"usage"`
+			} `json:"message"`
+		}
+		if json.Unmarshal(line, &e) != nil || e.Type != "assistant" || (e.Message.ID != "" && e.Message.ID == entry.lastID) {
+			continue
+		}
+		entry.lastID = e.Message.ID
+		u := e.Message.Usage
+		cost, _ := MessageCost(Message{
+			Model:         e.Message.Model,
+			InputTokens:   u.InputTokens,
+			OutputTokens:  u.OutputTokens,
+			CacheCreation: u.CacheCreationInputTokens,
+			CacheRead:     u.CacheReadInputTokens,
+		})
+		session.Cost += cost
+	}
+	entry.costOffset += int64(end)
+	return nil
+}
+
+// readLiveTail scans the last liveTail bytes of a session file. Side-chain
+// entries belong to sub-agents and are skipped.
+func readLiveTail(path string, size int64) (LiveSession, error) {
+	session := LiveSession{Path: path}
+	file, err := os.Open(path)
+	if err != nil {
+		return session, err
+	}
+	defer file.Close()
+
+	offset := max(size-liveTail, 0)
+	tail := make([]byte, size-offset)
+	if _, err := file.ReadAt(tail, offset); err != nil && err != io.EOF {
+		return session, err
+	}
+
+	var lastCall string
+	for _, line := range bytes.Split(tail, []byte("\n")) {
+		var entry struct {
+			Type        string `json:"type"`
+			Subtype     string `json:"subtype"`
+			Content     string `json:"content"` // System entries
+			UUID        string `json:"uuid"`
+			IsSidechain bool   `json:"isSidechain"`
+			IsAPIError  bool   `json:"isApiErrorMessage"`
+			Message     struct {
+				Model      string          `json:"model"`
+				StopReason string          `json:"stop_reason"`
+				Usage      TokenUsage      `json:"usage"`
+				Content    json.RawMessage `json:"content"`
+			} `json:"message"`
+		}
+		if err := json.Unmarshal(line, &entry); err != nil || entry.IsSidechain {
+			continue // The first line may be cut off
+		}
+
+		var blocks []struct {
+			Type    string          `json:"type"`
+			Name    string          `json:"name"`
+			Text    string          `json:"text"`
+			Input   json.RawMessage `json:"input"`
+			IsError bool            `json:"is_error"`
+		}
+		json.Unmarshal(entry.Message.Content, &blocks) // Plain string content has no blocks
+
+		if entry.Type == "user" || entry.Type == "assistant" {
+			session.LastUUID, session.LastRole = entry.UUID, entry.Type
+			session.TurnEnded, session.PendingTool, session.APIError = false, "", ""
+			session.AsksPermission = false
+		}
+
+		switch entry.Type {
+		case "system":
+			if isPermissionRequest(entry.Subtype, entry.Content) {
+				session.AsksPermission = true
+			}
+		case "assistant":
+			session.TurnEnded = entry.Message.StopReason != "tool_use"
+			for _, block := range blocks {
+				switch {
+				case block.Type == "tool_use":
+					session.TurnEnded, session.PendingTool = false, block.Name
+				case block.Type == "thinking":
+					session.TurnEnded = false // The reply follows
+				case block.Type == "text" && entry.IsAPIError:
+					session.TurnEnded, session.APIError = false, block.Text
+				}
+			}
+			u := entry.Message.Usage
+			if tokens := u.InputTokens + u.CacheReadInputTokens + u.CacheCreationInputTokens; tokens > 0 {
+				limit := ContextLimitFor(entry.Message.Model)
+				if tokens > limit {
+					limit = max(limit, extendedContextLimit)
+				}
+				session.ContextPercent = float64(tokens) * 100 / float64(limit)
+			}
+			for _, block := range blocks {
+				if block.Type != "tool_use" {
+					continue
+				}
+				call := block.Name + string(block.Input)
+				if call == lastCall {
+					session.RepeatCount++
+				} else {
+					lastCall, session.RepeatTool, session.RepeatCount = call, block.Name, 1
+				}
+			}
+		case "user":
+			results := 0
+			for _, block := range blocks {
+				if block.Type != "tool_result" {
+					continue
+				}
+				results++
+				if block.IsError {
+					session.ErrorStreak++
+				} else {
+					session.ErrorStreak = 0
+				}
+			}
+			if results == 0 {
+				// A new prompt: repeating the previous call is no longer a loop
+				lastCall, session.RepeatTool, session.RepeatCount = "", "", 0
+			}
+		}
+	}
+	return session, nil
+}
+
+// permissionRequestPrefix starts the note Claude writes when it asks to use a tool
+const permissionRequestPrefix = "Claude requested permissions to use "
+
+// isPermissionRequest reports whether a system entry is a permission request.
+// Other notes that mention permissions, such as permission mode changes, are not.
+func isPermissionRequest(subtype, content string) bool {
+	return subtype == "informational" && strings.HasPrefix(content, permissionRequestPrefix)
+}
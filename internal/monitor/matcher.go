@@ -0,0 +1,215 @@
+package monitor
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ProcessInfo is the subset of process data matchers look at.
+// Exe is the resolved executable path, Args the full argv including argv[0].
+type ProcessInfo struct {
+	Exe  string
+	Args []string
+}
+
+// ProcessMatcher decides whether a process is a Claude instance
+type ProcessMatcher interface {
+	Name() string
+	Match(info ProcessInfo) bool
+}
+
+// matcherFunc adapts a plain function to the ProcessMatcher interface
+type matcherFunc struct {
+	name  string
+	match func(info ProcessInfo) bool
+}
+
+func (m matcherFunc) Name() string                { return m.name }
+func (m matcherFunc) Match(info ProcessInfo) bool { return m.match(info) }
+
+// claudePackage is the npm package the CLI is published as
+const claudePackage = "@anthropic-ai/claude-code"
+
+// versionManagerDirs are path fragments used by node version managers
+// for installed binaries and shims (nvm, volta, asdf, mise, fnm, nodenv)
+var versionManagerDirs = []string{
+	"/.nvm/versions/",
+	"/.volta/",
+	"/.asdf/",
+	"/mise/installs/",
+	"/fnm/node-versions/",
+	"/fnm_multishells/",
+	"/.nodenv/",
+}
+
+// nativeVersionsPattern matches binaries laid down by the native installer,
+// e.g. ~/.local/share/claude/versions/2.0.14
+var nativeVersionsPattern = regexp.MustCompile(`/claude/versions/[^/]+$`)
+
+// DefaultMatchers returns the built-in matchers covering the usual launch styles
+func DefaultMatchers() []ProcessMatcher {
+	return []ProcessMatcher{
+		matcherFunc{name: "native", match: matchNative},
+		matcherFunc{name: "npx", match: matchNpx},
+		matcherFunc{name: "version-manager", match: matchVersionManager},
+		matcherFunc{name: "node", match: matchNodeCLI},
+	}
+}
+
+// matchNative matches standalone claude executables and native installer builds
+func matchNative(info ProcessInfo) bool {
+	if info.Exe == "claude" || strings.HasSuffix(info.Exe, "/claude") {
+		return true
+	}
+	return nativeVersionsPattern.MatchString(info.Exe)
+}
+
+// matchNpx matches the node process npx spawns from its package cache
+func matchNpx(info ProcessInfo) bool {
+	script, ok := nodeScript(info)
+	if !ok || !strings.Contains(script, "/_npx/") {
+		return false
+	}
+	return isClaudeScript(script)
+}
+
+// matchVersionManager matches claude run through nvm, volta, asdf and friends
+func matchVersionManager(info ProcessInfo) bool {
+	script, ok := nodeScript(info)
+	if !ok || !isClaudeScript(script) {
+		return false
+	}
+	for _, dir := range versionManagerDirs {
+		if strings.Contains(script, dir) || strings.Contains(info.Exe, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchNodeCLI matches `node .../@anthropic-ai/claude-code/cli.js` and global npm installs.
+// The CLI renames its process title to "claude", which replaces argv on Linux.
+func matchNodeCLI(info ProcessInfo) bool {
+	if isNodeExe(info.Exe) && len(info.Args) > 0 && info.Args[0] == "claude" {
+		return true
+	}
+	script, ok := nodeScript(info)
+	if !ok {
+		return false
+	}
+	return isClaudeScript(script)
+}
+
+// isClaudeScript reports whether a script path node is running belongs to the CLI
+func isClaudeScript(script string) bool {
+	if strings.Contains(script, claudePackage+"/") {
+		return true
+	}
+	return filepath.Base(script) == "claude"
+}
+
+// nodeScript returns the script argument when the process is a node runtime.
+// Flags passed to node itself (e.g. --max-old-space-size) are skipped.
+func nodeScript(info ProcessInfo) (string, bool) {
+	if !isNodeExe(info.Exe) && (len(info.Args) == 0 || !isNodeExe(info.Args[0])) {
+		return "", false
+	}
+	for _, arg := range info.Args[min(1, len(info.Args)):] {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		return arg, true
+	}
+	return "", false
+}
+
+// isNodeExe reports whether a path names a node binary (node, node20, nodejs)
+func isNodeExe(path string) bool {
+	base := filepath.Base(path)
+	return base == "nodejs" || (strings.HasPrefix(base, "node") && strings.Trim(base[4:], "0123456789.") == "")
+}
+
+// PatternMatcher is a user-defined matcher built from regular expressions.
+// Both patterns must match when set; Args is matched against argv joined by spaces.
+type PatternMatcher struct {
+	name string
+	exe  *regexp.Regexp
+	args *regexp.Regexp
+}
+
+// NewPatternMatcher compiles a matcher from an executable and/or argument pattern
+func NewPatternMatcher(name, exePattern, argsPattern string) (*PatternMatcher, error) {
+	if exePattern == "" && argsPattern == "" {
+		return nil, fmt.Errorf("matcher %q: needs an exe or args pattern", name)
+	}
+
+	m := &PatternMatcher{name: name}
+	if exePattern != "" {
+		re, err := regexp.Compile(exePattern)
+		if err != nil {
+			return nil, fmt.Errorf("matcher %q: invalid exe pattern: %w", name, err)
+		}
+		m.exe = re
+	}
+	if argsPattern != "" {
+		re, err := regexp.Compile(argsPattern)
+		if err != nil {
+			return nil, fmt.Errorf("matcher %q: invalid args pattern: %w", name, err)
+		}
+		m.args = re
+	}
+	return m, nil
+}
+
+// Name returns the matcher name used in diagnostics
+func (m *PatternMatcher) Name() string {
+	return m.name
+}
+
+// Match reports whether both configured patterns match the process
+func (m *PatternMatcher) Match(info ProcessInfo) bool {
+	if m.exe != nil && !m.exe.MatchString(info.Exe) {
+		return false
+	}
+	if m.args != nil && !m.args.MatchString(strings.Join(info.Args, " ")) {
+		return false
+	}
+	return true
+}
+
+var (
+	matchersMu    sync.RWMutex
+	extraMatchers []ProcessMatcher
+)
+
+// SetExtraMatchers installs user-defined matchers that are consulted before the built-ins
+func SetExtraMatchers(matchers []ProcessMatcher) {
+	matchersMu.Lock()
+	defer matchersMu.Unlock()
+	extraMatchers = matchers
+}
+
+// activeMatchers returns user matchers followed by the built-in set
+func activeMatchers() []ProcessMatcher {
+	matchersMu.RLock()
+	defer matchersMu.RUnlock()
+	return append(append([]ProcessMatcher{}, extraMatchers...), DefaultMatchers()...)
+}
+
+// MatchClaudeProcess returns the name of the first matcher that accepts the process
+func MatchClaudeProcess(info ProcessInfo) (string, bool) {
+	// Skip the desktop app and its helpers
+	if strings.Contains(info.Exe, "Claude.app") {
+		return "", false
+	}
+
+	for _, m := range activeMatchers() {
+		if m.Match(info) {
+			return m.Name(), true
+		}
+	}
+	return "", false
+}
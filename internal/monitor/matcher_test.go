@@ -0,0 +1,64 @@
+package monitor
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// processFixture describes a process as seen by the matchers
+type processFixture struct {
+	Desc string   `json:"desc"`
+	Exe  string   `json:"exe"`
+	Args []string `json:"args"`
+	Want string   `json:"want"` // Expected matcher name, empty for no match
+}
+
+// TestMatchClaudeProcess runs the built-in matchers against launch-style fixtures
+func TestMatchClaudeProcess(t *testing.T) {
+	data, err := os.ReadFile("testdata/processes.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixtures: %v", err)
+	}
+
+	var fixtures []processFixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		t.Fatalf("Failed to parse fixtures: %v", err)
+	}
+
+	for _, f := range fixtures {
+		got, ok := MatchClaudeProcess(ProcessInfo{Exe: f.Exe, Args: f.Args})
+		if f.Want == "" && ok {
+			t.Errorf("%s: matched by %q, want no match", f.Desc, got)
+		} else if f.Want != "" && got != f.Want {
+			t.Errorf("%s: got matcher %q, want %q", f.Desc, got, f.Want)
+		}
+	}
+}
+
+// TestExtraMatchers verifies user-defined matchers take precedence over built-ins
+func TestExtraMatchers(t *testing.T) {
+	custom, err := NewPatternMatcher("acme", `/opt/acme/bin/acme-agent$`, `--profile\s+claude`)
+	if err != nil {
+		t.Fatalf("NewPatternMatcher failed: %v", err)
+	}
+	SetExtraMatchers([]ProcessMatcher{custom})
+	defer SetExtraMatchers(nil)
+
+	info := ProcessInfo{Exe: "/opt/acme/bin/acme-agent", Args: []string{"acme-agent", "--profile", "claude"}}
+	if got, _ := MatchClaudeProcess(info); got != "acme" {
+		t.Errorf("Custom wrapper: got matcher %q, want %q", got, "acme")
+	}
+
+	info.Args = []string{"acme-agent", "--profile", "gpt"}
+	if got, ok := MatchClaudeProcess(info); ok {
+		t.Errorf("Wrapper with other profile matched by %q", got)
+	}
+
+	if _, err := NewPatternMatcher("broken", "(", ""); err == nil {
+		t.Error("Expected error for invalid exe pattern")
+	}
+	if _, err := NewPatternMatcher("empty", "", ""); err == nil {
+		t.Error("Expected error for matcher without patterns")
+	}
+}
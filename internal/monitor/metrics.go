@@ -3,6 +3,7 @@ package monitor
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	gopsutil_process "github.com/shirou/gopsutil/v4/process"
@@ -38,6 +39,79 @@ func RefreshMetrics(proc *types.ClaudeProcess) error {
 	return nil
 }
 
+// cpuSample is the CPU time a process tree had used at a point in time
+type cpuSample struct {
+	seconds float64
+	at      time.Time
+}
+
+var (
+	treeCPUMu      sync.Mutex
+	treeCPUSamples = make(map[int32]cpuSample)
+)
+
+// sampleTreeCPU sets TreeCPU of each process from the CPU time it and its
+// descendants used since the previous call. A process seen for the first
+// time gets the lifetime average of its descendants instead, as tools
+// running now were started recently.
+func sampleTreeCPU(procs []types.ClaudeProcess, all []*gopsutil_process.Process, now time.Time) {
+	if len(procs) == 0 {
+		return
+	}
+	byPID := make(map[int32]*gopsutil_process.Process, len(all))
+	children := make(map[int32][]int32)
+	for _, p := range all {
+		byPID[p.Pid] = p
+		if ppid, err := p.Ppid(); err == nil {
+			children[ppid] = append(children[ppid], p.Pid)
+		}
+	}
+
+	treeCPUMu.Lock()
+	defer treeCPUMu.Unlock()
+	seen := make(map[int32]bool, len(procs))
+	for i := range procs {
+		pid := procs[i].PID
+		seen[pid] = true
+		tree := descendants(pid, children)
+		seconds := 0.0
+		for _, p := range append(tree, pid) {
+			if proc, ok := byPID[p]; ok {
+				if times, err := proc.Times(); err == nil {
+					seconds += times.User + times.System
+				}
+			}
+		}
+
+		prev, ok := treeCPUSamples[pid]
+		treeCPUSamples[pid] = cpuSample{seconds: seconds, at: now}
+		if ok && now.After(prev.at) {
+			procs[i].TreeCPU = max(seconds-prev.seconds, 0) / now.Sub(prev.at).Seconds() * 100
+			continue
+		}
+		for _, p := range tree {
+			if percent, err := byPID[p].CPUPercent(); err == nil {
+				procs[i].TreeCPU += percent
+			}
+		}
+	}
+	for pid := range treeCPUSamples {
+		if !seen[pid] {
+			delete(treeCPUSamples, pid)
+		}
+	}
+}
+
+// descendants returns the PIDs below pid in the process tree
+func descendants(pid int32, children map[int32][]int32) []int32 {
+	var tree []int32
+	for _, child := range children[pid] {
+		tree = append(tree, child)
+		tree = append(tree, descendants(child, children)...)
+	}
+	return tree
+}
+
 // GetProcessByPID retrieves a gopsutil process by PID
 func GetProcessByPID(pid int32) (*gopsutil_process.Process, error) {
 	return gopsutil_process.NewProcess(pid)
@@ -0,0 +1,64 @@
+package monitor
+
+import (
+	"strings"
+	"sync"
+)
+
+// ModelPricing holds token prices in USD per million tokens
+type ModelPricing struct {
+	Input      float64
+	Output     float64
+	CacheWrite float64
+	CacheRead  float64
+}
+
+// DefaultPricing is used for models without an override (Claude Sonnet rates)
+var DefaultPricing = ModelPricing{
+	Input:      3.00,
+	Output:     15.00,
+	CacheWrite: 3.00,
+	CacheRead:  0.30,
+}
+
+var (
+	pricingMu        sync.RWMutex
+	pricingOverrides map[string]ModelPricing
+)
+
+// SetPricingOverrides installs per-model prices keyed by model name prefix
+func SetPricingOverrides(overrides map[string]ModelPricing) {
+	pricingMu.Lock()
+	defer pricingMu.Unlock()
+	pricingOverrides = overrides
+}
+
+// PricingFor returns the prices for model. The override with the longest
+// matching prefix wins; DefaultPricing is used when none matches.
+func PricingFor(model string) ModelPricing {
+	pricingMu.RLock()
+	defer pricingMu.RUnlock()
+
+	best := ""
+	pricing := DefaultPricing
+	for prefix, p := range pricingOverrides {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best, pricing = prefix, p
+		}
+	}
+	return pricing
+}
+
+// MessageCost returns the cost of a message's token usage and the amount
+// saved by reading from the cache instead of sending fresh input
+func MessageCost(msg Message) (cost, savings float64) {
+	p := PricingFor(msg.Model)
+	const perToken = 1.0 / 1_000_000
+
+	cost = float64(msg.InputTokens)*p.Input*perToken +
+		float64(msg.CacheCreation)*p.CacheWrite*perToken +
+		float64(msg.CacheRead)*p.CacheRead*perToken +
+		float64(msg.OutputTokens)*p.Output*perToken
+	savings = float64(msg.CacheRead) * (p.Input - p.CacheRead) * perToken
+	return cost, savings
+}
@@ -0,0 +1,51 @@
+package monitor
+
+import (
+	"math"
+	"testing"
+)
+
+// TestMessageCost tests cost calculation with default and overridden pricing
+func TestMessageCost(t *testing.T) {
+	defer SetPricingOverrides(nil)
+
+	msg := Message{
+		Model:         "claude-opus-4-1-20250805",
+		InputTokens:   1_000_000,
+		OutputTokens:  1_000_000,
+		CacheCreation: 1_000_000,
+		CacheRead:     1_000_000,
+	}
+
+	tests := []struct {
+		name        string
+		overrides   map[string]ModelPricing
+		wantCost    float64
+		wantSavings float64
+	}{
+		{"default", nil, 3 + 15 + 3 + 0.30, 3 - 0.30},
+		{"override", map[string]ModelPricing{
+			"claude-opus-4": {Input: 15, Output: 75, CacheWrite: 18.75, CacheRead: 1.5},
+		}, 15 + 75 + 18.75 + 1.5, 15 - 1.5},
+		{"longest prefix wins", map[string]ModelPricing{
+			"claude":          {Input: 1},
+			"claude-opus-4-1": {Input: 2},
+		}, 2, 2},
+		{"other model", map[string]ModelPricing{
+			"claude-haiku": {Input: 1},
+		}, 3 + 15 + 3 + 0.30, 3 - 0.30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetPricingOverrides(tt.overrides)
+			cost, savings := MessageCost(msg)
+			if math.Abs(cost-tt.wantCost) > 1e-9 {
+				t.Errorf("cost: got %v, want %v", cost, tt.wantCost)
+			}
+			if math.Abs(savings-tt.wantSavings) > 1e-9 {
+				t.Errorf("savings: got %v, want %v", savings, tt.wantSavings)
+			}
+		})
+	}
+}
@@ -2,8 +2,6 @@ package monitor
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -22,7 +20,8 @@ func FindClaudeProcesses(showHelpers bool) ([]types.ClaudeProcess, error) {
 
 	for _, proc := range processes {
 		// Skip processes that aren't Claude
-		if !isClaudeProcess(proc) {
+		launcher, ok := isClaudeProcess(proc)
+		if !ok {
 			continue
 		}
 
@@ -44,33 +43,56 @@ func FindClaudeProcesses(showHelpers bool) ([]types.ClaudeProcess, error) {
 		if err != nil {
 			continue // Skip processes we can't collect metrics for
 		}
+		claudeProc.Launcher = launcher
 
-		// Only include processes that have sessions in ~/.claude
-		if !hasActiveSessions(claudeProc.WorkingDir) {
+		// Only include processes whose working directory maps to a project in a data root
+		root, project := ResolveProject(claudeProc.WorkingDir, processConfigDir(proc))
+		if project.Status == ProjectUnknown {
 			continue
 		}
+		claudeProc.Root = root.Label
+		claudeProc.ProjectDir = project.Dir
+		claudeProc.HasSessions = project.Status == ProjectFound
+		if claudeProc.HasSessions {
+			claudeProc.SessionFile = NewestSession(project.Dir)
+			if live, err := ReadLiveSession(claudeProc.SessionFile); err == nil {
+				claudeProc.ContextPercent = live.ContextPercent
+			}
+		}
 
 		claudeProcesses = append(claudeProcesses, claudeProc)
 	}
 
+	sampleTreeCPU(claudeProcesses, processes, time.Now())
 	return claudeProcesses, nil
 }
 
-// isClaudeProcess checks if a process is a Claude instance
-func isClaudeProcess(proc *process.Process) bool {
+// isClaudeProcess checks if a process is a Claude instance and returns the matcher that accepted it
+// Project resolution in FindClaudeProcesses filters out false positives
+func isClaudeProcess(proc *process.Process) (string, bool) {
 	exe, err := proc.Exe()
 	if err != nil {
-		return false
+		return "", false
 	}
 
-	// Skip the desktop app
-	if strings.Contains(exe, "Claude.app") {
-		return false
-	}
+	// Argument lists are optional: native binaries match on exe alone
+	args, _ := proc.CmdlineSlice()
+
+	return MatchClaudeProcess(ProcessInfo{Exe: exe, Args: args})
+}
 
-	// Match any executable ending with /claude
-	// Session validation via hasActiveSessions() filters out false positives
-	return strings.HasSuffix(exe, "/claude") || exe == "claude"
+// processConfigDir returns CLAUDE_CONFIG_DIR from the process environment, if readable
+func processConfigDir(proc *process.Process) string {
+	env, err := proc.Environ()
+	if err != nil {
+		return ""
+	}
+	for _, kv := range env {
+		if dir, ok := strings.CutPrefix(kv, "CLAUDE_CONFIG_DIR="); ok {
+			return dir
+		}
+	}
+	return ""
 }
 
 // isClaudeHelperProcess checks if a process is a Claude MCP helper
@@ -121,64 +143,3 @@ func collectMetrics(proc *process.Process, isHelper bool) (types.ClaudeProcess,
 		IsHelper:   isHelper,
 	}, nil
 }
-
-// hasActiveSessions checks if a working directory has any active Claude sessions
-func hasActiveSessions(workingDir string) bool {
-	if workingDir == "[Permission Denied]" || workingDir == "" {
-		return false
-	}
-
-	// Get the Claude projects directory
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return false
-	}
-
-	claudeProjectsDir := filepath.Join(home, ".claude", "projects")
-
-	// Check if projects directory exists
-	if _, err := os.Stat(claudeProjectsDir); err != nil {
-		return false
-	}
-
-	// List all directories in projects
-	entries, err := os.ReadDir(claudeProjectsDir)
-	if err != nil {
-		return false
-	}
-
-	// Check each project directory for sessions matching this working directory
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		projectDir := filepath.Join(claudeProjectsDir, entry.Name())
-
-		// Check for sessions-index.json to verify the originalPath matches
-		indexPath := filepath.Join(projectDir, "sessions-index.json")
-		if indexData, err := os.ReadFile(indexPath); err == nil {
-			// Simple check: if the index file contains the working directory path, it's a match
-			if strings.Contains(string(indexData), workingDir) {
-				return true
-			}
-		}
-
-		// Fallback: if index doesn't exist or doesn't match, check for any .jsonl files
-		// This handles cases where sessions-index.json might be missing
-		sessionEntries, err := os.ReadDir(projectDir)
-		if err != nil {
-			continue
-		}
-
-		for _, sessionEntry := range sessionEntries {
-			if !sessionEntry.IsDir() && strings.HasSuffix(sessionEntry.Name(), ".jsonl") {
-				// If we can't verify via index, assume it's valid if sessions exist
-				// This is a conservative approach that might include unrelated sessions
-				continue
-			}
-		}
-	}
-
-	return false
-}
@@ -0,0 +1,170 @@
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// decodedPath is a ProjectPath result. A failed lookup is kept only while
+// the project directory is unchanged: a later index or transcript may resolve it.
+type decodedPath struct {
+	path    string
+	ok      bool
+	modTime time.Time // Of the project directory, for failed lookups
+}
+
+// decodedPaths caches ProjectPath results keyed by project directory
+var decodedPaths sync.Map
+
+// ProjectPath recovers the original working directory of a project directory name.
+// The encoding is lossy (SaaS-Bonn and SaaS/Bonn encode the same), so sources are
+// tried from most to least reliable:
+//  1. originalPath in sessions-index.json
+//  2. cwd fields recorded in the session transcripts
+//  3. a filesystem probe for an existing directory whose encoding matches the name
+//
+// The second return value is false when none of them succeeded; the path is then
+// a best-effort decoding with every dash read as a separator. Successful results
+// are cached for the lifetime of the process, failed ones until the project
+// directory is modified.
+func (r *ProjectResolver) ProjectPath(name string) (string, bool) {
+	dir := filepath.Join(r.projectsDir, name)
+	var modTime time.Time
+	if info, err := os.Stat(dir); err == nil {
+		modTime = info.ModTime()
+	}
+	if cached, ok := decodedPaths.Load(dir); ok {
+		if d := cached.(decodedPath); d.ok || d.modTime.Equal(modTime) {
+			return d.path, d.ok
+		}
+	}
+
+	path := originalPathFromIndex(dir)
+	if path == "" {
+		path = cwdFromSessions(dir, name)
+	}
+	if path == "" {
+		path = probeEncodedPath(name)
+	}
+	if path == "" {
+		// A change in the same mtime tick would go unnoticed, so the failure
+		// of a just-modified directory is not kept
+		if time.Since(modTime) > time.Second {
+			decodedPaths.Store(dir, decodedPath{path: naiveDecode(name), modTime: modTime})
+		}
+		return naiveDecode(name), false
+	}
+
+	decodedPaths.Store(dir, decodedPath{path: path, ok: true})
+	return path, true
+}
+
+// originalPathFromIndex reads originalPath from a project's sessions-index.json
+func originalPathFromIndex(projectDir string) string {
+	index, err := ParseSessionIndex(filepath.Join(projectDir, "sessions-index.json"))
+	if err != nil {
+		return ""
+	}
+	return index.OriginalPath
+}
+
+// cwdFromSessions scans transcripts for a cwd whose encoding matches the project name.
+// A session may change directory, so only a cwd that round-trips to the name counts.
+func cwdFromSessions(projectDir, name string) string {
+	entries, err := os.ReadDir(projectDir)
+	if err != nil {
+		return ""
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jsonl") {
+			continue
+		}
+		if cwd := matchingCwd(filepath.Join(projectDir, entry.Name()), name); cwd != "" {
+			return cwd
+		}
+	}
+	return ""
+}
+
+// cwdScanLines bounds how far into a transcript matchingCwd looks
+const cwdScanLines = 50
+
+// matchingCwd returns the first cwd in a session file that encodes to name
+func matchingCwd(filePath, name string) string {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	buf := make([]byte, 0, 512*1024)  // 512KB buffer
+	scanner.Buffer(buf, 10*1024*1024) // 10MB max token size
+
+	for lineNum := 0; scanner.Scan() && lineNum < cwdScanLines; lineNum++ {
+		var entry struct {
+			Cwd string `json:"cwd"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Cwd == "" {
+			continue
+		}
+		if EncodeProjectPath(entry.Cwd) == name {
+			return entry.Cwd
+		}
+	}
+	return ""
+}
+
+// probeEncodedPath walks the filesystem from / looking for a directory whose
+// encoding equals name. At each level only entries whose encoded name is a
+// prefix of the remaining name are followed, so the search stays narrow.
+func probeEncodedPath(name string) string {
+	if !strings.HasPrefix(name, "-") {
+		return ""
+	}
+	return probeFrom(string(filepath.Separator), name[1:])
+}
+
+// probeFrom matches the remaining encoded name against entries below dir
+func probeFrom(dir, rest string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	// Prefer longer names so "SaaS-Bonn" wins over "SaaS" + "Bonn"
+	sort.Slice(entries, func(i, j int) bool {
+		return len(entries[i].Name()) > len(entries[j].Name())
+	})
+
+	for _, entry := range entries {
+		encoded := EncodeProjectPath(entry.Name())
+		path := filepath.Join(dir, entry.Name())
+
+		if rest != encoded && !strings.HasPrefix(rest, encoded+"-") {
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			continue
+		}
+		if rest == encoded {
+			return path
+		}
+		if found := probeFrom(path, rest[len(encoded)+1:]); found != "" {
+			return found
+		}
+	}
+	return ""
+}
+
+// naiveDecode reads every dash as a path separator
+func naiveDecode(name string) string {
+	return strings.ReplaceAll(name, "-", string(filepath.Separator))
+}
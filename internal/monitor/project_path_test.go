@@ -0,0 +1,139 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeProject creates a project directory with optional index and session content
+func writeProject(t *testing.T, projectsDir, name, index, session string) string {
+	t.Helper()
+	dir := filepath.Join(projectsDir, name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create project dir: %v", err)
+	}
+	if index != "" {
+		if err := os.WriteFile(filepath.Join(dir, "sessions-index.json"), []byte(index), 0644); err != nil {
+			t.Fatalf("Failed to write index: %v", err)
+		}
+	}
+	if session != "" {
+		if err := os.WriteFile(filepath.Join(dir, "s1.jsonl"), []byte(session), 0644); err != nil {
+			t.Fatalf("Failed to write session: %v", err)
+		}
+	}
+	return dir
+}
+
+// TestProjectPathProbe recovers dashed names from the filesystem for Linux and macOS layouts
+func TestProjectPathProbe(t *testing.T) {
+	root := t.TempDir()
+	dirs := mkdirs(t, root,
+		"home/alice/src/SaaS-Bonn/cloud",
+		"Users/thies/Projects/my_app.v2",
+		"Users/thies/.config/nvim",
+	)
+	projectsDir := t.TempDir()
+	resolver := NewProjectResolver(projectsDir)
+
+	for _, want := range dirs {
+		name := EncodeProjectPath(want)
+		writeProject(t, projectsDir, name, "", "")
+
+		got, ok := resolver.ProjectPath(name)
+		if !ok || got != want {
+			t.Errorf("ProjectPath(%q) = %q (%v), want %q", name, got, ok, want)
+		}
+	}
+}
+
+// TestProjectPathSources verifies index and transcript paths win over probing
+func TestProjectPathSources(t *testing.T) {
+	projectsDir := t.TempDir()
+	resolver := NewProjectResolver(projectsDir)
+
+	// Neither path exists locally, so only the recorded values can recover them
+	indexName := "-Users-thies-Projects-SaaS-Bonn-cloud"
+	writeProject(t, projectsDir, indexName,
+		`{"version":1,"entries":[],"originalPath":"/Users/thies/Projects/SaaS-Bonn/cloud"}`, "")
+
+	cwdName := "-home-bob-work-api-gateway"
+	writeProject(t, projectsDir, cwdName, "",
+		`{"type":"user","cwd":"/home/bob/work/api-gateway/sub","message":{"role":"user","content":"x"}}
+{"type":"user","cwd":"/home/bob/work/api-gateway","message":{"role":"user","content":"y"}}
+`)
+
+	tests := []struct {
+		name, want string
+	}{
+		{indexName, "/Users/thies/Projects/SaaS-Bonn/cloud"},
+		{cwdName, "/home/bob/work/api-gateway"},
+	}
+	for _, tt := range tests {
+		got, ok := resolver.ProjectPath(tt.name)
+		if !ok || got != tt.want {
+			t.Errorf("ProjectPath(%q) = %q (%v), want %q", tt.name, got, ok, tt.want)
+		}
+	}
+
+	// Unresolvable names fall back to naive decoding and report failure
+	unknown := "-nonexistent-xyz-abc"
+	writeProject(t, projectsDir, unknown, "", "")
+	if got, ok := resolver.ProjectPath(unknown); ok || got != "/nonexistent/xyz/abc" {
+		t.Errorf("ProjectPath(%q) = %q (%v), want naive fallback", unknown, got, ok)
+	}
+}
+
+// TestProjectPathCache verifies results survive removal of their source
+func TestProjectPathCache(t *testing.T) {
+	projectsDir := t.TempDir()
+	name := "-srv-cached-project"
+	dir := writeProject(t, projectsDir, name,
+		`{"version":1,"entries":[],"originalPath":"/srv/cached-project"}`, "")
+
+	resolver := NewProjectResolver(projectsDir)
+	first, _ := resolver.ProjectPath(name)
+
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("Failed to remove project: %v", err)
+	}
+	if second, ok := resolver.ProjectPath(name); !ok || second != first {
+		t.Errorf("Cached ProjectPath = %q (%v), want %q", second, ok, first)
+	}
+}
+
+// TestProjectPathFailureCache verifies failed lookups are kept until the
+// project directory changes
+func TestProjectPathFailureCache(t *testing.T) {
+	projectsDir := t.TempDir()
+	name := "-nonexistent-promptwatch-failed"
+	dir := writeProject(t, projectsDir, name, "", "")
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(dir, old, old); err != nil {
+		t.Fatalf("Failed to set project time: %v", err)
+	}
+
+	resolver := NewProjectResolver(projectsDir)
+	if _, ok := resolver.ProjectPath(name); ok {
+		t.Fatal("Unresolvable project resolved")
+	}
+
+	// An index written without touching the directory's mtime is not seen
+	writeProject(t, projectsDir, name, `{"version":1,"entries":[],"originalPath":"/srv/failed"}`, "")
+	if err := os.Chtimes(dir, old, old); err != nil {
+		t.Fatalf("Failed to set project time: %v", err)
+	}
+	if _, ok := resolver.ProjectPath(name); ok {
+		t.Error("Failed lookup was not cached")
+	}
+
+	now := time.Now()
+	if err := os.Chtimes(dir, now, now); err != nil {
+		t.Fatalf("Failed to set project time: %v", err)
+	}
+	if path, ok := resolver.ProjectPath(name); !ok || path != "/srv/failed" {
+		t.Errorf("ProjectPath after change = %q (%v), want /srv/failed", path, ok)
+	}
+}
@@ -0,0 +1,297 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProjectStatus describes what is known about the project of a working directory
+type ProjectStatus int
+
+const (
+	// ProjectUnknown means the working directory or the projects root could not be inspected
+	ProjectUnknown ProjectStatus = iota
+	// ProjectNoSessions means the directory is usable but Claude has not written a transcript for it yet
+	ProjectNoSessions
+	// ProjectFound means a project directory with at least one session exists
+	ProjectFound
+)
+
+// String returns a short label for display
+func (s ProjectStatus) String() string {
+	switch s {
+	case ProjectFound:
+		return "found"
+	case ProjectNoSessions:
+		return "no sessions"
+	default:
+		return "unknown"
+	}
+}
+
+// ProjectResolution is the result of mapping a working directory to its project
+type ProjectResolution struct {
+	Status     ProjectStatus
+	Dir        string // Project directory under the projects root (empty if none exists)
+	WorkingDir string // Directory the project was recorded for (may be a parent of the input)
+}
+
+// ProjectResolver maps working directories to project directories in a projects directory
+type ProjectResolver struct {
+	projectsDir string
+}
+
+// NewProjectResolver creates a resolver for the given projects directory
+func NewProjectResolver(projectsDir string) *ProjectResolver {
+	return &ProjectResolver{projectsDir: projectsDir}
+}
+
+// ProjectsDir returns the projects directory this resolver looks in
+func (r *ProjectResolver) ProjectsDir() string {
+	return r.projectsDir
+}
+
+// projectNames indexes the project directory names of a projects directory
+type projectNames struct {
+	modTime time.Time // Of the projects directory when it was listed
+	exact   map[string]string
+	folded  map[string]string // Lowercased name -> name
+
+	originalsMu sync.Mutex
+	originals   map[string]originalPath // Name -> originalPath from sessions-index.json
+}
+
+// originalPath is the originalPath of a project's sessions index with the
+// index's modification time it was read at
+type originalPath struct {
+	modTime time.Time
+	path    string
+}
+
+var (
+	projectNamesMu    sync.Mutex
+	projectNamesCache = make(map[string]*projectNames)
+)
+
+// names lists the projects directory, reusing the last listing until the
+// directory's mtime changes (a project was added or removed)
+func (r *ProjectResolver) names() (*projectNames, error) {
+	listedAt := time.Now()
+	info, err := os.Stat(r.projectsDir)
+	if err != nil {
+		return nil, err
+	}
+	projectNamesMu.Lock()
+	cached, ok := projectNamesCache[r.projectsDir]
+	projectNamesMu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) {
+		return cached, nil
+	}
+
+	entries, err := os.ReadDir(r.projectsDir)
+	if err != nil {
+		return nil, err
+	}
+	names := &projectNames{
+		modTime: info.ModTime(),
+		exact:   make(map[string]string, len(entries)),
+		folded:  make(map[string]string, len(entries)),
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		names.exact[entry.Name()] = entry.Name()
+		names.folded[strings.ToLower(entry.Name())] = entry.Name()
+	}
+	// A change in the same mtime tick as the listing would go unnoticed, so a
+	// listing of a just-modified directory is not reused
+	if !info.ModTime().Before(listedAt.Add(-time.Second)) {
+		names.modTime = time.Time{}
+	}
+
+	projectNamesMu.Lock()
+	projectNamesCache[r.projectsDir] = names
+	projectNamesMu.Unlock()
+	return names, nil
+}
+
+// originalPaths returns the originalPath recorded for each project. An index
+// is read again only when it has been modified since it was last read.
+func (r *ProjectResolver) originalPaths(names *projectNames) map[string]string {
+	names.originalsMu.Lock()
+	defer names.originalsMu.Unlock()
+	if names.originals == nil {
+		names.originals = make(map[string]originalPath, len(names.exact))
+	}
+
+	now := time.Now()
+	paths := make(map[string]string)
+	for name := range names.exact {
+		indexPath := filepath.Join(r.projectsDir, name, "sessions-index.json")
+		var modTime time.Time
+		if info, err := os.Stat(indexPath); err == nil {
+			modTime = info.ModTime()
+		}
+		cached, ok := names.originals[name]
+		if !ok || !cached.modTime.Equal(modTime) {
+			cached = originalPath{modTime: modTime}
+			if index, err := ParseSessionIndex(indexPath); err == nil {
+				cached.path = index.OriginalPath
+			}
+			// An edit in the same mtime tick would go unnoticed, so a
+			// just-modified index is read again next time
+			if !modTime.Before(now.Add(-time.Second)) {
+				cached.modTime = time.Time{}
+			}
+			names.originals[name] = cached
+		}
+		if cached.path != "" {
+			paths[name] = cached.path
+		}
+	}
+	return paths
+}
+
+// Resolve finds the project directory for a working directory.
+// The nearest recorded ancestor wins, so a process that changed into a
+// subdirectory still maps to the project it was started in. The walk stops
+// below the home directory and the data root's parent: a project recorded
+// for $HOME does not own every directory beneath it. Symlinked working
+// directories are tried in both their given and resolved form, and names are
+// compared case-insensitively when no exact match exists.
+func (r *ProjectResolver) Resolve(workingDir string) ProjectResolution {
+	// Placeholders like "[Permission Denied]" carry no path
+	if workingDir == "" || strings.HasPrefix(workingDir, "[") {
+		return ProjectResolution{Status: ProjectUnknown}
+	}
+	if abs, err := filepath.Abs(workingDir); err == nil {
+		workingDir = abs
+	}
+
+	names, err := r.names()
+	if err != nil {
+		return ProjectResolution{Status: ProjectUnknown}
+	}
+
+	stops := r.walkStops()
+	for _, candidate := range workingDirCandidates(workingDir) {
+		for dir := candidate; ; dir = filepath.Dir(dir) {
+			if dir != candidate && isWalkStop(dir, stops) {
+				break
+			}
+			encoded := EncodeProjectPath(dir)
+			name, ok := names.exact[encoded]
+			if !ok {
+				name, ok = names.folded[strings.ToLower(encoded)]
+			}
+			if ok {
+				return r.resolution(name, dir)
+			}
+		}
+	}
+
+	// Older layouts may not follow the encoding; fall back to the recorded original path
+	originals := r.originalPaths(names)
+	for _, candidate := range workingDirCandidates(workingDir) {
+		for name, original := range originals {
+			if samePath(original, candidate) {
+				return r.resolution(name, candidate)
+			}
+		}
+	}
+
+	return ProjectResolution{Status: ProjectNoSessions, WorkingDir: workingDir}
+}
+
+// walkStops returns the directories the ancestor walk must not reach: the
+// home directory and the parent of the data root, in given and resolved form
+func (r *ProjectResolver) walkStops() []string {
+	stops := []string{filepath.Dir(filepath.Dir(r.projectsDir))}
+	if home, err := os.UserHomeDir(); err == nil {
+		stops = append(stops, home)
+	}
+	for _, dir := range stops {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil && resolved != dir {
+			stops = append(stops, resolved)
+		}
+	}
+	return stops
+}
+
+// isWalkStop reports whether an ancestor is the filesystem root or one of stops
+func isWalkStop(dir string, stops []string) bool {
+	if dir == filepath.Dir(dir) {
+		return true
+	}
+	for _, stop := range stops {
+		if strings.EqualFold(dir, filepath.Clean(stop)) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolution builds the result for a matched project directory name
+func (r *ProjectResolver) resolution(name, workingDir string) ProjectResolution {
+	dir := filepath.Join(r.projectsDir, name)
+	status := ProjectNoSessions
+	if hasSessionFiles(dir) {
+		status = ProjectFound
+	}
+	return ProjectResolution{Status: status, Dir: dir, WorkingDir: workingDir}
+}
+
+// workingDirCandidates returns the cleaned working directory and, if different, its symlink-resolved form
+func workingDirCandidates(workingDir string) []string {
+	cleaned := filepath.Clean(workingDir)
+	candidates := []string{cleaned}
+	if resolved, err := filepath.EvalSymlinks(cleaned); err == nil && resolved != cleaned {
+		candidates = append(candidates, resolved)
+	}
+	return candidates
+}
+
+// samePath compares two paths after symlink resolution, ignoring case differences
+func samePath(a, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return strings.EqualFold(filepath.Clean(a), filepath.Clean(b))
+}
+
+// hasSessionFiles reports whether a project directory contains any .jsonl transcripts
+func hasSessionFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".jsonl") {
+			return true
+		}
+	}
+	return false
+}
+
+// EncodeProjectPath converts a path to the directory name Claude uses under projects.
+// Every character other than ASCII letters and digits becomes a dash:
+// /Users/thies/Projects/my_app.v2 -> -Users-thies-Projects-my-app-v2
+func EncodeProjectPath(path string) string {
+	var b strings.Builder
+	b.Grow(len(path))
+	for _, c := range path {
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+			b.WriteRune(c)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,211 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClaudeHome builds a ~/.claude/projects tree for the given working directories.
+// Each project gets one session file unless listed in empty.
+func fakeClaudeHome(t *testing.T, projects []string, empty []string) string {
+	t.Helper()
+	projectsDir := filepath.Join(t.TempDir(), ".claude", "projects")
+
+	for _, wd := range projects {
+		dir := filepath.Join(projectsDir, EncodeProjectPath(wd))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create project dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "s1.jsonl"), []byte("{}\n"), 0644); err != nil {
+			t.Fatalf("Failed to write session file: %v", err)
+		}
+	}
+	for _, wd := range empty {
+		if err := os.MkdirAll(filepath.Join(projectsDir, EncodeProjectPath(wd)), 0755); err != nil {
+			t.Fatalf("Failed to create project dir: %v", err)
+		}
+	}
+	return projectsDir
+}
+
+// mkdirs creates directories below root and returns their absolute paths
+func mkdirs(t *testing.T, root string, dirs ...string) []string {
+	t.Helper()
+	var paths []string
+	for _, d := range dirs {
+		p := filepath.Join(root, d)
+		if err := os.MkdirAll(p, 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", p, err)
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// TestEncodeProjectPath verifies the dash encoding of non-alphanumeric characters
+func TestEncodeProjectPath(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		{"/Users/thies/Projects/foo", "-Users-thies-Projects-foo"},
+		{"/home/thies/SaaS-Bonn/cloud", "-home-thies-SaaS-Bonn-cloud"},
+		{"/home/thies/my_app.v2", "-home-thies-my-app-v2"},
+		{"/home/thies/with space", "-home-thies-with-space"},
+	}
+	for _, tt := range tests {
+		if got := EncodeProjectPath(tt.path); got != tt.want {
+			t.Errorf("EncodeProjectPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestProjectResolver resolves working directories against a fake ~/.claude tree
+func TestProjectResolver(t *testing.T) {
+	work := t.TempDir()
+	dirs := mkdirs(t, work, "repo", "repo/internal/ui", "my_app.v2", "Mixed", "fresh", "untracked")
+	repo, nested, dotted, mixed, fresh, untracked := dirs[0], dirs[1], dirs[2], dirs[3], dirs[4], dirs[5]
+
+	link := filepath.Join(work, "link")
+	if err := os.Symlink(repo, link); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	// Mixed is recorded with different casing, as on case-insensitive filesystems
+	projectsDir := fakeClaudeHome(t,
+		[]string{repo, dotted, strings.ToLower(mixed)},
+		[]string{fresh},
+	)
+	resolver := NewProjectResolver(projectsDir)
+
+	tests := []struct {
+		name       string
+		workingDir string
+		status     ProjectStatus
+		projectFor string // Working dir whose encoded project should be returned
+	}{
+		{"exact", repo, ProjectFound, repo},
+		{"dash encoding", dotted, ProjectFound, dotted},
+		{"nested directory", nested, ProjectFound, repo},
+		{"symlink", link, ProjectFound, repo},
+		{"case difference", mixed, ProjectFound, strings.ToLower(mixed)},
+		{"project without sessions", fresh, ProjectNoSessions, fresh},
+		{"no project yet", untracked, ProjectNoSessions, ""},
+		{"permission denied", "[Permission Denied]", ProjectUnknown, ""},
+		{"empty", "", ProjectUnknown, ""},
+	}
+
+	for _, tt := range tests {
+		got := resolver.Resolve(tt.workingDir)
+		if got.Status != tt.status {
+			t.Errorf("%s: status %v, want %v", tt.name, got.Status, tt.status)
+		}
+		wantDir := ""
+		if tt.projectFor != "" {
+			wantDir = filepath.Join(projectsDir, EncodeProjectPath(tt.projectFor))
+		}
+		if got.Dir != wantDir {
+			t.Errorf("%s: dir %q, want %q", tt.name, got.Dir, wantDir)
+		}
+	}
+}
+
+// TestProjectResolverOriginalPath falls back to originalPath when names don't follow the encoding
+func TestProjectResolverOriginalPath(t *testing.T) {
+	repo := mkdirs(t, t.TempDir(), "legacy")[0]
+	projectsDir := filepath.Join(t.TempDir(), "projects")
+	dir := filepath.Join(projectsDir, "legacy-project")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create project dir: %v", err)
+	}
+	index := `{"version":1,"entries":[],"originalPath":"` + repo + `"}`
+	if err := os.WriteFile(filepath.Join(dir, "sessions-index.json"), []byte(index), 0644); err != nil {
+		t.Fatalf("Failed to write index: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "s1.jsonl"), []byte("{}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write session: %v", err)
+	}
+
+	// Settled times, so the listing and index are cached
+	old := time.Now().Add(-time.Hour)
+	for _, path := range []string{filepath.Join(dir, "sessions-index.json"), projectsDir} {
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatalf("Failed to set time: %v", err)
+		}
+	}
+
+	resolver := NewProjectResolver(projectsDir)
+	got := resolver.Resolve(repo)
+	if got.Status != ProjectFound || got.Dir != dir {
+		t.Errorf("Resolve(%q) = %+v, want found in %q", repo, got, dir)
+	}
+
+	// An edited index is read again although no project was added or removed
+	moved := mkdirs(t, t.TempDir(), "moved")[0]
+	index = `{"version":1,"entries":[],"originalPath":"` + moved + `"}`
+	if err := os.WriteFile(filepath.Join(dir, "sessions-index.json"), []byte(index), 0644); err != nil {
+		t.Fatalf("Failed to write index: %v", err)
+	}
+	edited := old.Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, "sessions-index.json"), edited, edited); err != nil {
+		t.Fatalf("Failed to set time: %v", err)
+	}
+	if err := os.Chtimes(projectsDir, old, old); err != nil {
+		t.Fatalf("Failed to set time: %v", err)
+	}
+	if got := resolver.Resolve(moved); got.Dir != dir {
+		t.Errorf("Edited index not read: Resolve(%q) = %+v", moved, got)
+	}
+}
+
+// TestProjectResolverMissingRoot reports unknown when ~/.claude/projects does not exist
+func TestProjectResolverMissingRoot(t *testing.T) {
+	resolver := NewProjectResolver(filepath.Join(t.TempDir(), "missing"))
+	if got := resolver.Resolve(t.TempDir()); got.Status != ProjectUnknown {
+		t.Errorf("Status %v, want %v", got.Status, ProjectUnknown)
+	}
+}
+
+// TestProjectResolverStopsAtHome keeps a project recorded for $HOME from
+// claiming every directory below it
+func TestProjectResolverStopsAtHome(t *testing.T) {
+	work := t.TempDir()
+	dirs := mkdirs(t, work, "home", "home/Projects/new", "home/Projects/repo/cmd")
+	home, fresh, nested := dirs[0], dirs[1], dirs[2]
+	repo := filepath.Dir(nested)
+	t.Setenv("HOME", home)
+
+	projectsDir := fakeClaudeHome(t, []string{home, repo}, nil)
+	resolver := NewProjectResolver(projectsDir)
+
+	tests := []struct {
+		name       string
+		workingDir string
+		status     ProjectStatus
+		projectFor string
+	}{
+		{"home itself", home, ProjectFound, home},
+		{"new directory below home", fresh, ProjectNoSessions, ""},
+		{"nested below a project", nested, ProjectFound, repo},
+	}
+	for _, tt := range tests {
+		got := resolver.Resolve(tt.workingDir)
+		wantDir := ""
+		if tt.projectFor != "" {
+			wantDir = filepath.Join(projectsDir, EncodeProjectPath(tt.projectFor))
+		}
+		if got.Status != tt.status || got.Dir != wantDir {
+			t.Errorf("%s: %+v, want status %v in %q", tt.name, got, tt.status, wantDir)
+		}
+	}
+
+	// A project created later is picked up
+	if err := os.MkdirAll(filepath.Join(projectsDir, EncodeProjectPath(fresh)), 0755); err != nil {
+		t.Fatalf("Failed to create project dir: %v", err)
+	}
+	if got := resolver.Resolve(fresh); got.Dir != filepath.Join(projectsDir, EncodeProjectPath(fresh)) {
+		t.Errorf("New project not found: %+v", got)
+	}
+}
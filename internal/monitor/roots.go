@@ -0,0 +1,156 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DataRoot is a Claude data directory (the one containing projects/), e.g. ~/.claude
+type DataRoot struct {
+	Label string // Short name shown next to entities from this root
+	Dir   string
+}
+
+// ProjectsDir returns the projects directory inside the root
+func (r DataRoot) ProjectsDir() string {
+	return filepath.Join(r.Dir, "projects")
+}
+
+// Resolver returns a project resolver for the root
+func (r DataRoot) Resolver() *ProjectResolver {
+	return NewProjectResolver(r.ProjectsDir())
+}
+
+var (
+	rootsMu sync.RWMutex
+	roots   []DataRoot
+)
+
+// NewDataRoot creates a root for dir, deriving a label when none is given
+func NewDataRoot(label, dir string) DataRoot {
+	dir = filepath.Clean(expandHome(dir))
+	if label == "" {
+		label = rootLabel(dir)
+	}
+	return DataRoot{Label: label, Dir: dir}
+}
+
+// DefaultDataRoots returns $CLAUDE_CONFIG_DIR when set and ~/.claude when it exists
+func DefaultDataRoots() []DataRoot {
+	var defaults []DataRoot
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		defaults = append(defaults, NewDataRoot("", dir))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dir := filepath.Join(home, ".claude")
+		if _, err := os.Stat(dir); err == nil || len(defaults) == 0 {
+			defaults = appendRoot(defaults, NewDataRoot("", dir))
+		}
+	}
+	return defaults
+}
+
+// SetDataRoots replaces the roots all lookups use. An empty list restores the defaults.
+func SetDataRoots(list []DataRoot) {
+	rootsMu.Lock()
+	defer rootsMu.Unlock()
+	roots = nil
+	for _, r := range list {
+		roots = appendRoot(roots, r)
+	}
+}
+
+// DataRoots returns the configured roots, or the defaults when none are configured
+func DataRoots() []DataRoot {
+	rootsMu.RLock()
+	defer rootsMu.RUnlock()
+	if len(roots) == 0 {
+		return DefaultDataRoots()
+	}
+	return append([]DataRoot{}, roots...)
+}
+
+// RootForPath returns the root containing path, if any
+func RootForPath(path string) (DataRoot, bool) {
+	for _, r := range DataRoots() {
+		if path == r.Dir || strings.HasPrefix(path, r.Dir+string(filepath.Separator)) {
+			return r, true
+		}
+	}
+	return DataRoot{}, false
+}
+
+// ResolveProject resolves a working directory against every root.
+// A root named by preferDir (the process's CLAUDE_CONFIG_DIR) is tried first
+// and is included even when it is not configured. A project with sessions
+// beats an empty one; otherwise the first root that knows the directory wins.
+func ResolveProject(workingDir, preferDir string) (DataRoot, ProjectResolution) {
+	candidates := DataRoots()
+	if preferDir != "" {
+		preferred := NewDataRoot("", preferDir)
+		for _, r := range candidates {
+			if r.Dir == preferred.Dir {
+				preferred = r // Keep the configured label
+				break
+			}
+		}
+		candidates = appendRoot([]DataRoot{preferred}, candidates...)
+	}
+
+	var fallbackRoot DataRoot
+	fallback := ProjectResolution{Status: ProjectUnknown}
+	for _, root := range candidates {
+		res := root.Resolver().Resolve(workingDir)
+		switch {
+		case res.Status == ProjectFound:
+			return root, res
+		case res.Status == ProjectNoSessions && (fallback.Status == ProjectUnknown || fallback.Dir == "" && res.Dir != ""):
+			fallbackRoot, fallback = root, res
+		}
+	}
+	return fallbackRoot, fallback
+}
+
+// appendRoot adds roots to list, skipping directories already present
+func appendRoot(list []DataRoot, add ...DataRoot) []DataRoot {
+	for _, r := range add {
+		duplicate := false
+		for _, existing := range list {
+			if existing.Dir == r.Dir {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			list = append(list, r)
+		}
+	}
+	return list
+}
+
+// rootLabel derives a short label: the owning user for <home>/<user>/.claude,
+// "~" for the current user's ~/.claude, otherwise the directory name
+func rootLabel(dir string) string {
+	if home, err := os.UserHomeDir(); err == nil && dir == filepath.Join(home, ".claude") {
+		return "~"
+	}
+	base := filepath.Base(dir)
+	if base == ".claude" {
+		return filepath.Base(filepath.Dir(dir))
+	}
+	return base
+}
+
+// expandHome replaces a leading ~ with the current user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
@@ -0,0 +1,54 @@
+package monitor
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestResolveProjectAcrossRoots verifies projects are found in any root and labelled
+func TestResolveProjectAcrossRoots(t *testing.T) {
+	work := t.TempDir()
+	dirs := mkdirs(t, work, "shared", "alice-only", "fresh")
+	shared, aliceOnly, fresh := dirs[0], dirs[1], dirs[2]
+
+	// Each fake home holds its projects below <home>/.claude/projects
+	bobProjects := fakeClaudeHome(t, []string{shared}, []string{fresh})
+	aliceProjects := fakeClaudeHome(t, []string{aliceOnly}, nil)
+	bob := NewDataRoot("bob", filepath.Dir(bobProjects))
+	alice := NewDataRoot("", filepath.Dir(aliceProjects))
+
+	SetDataRoots([]DataRoot{bob, alice})
+	defer SetDataRoots(nil)
+
+	if alice.Label != filepath.Base(filepath.Dir(alice.Dir)) {
+		t.Errorf("Derived label %q, want owning directory name", alice.Label)
+	}
+
+	tests := []struct {
+		name      string
+		dir       string
+		prefer    string
+		wantRoot  string
+		wantState ProjectStatus
+	}{
+		{"first root", shared, "", "bob", ProjectFound},
+		{"second root", aliceOnly, "", alice.Label, ProjectFound},
+		{"empty project", fresh, "", "bob", ProjectNoSessions},
+		{"preferred root without project", shared, alice.Dir, "bob", ProjectFound},
+	}
+
+	for _, tt := range tests {
+		root, res := ResolveProject(tt.dir, tt.prefer)
+		if root.Label != tt.wantRoot || res.Status != tt.wantState {
+			t.Errorf("%s: got root %q status %v, want %q %v", tt.name, root.Label, res.Status, tt.wantRoot, tt.wantState)
+		}
+	}
+
+	sessions, err := FindSessionsForDirectory(aliceOnly)
+	if err != nil {
+		t.Fatalf("FindSessionsForDirectory failed: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Root != alice.Label {
+		t.Errorf("Sessions %+v, want one from root %q", sessions, alice.Label)
+	}
+}
@@ -0,0 +1,252 @@
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/thieso2/promptwatch/internal/types"
+)
+
+// ProjectSample is the resource use of one working directory at a sample time
+type ProjectSample struct {
+	Dir      string  `json:"d"`
+	Agents   int     `json:"n"` // Main instances; MCP helpers only add CPU and memory
+	CPU      float64 `json:"c"` // Percent, summed over the project's processes
+	MemoryMB float64 `json:"m"` // Resident memory, summed over the project's processes
+}
+
+// Sample is the resource use of every running project at one time
+type Sample struct {
+	Time     time.Time
+	Projects []ProjectSample // Sorted by directory
+}
+
+// sampleLine is the stored form of a Sample, one JSON object per line
+type sampleLine struct {
+	Time     int64           `json:"t"` // Unix seconds
+	Projects []ProjectSample `json:"p,omitempty"`
+}
+
+// NewSample sums the processes' resource use per working directory
+func NewSample(processes []types.ClaudeProcess, now time.Time) Sample {
+	byDir := make(map[string]*ProjectSample)
+	for _, proc := range processes {
+		p, ok := byDir[proc.WorkingDir]
+		if !ok {
+			p = &ProjectSample{Dir: proc.WorkingDir}
+			byDir[proc.WorkingDir] = p
+		}
+		if !proc.IsHelper {
+			p.Agents++
+		}
+		p.CPU += proc.CPUPercent
+		p.MemoryMB += proc.MemoryMB
+	}
+
+	sample := Sample{Time: now.Truncate(time.Second)}
+	for _, p := range byDir {
+		// Rounding keeps the file small; finer detail is noise anyway
+		p.CPU = math.Round(p.CPU*10) / 10
+		p.MemoryMB = math.Round(p.MemoryMB)
+		sample.Projects = append(sample.Projects, *p)
+	}
+	sort.Slice(sample.Projects, func(i, j int) bool { return sample.Projects[i].Dir < sample.Projects[j].Dir })
+	return sample
+}
+
+// SampleStore is an append-only file of samples. When appending would grow
+// the file past MaxSize it is rotated to Path.1, Path.1 to Path.2 and so on,
+// keeping Keep rotated files.
+type SampleStore struct {
+	Path    string
+	MaxSize int64 // Bytes
+	Keep    int
+}
+
+// Append writes a sample, rotating the file first when it is full
+func (s SampleStore) Append(sample Sample) error {
+	line, err := json.Marshal(sampleLine{Time: sample.Time.Unix(), Projects: sample.Projects})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return err
+	}
+	if info, err := os.Stat(s.Path); err == nil && info.Size()+int64(len(line)) > s.MaxSize {
+		if err := s.rotate(); err != nil {
+			return fmt.Errorf("cannot rotate %s: %w", s.Path, err)
+		}
+	}
+
+	file, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(line); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// rotate shifts the current and rotated files up by one, dropping the oldest
+func (s SampleStore) rotate() error {
+	if err := os.Remove(s.rotated(s.Keep)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := s.Keep - 1; i >= 0; i-- {
+		if err := os.Rename(s.rotated(i), s.rotated(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// rotated returns the path of the i-th rotated file; 0 is the current file
+func (s SampleStore) rotated(i int) string {
+	if i == 0 {
+		return s.Path
+	}
+	return fmt.Sprintf("%s.%d", s.Path, i)
+}
+
+// Load reads the samples taken at or after since from the rotated and
+// current files, oldest first. Missing files and unreadable lines are skipped.
+func (s SampleStore) Load(since time.Time) ([]Sample, error) {
+	var samples []Sample
+	for i := s.Keep; i >= 0; i-- {
+		file, err := os.Open(s.rotated(i))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var line sampleLine
+			if json.Unmarshal(scanner.Bytes(), &line) != nil {
+				continue // A line cut off by a crash
+			}
+			t := time.Unix(line.Time, 0)
+			if t.Before(since) {
+				continue
+			}
+			samples = append(samples, Sample{Time: t, Projects: line.Projects})
+		}
+		err = scanner.Err()
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return samples, nil
+}
+
+// ProjectUsage is the resource use of one project over a time range, as
+// peaks per bucket and as a whole
+type ProjectUsage struct {
+	Dir    string // "" for the total of all projects
+	Agents []float64
+	CPU    []float64
+	Memory []float64 // MB
+
+	PeakAgents int
+	AvgAgents  float64
+	PeakCPU    float64
+	AvgCPU     float64
+	PeakMemory float64
+	AvgMemory  float64
+}
+
+// UsageHistory splits a time range into equal buckets and holds the resource
+// use of every project seen in it
+type UsageHistory struct {
+	Start    time.Time
+	End      time.Time
+	Samples  int
+	Covered  []bool         // Buckets with at least one sample
+	Total    ProjectUsage   // All projects summed per sample
+	Projects []ProjectUsage // Sorted by peak memory, largest first
+}
+
+// SummarizeSamples buckets the samples between start and end. A project
+// missing from a sample counts as using nothing at that time, so averages
+// cover the whole time the recorder ran.
+func SummarizeSamples(samples []Sample, start, end time.Time, buckets int) UsageHistory {
+	h := UsageHistory{Start: start, End: end, Covered: make([]bool, buckets)}
+	newUsage := func(dir string) *ProjectUsage {
+		return &ProjectUsage{
+			Dir:    dir,
+			Agents: make([]float64, buckets),
+			CPU:    make([]float64, buckets),
+			Memory: make([]float64, buckets),
+		}
+	}
+	add := func(u *ProjectUsage, bucket int, p ProjectSample) {
+		u.Agents[bucket] = max(u.Agents[bucket], float64(p.Agents))
+		u.CPU[bucket] = max(u.CPU[bucket], p.CPU)
+		u.Memory[bucket] = max(u.Memory[bucket], p.MemoryMB)
+		u.PeakAgents = max(u.PeakAgents, p.Agents)
+		u.PeakCPU = max(u.PeakCPU, p.CPU)
+		u.PeakMemory = max(u.PeakMemory, p.MemoryMB)
+		u.AvgAgents += float64(p.Agents)
+		u.AvgCPU += p.CPU
+		u.AvgMemory += p.MemoryMB
+	}
+
+	total := newUsage("")
+	projects := make(map[string]*ProjectUsage)
+	span := end.Sub(start)
+	for _, sample := range samples {
+		if sample.Time.Before(start) || !sample.Time.Before(end) || span <= 0 {
+			continue
+		}
+		bucket := min(int(sample.Time.Sub(start)*time.Duration(buckets)/span), buckets-1)
+		h.Covered[bucket] = true
+		h.Samples++
+
+		var sum ProjectSample
+		for _, p := range sample.Projects {
+			u, ok := projects[p.Dir]
+			if !ok {
+				u = newUsage(p.Dir)
+				projects[p.Dir] = u
+			}
+			add(u, bucket, p)
+			sum.Agents += p.Agents
+			sum.CPU += p.CPU
+			sum.MemoryMB += p.MemoryMB
+		}
+		add(total, bucket, sum)
+	}
+
+	average := func(u *ProjectUsage) ProjectUsage {
+		if h.Samples > 0 {
+			u.AvgAgents /= float64(h.Samples)
+			u.AvgCPU /= float64(h.Samples)
+			u.AvgMemory /= float64(h.Samples)
+		}
+		return *u
+	}
+	h.Total = average(total)
+	for _, u := range projects {
+		h.Projects = append(h.Projects, average(u))
+	}
+	sort.Slice(h.Projects, func(i, j int) bool {
+		if h.Projects[i].PeakMemory != h.Projects[j].PeakMemory {
+			return h.Projects[i].PeakMemory > h.Projects[j].PeakMemory
+		}
+		return h.Projects[i].Dir < h.Projects[j].Dir
+	})
+	return h
+}
@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thieso2/promptwatch/internal/types"
+)
+
+// TestNewSample tests summing processes per working directory
+func TestNewSample(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 500, time.UTC)
+	sample := NewSample([]types.ClaudeProcess{
+		{WorkingDir: "/work/web", CPUPercent: 10.04, MemoryMB: 300.4},
+		{WorkingDir: "/work/api", CPUPercent: 50, MemoryMB: 500},
+		{WorkingDir: "/work/api", CPUPercent: 5, MemoryMB: 80, IsHelper: true},
+		{WorkingDir: "/work/api", CPUPercent: 20, MemoryMB: 400},
+	}, now)
+
+	if !sample.Time.Equal(now.Truncate(time.Second)) {
+		t.Errorf("Time: got %v, want %v", sample.Time, now.Truncate(time.Second))
+	}
+	expected := []ProjectSample{
+		{Dir: "/work/api", Agents: 2, CPU: 75, MemoryMB: 980},
+		{Dir: "/work/web", Agents: 1, CPU: 10, MemoryMB: 300},
+	}
+	if len(sample.Projects) != len(expected) {
+		t.Fatalf("Projects: got %+v, want %+v", sample.Projects, expected)
+	}
+	for i, want := range expected {
+		if sample.Projects[i] != want {
+			t.Errorf("Project %d: got %+v, want %+v", i, sample.Projects[i], want)
+		}
+	}
+}
+
+// TestSampleStoreRotation tests rotating the sample file and reading across rotations
+func TestSampleStoreRotation(t *testing.T) {
+	dir := t.TempDir()
+	store := SampleStore{Path: filepath.Join(dir, "state", "samples.jsonl"), MaxSize: 200, Keep: 2}
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 20; i++ {
+		sample := Sample{Time: base.Add(time.Duration(i) * time.Minute), Projects: []ProjectSample{
+			{Dir: "/work/api", Agents: 1, CPU: float64(i), MemoryMB: 100},
+		}}
+		if err := store.Append(sample); err != nil {
+			t.Fatalf("Failed to append sample %d: %v", i, err)
+		}
+	}
+
+	for i := 0; i <= store.Keep; i++ {
+		info, err := os.Stat(store.rotated(i))
+		if err != nil {
+			t.Fatalf("Failed to stat rotated file %d: %v", i, err)
+		}
+		if info.Size() > store.MaxSize {
+			t.Errorf("File %d has %d bytes, over the %d limit", i, info.Size(), store.MaxSize)
+		}
+	}
+	if _, err := os.Stat(store.rotated(store.Keep + 1)); !os.IsNotExist(err) {
+		t.Errorf("Rotated file beyond keep exists")
+	}
+
+	samples, err := store.Load(time.Time{})
+	if err != nil {
+		t.Fatalf("Failed to load samples: %v", err)
+	}
+	if len(samples) == 0 || len(samples) >= 20 {
+		t.Fatalf("Loaded %d samples, want the newest few of 20", len(samples))
+	}
+	for i, s := range samples {
+		if i > 0 && !s.Time.After(samples[i-1].Time) {
+			t.Errorf("Samples out of order at %d", i)
+		}
+	}
+	if last := samples[len(samples)-1]; last.Projects[0].CPU != 19 {
+		t.Errorf("Last sample: got %+v, want the 20th", last)
+	}
+
+	recent, err := store.Load(base.Add(18 * time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to load samples: %v", err)
+	}
+	if len(recent) != 2 {
+		t.Errorf("Samples since minute 18: got %d, want 2", len(recent))
+	}
+}
+
+// TestSummarizeSamples tests bucketing samples into per-project peaks and averages
+func TestSummarizeSamples(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	samples := []Sample{
+		{Time: start.Add(-time.Hour), Projects: []ProjectSample{{Dir: "/old", Agents: 9}}},
+		{Time: start.Add(time.Hour), Projects: []ProjectSample{
+			{Dir: "/work/api", Agents: 2, CPU: 40, MemoryMB: 800},
+			{Dir: "/work/web", Agents: 1, CPU: 10, MemoryMB: 1200},
+		}},
+		{Time: start.Add(2 * time.Hour), Projects: []ProjectSample{
+			{Dir: "/work/api", Agents: 1, CPU: 20, MemoryMB: 600},
+		}},
+		{Time: start.Add(20 * time.Hour)},
+	}
+
+	h := SummarizeSamples(samples, start, start.Add(24*time.Hour), 4)
+	if h.Samples != 3 {
+		t.Errorf("Samples: got %d, want 3", h.Samples)
+	}
+	if want := []bool{true, false, false, true}; len(h.Covered) != 4 || h.Covered[0] != want[0] || h.Covered[1] || h.Covered[3] != want[3] {
+		t.Errorf("Covered: got %v, want %v", h.Covered, want)
+	}
+
+	total := h.Total
+	if total.PeakAgents != 3 || total.Agents[0] != 3 || total.Agents[3] != 0 {
+		t.Errorf("Total agents: got peak %d, buckets %v", total.PeakAgents, total.Agents)
+	}
+	if total.PeakMemory != 2000 || total.AvgAgents != 4.0/3 {
+		t.Errorf("Total: got peak memory %v, avg agents %v", total.PeakMemory, total.AvgAgents)
+	}
+
+	if len(h.Projects) != 2 || h.Projects[0].Dir != "/work/web" || h.Projects[1].Dir != "/work/api" {
+		t.Fatalf("Projects: got %+v, want web then api by peak memory", h.Projects)
+	}
+	api := h.Projects[1]
+	if api.PeakCPU != 40 || api.AvgCPU != 20 || api.CPU[0] != 40 {
+		t.Errorf("API CPU: got peak %v, avg %v, buckets %v", api.PeakCPU, api.AvgCPU, api.CPU)
+	}
+}
@@ -0,0 +1,139 @@
+package monitor
+
+import (
+	"strings"
+	"time"
+
+	"github.com/thieso2/promptwatch/internal/diff"
+)
+
+// Turn is a user prompt with the responses and tool calls that followed it.
+// Messages before the first prompt form a turn without one.
+type Turn struct {
+	Prompt    string
+	Time      time.Time
+	Responses []string // Lines of the assistant's text, in order
+	Tools     []string // Names of the tools called, in order
+}
+
+// SessionTurns splits a session's conversation at its user prompts
+func SessionTurns(stats *SessionStats) []Turn {
+	var turns []Turn
+	for _, msg := range stats.MessageHistory {
+		if msg.Type == "prompt" || len(turns) == 0 {
+			turns = append(turns, Turn{Time: msg.Timestamp})
+			if msg.Type == "prompt" {
+				turns[len(turns)-1].Prompt = msg.Content
+				continue
+			}
+		}
+		turn := &turns[len(turns)-1]
+		if msg.ToolName != "" {
+			turn.Tools = append(turn.Tools, msg.ToolName)
+		}
+		// A tool call without text carries a placeholder as its content
+		text := msg.Content
+		if msg.ToolName != "" && text == "Called tool: "+msg.ToolName {
+			text = ""
+		}
+		if msg.Type == "assistant_response" && strings.TrimSpace(text) != "" {
+			turn.Responses = append(turn.Responses, diff.SplitLines(text)...)
+		}
+	}
+	return turns
+}
+
+// TurnDiff compares a turn of one session with the turn of the other
+// session it was aligned with. A or B is nil for a turn only one session has.
+type TurnDiff struct {
+	A, B           *Turn
+	IndexA, IndexB int // Positions of A and B among their session's turns, -1 when absent
+	SamePrompt     bool
+	Responses      []diff.Line // Edit script from A's responses to B's
+	Tools          []diff.Line // Edit script from A's tool calls to B's
+}
+
+// Changed reports whether the turns differ in prompt, responses or tool calls
+func (d TurnDiff) Changed() bool {
+	if !d.SamePrompt {
+		return true
+	}
+	return changed(d.Responses) || changed(d.Tools)
+}
+
+// changed reports whether an edit script has insertions or deletions
+func changed(lines []diff.Line) bool {
+	for _, l := range lines {
+		if l.Op != diff.Equal {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffSessions aligns the turns of two sessions by their user prompts and
+// diffs the responses and tool calls of each aligned pair. Prompts that were
+// reworded (a run of removed prompts followed by added ones) are paired in
+// order, as A/B experiments vary the prompt of the same step.
+func DiffSessions(a, b *SessionStats) []TurnDiff {
+	turnsA, turnsB := SessionTurns(a), SessionTurns(b)
+	script := diff.Lines(promptKeys(turnsA), promptKeys(turnsB))
+
+	var diffs []TurnDiff
+	var removed, added []int // Indexes of unmatched turns since the last match
+	i, j := 0, 0
+	flush := func() {
+		for k := 0; k < max(len(removed), len(added)); k++ {
+			d := TurnDiff{IndexA: -1, IndexB: -1}
+			if k < len(removed) {
+				d.A, d.IndexA = &turnsA[removed[k]], removed[k]
+			}
+			if k < len(added) {
+				d.B, d.IndexB = &turnsB[added[k]], added[k]
+			}
+			diffs = append(diffs, d.withEdits())
+		}
+		removed, added = nil, nil
+	}
+	for _, l := range script {
+		switch l.Op {
+		case diff.Delete:
+			removed = append(removed, i)
+			i++
+		case diff.Insert:
+			added = append(added, j)
+			j++
+		default:
+			flush()
+			d := TurnDiff{A: &turnsA[i], B: &turnsB[j], IndexA: i, IndexB: j, SamePrompt: true}
+			diffs = append(diffs, d.withEdits())
+			i++
+			j++
+		}
+	}
+	flush()
+	return diffs
+}
+
+// promptKeys returns the prompts of turns with whitespace runs collapsed
+func promptKeys(turns []Turn) []string {
+	keys := make([]string, len(turns))
+	for i, t := range turns {
+		keys[i] = strings.Join(strings.Fields(t.Prompt), " ")
+	}
+	return keys
+}
+
+// withEdits diffs the responses and tool calls of the aligned turns
+func (d TurnDiff) withEdits() TurnDiff {
+	var a, b Turn
+	if d.A != nil {
+		a = *d.A
+	}
+	if d.B != nil {
+		b = *d.B
+	}
+	d.Responses = diff.Lines(a.Responses, b.Responses)
+	d.Tools = diff.Lines(a.Tools, b.Tools)
+	return d
+}
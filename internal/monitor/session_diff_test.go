@@ -0,0 +1,75 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thieso2/promptwatch/internal/diff"
+)
+
+// diffTestSession builds a session from prompts and, per prompt, the
+// response text and tool calls that followed
+func diffTestSession(turns ...[]string) *SessionStats {
+	stats := &SessionStats{}
+	for _, turn := range turns {
+		stats.MessageHistory = append(stats.MessageHistory, Message{Type: "prompt", Role: "user", Content: turn[0]})
+		stats.MessageHistory = append(stats.MessageHistory, Message{Type: "assistant_response", Role: "assistant", Content: turn[1]})
+		for _, tool := range turn[2:] {
+			stats.MessageHistory = append(stats.MessageHistory, Message{Type: "assistant_response", Role: "assistant", ToolName: tool})
+		}
+	}
+	return stats
+}
+
+// TestDiffSessions tests aligning turns by prompt and diffing what followed
+func TestDiffSessions(t *testing.T) {
+	a := diffTestSession(
+		[]string{"set up the project", "Done.", "Bash"},
+		[]string{"add a login page", "Added it.\nUses sessions.", "Read", "Edit"},
+		[]string{"write tests", "Tests pass.", "Bash"},
+		[]string{"deploy", "Deployed."},
+	)
+	b := diffTestSession(
+		[]string{"set up  the project", "Done.", "Bash"},
+		[]string{"add a login page with OAuth", "Added it.\nUses OAuth.", "Read", "Write"},
+		[]string{"write tests", "Tests pass.", "Bash"},
+	)
+
+	diffs := DiffSessions(a, b)
+	if len(diffs) != 4 {
+		t.Fatalf("Got %d aligned turns, want 4: %+v", len(diffs), diffs)
+	}
+
+	// Whitespace does not make prompts differ
+	if !diffs[0].SamePrompt || diffs[0].Changed() {
+		t.Errorf("Turn 1 differs: %+v", diffs[0])
+	}
+
+	// A reworded prompt pairs with the prompt it replaced
+	reworded := diffs[1]
+	if reworded.SamePrompt || reworded.IndexA != 1 || reworded.IndexB != 1 {
+		t.Errorf("Turn 2: %+v", reworded)
+	}
+	if got := editScript(reworded.Tools); got != "Read -Edit +Write" {
+		t.Errorf("Turn 2 tools = %q", got)
+	}
+	if got := editScript(reworded.Responses); got != "Added it. -Uses sessions. +Uses OAuth." {
+		t.Errorf("Turn 2 responses = %q", got)
+	}
+
+	if !diffs[2].SamePrompt || diffs[2].Changed() {
+		t.Errorf("Turn 3 differs: %+v", diffs[2])
+	}
+	if only := diffs[3]; only.B != nil || only.IndexB != -1 || only.A.Prompt != "deploy" || !only.Changed() {
+		t.Errorf("Turn only in A: %+v", only)
+	}
+}
+
+// editScript formats an edit script on one line, e.g. "Read -Edit +Write"
+func editScript(lines []diff.Line) string {
+	var parts []string
+	for _, l := range lines {
+		parts = append(parts, strings.TrimSpace(l.Op.Prefix())+l.Text)
+	}
+	return strings.Join(parts, " ")
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -57,6 +58,9 @@ type Message struct {
 	Type          string // "prompt", "assistant_response", or "tool_result"
 	ToolName      string // Name of tool that was called
 	ToolInput     string // Input passed to tool
+	ToolUseID     string // ID linking a tool call to its tool_result message
+	IsError       bool   // tool_result reported a failure
+	MessageID     string // API message ID, shared by the content blocks of one response
 	Model         string // Claude model used (assistant messages only)
 	InputTokens   int    // Number of input tokens (assistant messages)
 	OutputTokens  int    // Number of output tokens (assistant messages)
@@ -87,7 +91,9 @@ type SessionStats struct {
 	FileSnapshots     int
 	QueueOperations   int
 	CompactCount      int
+	Compacts          []CompactEvent // Compactions in session order
 	MessageHistory    []Message
+	Checkpoints       []FileCheckpoint // File-history snapshots in session order
 	ErrorCount        int
 	ClaudeVersion     string // Version from the session file
 }
@@ -164,14 +170,18 @@ func ParseSessionFile(filePath string) (*SessionStats, error) {
 				var contentStr string
 				var toolName string
 				var toolInput string
+				var toolUseID string
+				var isError bool
 				var msgType string
 				var model string
+				var messageID string
 				var inputTokens, outputTokens, cacheCreation, cacheRead int
 
 				// For assistant messages, try to extract token usage from full JSON
 				if entry.Message.Role == "assistant" {
 					var detailedEntry struct {
 						Message struct {
+							ID    string `json:"id"`
 							Model string `json:"model"`
 							Usage struct {
 								InputTokens              int `json:"input_tokens"`
@@ -182,6 +192,7 @@ func ParseSessionFile(filePath string) (*SessionStats, error) {
 						} `json:"message"`
 					}
 					if err := json.Unmarshal(scanner.Bytes(), &detailedEntry); err == nil {
+						messageID = detailedEntry.Message.ID
 						model = detailedEntry.Message.Model
 						inputTokens = detailedEntry.Message.Usage.InputTokens
 						outputTokens = detailedEntry.Message.Usage.OutputTokens
@@ -200,9 +211,11 @@ func ParseSessionFile(filePath string) (*SessionStats, error) {
 						for _, item := range contentArr {
 							if itemMap, ok := item.(map[string]interface{}); ok {
 								if itemType, ok := itemMap["type"].(string); ok && itemType == "tool_result" {
-									if itemContent, ok := itemMap["content"].(string); ok {
+									if itemContent, ok := ToolResultText(itemMap["content"]); ok {
 										contentStr = itemContent
 										msgType = "tool_result"
+										toolUseID, _ = itemMap["tool_use_id"].(string)
+										isError, _ = itemMap["is_error"].(bool)
 										break
 									}
 								}
@@ -224,6 +237,7 @@ func ParseSessionFile(filePath string) (*SessionStats, error) {
 										// Extract tool information
 										if name, ok := itemMap["name"].(string); ok {
 											toolName = name
+											toolUseID, _ = itemMap["id"].(string)
 											msgType = "assistant_response"
 											// Try to extract input
 											if input, ok := itemMap["input"]; ok {
@@ -287,6 +301,9 @@ func ParseSessionFile(filePath string) (*SessionStats, error) {
 						Type:          msgType,
 						ToolName:      toolName,
 						ToolInput:     toolInput,
+						ToolUseID:     toolUseID,
+						IsError:       isError,
+						MessageID:     messageID,
 						Model:         model,
 						InputTokens:   inputTokens,
 						OutputTokens:  outputTokens,
@@ -311,15 +328,19 @@ func ParseSessionFile(filePath string) (*SessionStats, error) {
 
 		case "system":
 			stats.SystemEvents++
+			if subtype, _ := rawData["subtype"].(string); subtype == "compact_boundary" {
+				stats.addCompact(timestamp, rawData["compactMetadata"])
+			}
 
 		case "file-history-snapshot":
 			stats.FileSnapshots++
+			stats.addSnapshot(scanner.Bytes())
 
 		case "queue-operation":
 			stats.QueueOperations++
 
 		case "compact":
-			stats.CompactCount++
+			stats.addCompact(timestamp, nil)
 
 		case "error":
 			stats.ErrorCount++
@@ -338,6 +359,44 @@ func ParseSessionFile(filePath string) (*SessionStats, error) {
 	return stats, nil
 }
 
+// ToolResultText extracts the text of a tool_result's content, which is either
+// a string or, for MCP and multi-part results, a list of text blocks
+func ToolResultText(content interface{}) (string, bool) {
+	switch c := content.(type) {
+	case string:
+		return c, true
+	case []interface{}:
+		var parts []string
+		for _, block := range c {
+			if blockMap, ok := block.(map[string]interface{}); ok {
+				if text, ok := blockMap["text"].(string); ok {
+					parts = append(parts, text)
+				}
+			}
+		}
+		return strings.Join(parts, "\n"), len(parts) > 0
+	}
+	return "", false
+}
+
+// UsageSeen records the API responses whose usage has been counted. Claude
+// writes each content block of a response as its own entry, and every entry
+// repeats the response's usage.
+type UsageSeen map[string]bool
+
+// First reports whether msg is the first entry of its response, so its usage
+// and cost should be counted
+func (s UsageSeen) First(msg Message) bool {
+	if msg.MessageID == "" {
+		return true
+	}
+	if s[msg.MessageID] {
+		return false
+	}
+	s[msg.MessageID] = true
+	return true
+}
+
 // GetSummary returns a human-readable summary of session stats
 func (s *SessionStats) GetSummary() string {
 	duration := formatDuration(s.Duration)
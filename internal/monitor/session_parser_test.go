@@ -136,3 +136,41 @@ func TestEventTypeDetection(t *testing.T) {
 		t.Errorf("Message count: got %d, want 2", metadata.MessageCount)
 	}
 }
+
+// TestToolResultLinking tests that tool calls and their results share an ID
+func TestToolResultLinking(t *testing.T) {
+	sessionFile := filepath.Join(t.TempDir(), "test-tools.jsonl")
+
+	testData := `{"type":"assistant","timestamp":"2026-01-09T14:00:00.000Z","message":{"role":"assistant","content":[{"type":"tool_use","id":"toolu_1","name":"Read","input":{"file_path":"/a.go"}}]}}
+{"type":"user","timestamp":"2026-01-09T14:00:01.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"package a"}]}}
+{"type":"user","timestamp":"2026-01-09T14:00:02.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"toolu_2","content":[{"type":"text","text":"{\"id\":1}"},{"type":"text","text":"done"}]}]}}
+`
+	if err := os.WriteFile(sessionFile, []byte(testData), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	stats, err := ParseSessionFile(sessionFile)
+	if err != nil {
+		t.Fatalf("ParseSessionFile failed: %v", err)
+	}
+	if len(stats.MessageHistory) != 3 {
+		t.Fatalf("Expected 3 messages, got %d", len(stats.MessageHistory))
+	}
+
+	tests := []struct {
+		name     string
+		got      interface{}
+		expected interface{}
+	}{
+		{"CallID", stats.MessageHistory[0].ToolUseID, "toolu_1"},
+		{"ResultID", stats.MessageHistory[1].ToolUseID, "toolu_1"},
+		{"ResultType", stats.MessageHistory[1].Type, "tool_result"},
+		{"BlockResult", stats.MessageHistory[2].Content, "{\"id\":1}\ndone"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package monitor
+
+import (
+	"sort"
+	"time"
+)
+
+// SessionProfile holds the figures sessions are compared by
+type SessionProfile struct {
+	Path          string
+	Started       time.Time
+	Duration      time.Duration
+	Turns         int // User prompts
+	InputTokens   int
+	OutputTokens  int
+	CacheCreation int
+	CacheRead     int
+	Cost          float64
+	ToolCalls     map[string]int // Calls by tool name
+	ToolErrors    int            // Failed tool results
+	APIErrors     int            // Error entries of the session file
+	Models        []string       // Models used, in order of first use
+	Compacts      int
+	Curve         []TokenPoint // Cumulative tokens after each response
+}
+
+// TokenPoint is the cumulative token use of a session at a point in time
+type TokenPoint struct {
+	Elapsed time.Duration // Since the session started
+	Tokens  int
+}
+
+// TotalTokens returns the tokens of all types
+func (p SessionProfile) TotalTokens() int {
+	return p.InputTokens + p.OutputTokens + p.CacheCreation + p.CacheRead
+}
+
+// ToolNames returns the tools called, most called first
+func (p SessionProfile) ToolNames() []string {
+	names := make([]string, 0, len(p.ToolCalls))
+	for name := range p.ToolCalls {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if p.ToolCalls[names[i]] != p.ToolCalls[names[j]] {
+			return p.ToolCalls[names[i]] > p.ToolCalls[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// ProfileSession collects the comparison figures of a parsed session
+func ProfileSession(stats *SessionStats) SessionProfile {
+	p := SessionProfile{
+		Path:      stats.FilePath,
+		Started:   stats.CreatedAt,
+		Duration:  stats.Duration,
+		APIErrors: stats.ErrorCount,
+		Compacts:  stats.CompactCount,
+		ToolCalls: make(map[string]int),
+	}
+
+	seenModels := make(map[string]bool)
+	seenUsage := make(UsageSeen)
+	for _, msg := range stats.MessageHistory {
+		counted := seenUsage.First(msg)
+		switch msg.Type {
+		case "prompt":
+			p.Turns++
+		case "tool_result":
+			if msg.IsError {
+				p.ToolErrors++
+			}
+		case "assistant_response":
+			if counted {
+				cost, _ := MessageCost(msg)
+				p.Cost += cost
+			}
+		}
+		if msg.ToolName != "" {
+			p.ToolCalls[msg.ToolName]++
+		}
+		if msg.Model != "" && !seenModels[msg.Model] {
+			seenModels[msg.Model] = true
+			p.Models = append(p.Models, msg.Model)
+		}
+
+		tokens := msg.InputTokens + msg.OutputTokens + msg.CacheCreation + msg.CacheRead
+		if tokens == 0 || !counted {
+			continue
+		}
+		p.InputTokens += msg.InputTokens
+		p.OutputTokens += msg.OutputTokens
+		p.CacheCreation += msg.CacheCreation
+		p.CacheRead += msg.CacheRead
+		elapsed := time.Duration(0)
+		if !msg.Timestamp.IsZero() && !stats.CreatedAt.IsZero() {
+			elapsed = max(msg.Timestamp.Sub(stats.CreatedAt), 0)
+		}
+		p.Curve = append(p.Curve, TokenPoint{Elapsed: elapsed, Tokens: p.TotalTokens()})
+	}
+	return p
+}
+
+// TokensAt returns the cumulative tokens a session had used after elapsed
+func (p SessionProfile) TokensAt(elapsed time.Duration) int {
+	i := sort.Search(len(p.Curve), func(i int) bool { return p.Curve[i].Elapsed > elapsed })
+	if i == 0 {
+		return 0
+	}
+	return p.Curve[i-1].Tokens
+}
@@ -0,0 +1,87 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestProfileSession tests the figures and token curve sessions are compared by
+func TestProfileSession(t *testing.T) {
+	sessionFile := filepath.Join(t.TempDir(), "session.jsonl")
+	data := `{"type":"user","timestamp":"2026-01-09T14:00:00.000Z","message":{"role":"user","content":"fix the build"}}
+{"type":"assistant","timestamp":"2026-01-09T14:00:10.000Z","message":{"role":"assistant","model":"claude-opus-4","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{}}],"usage":{"input_tokens":100,"cache_read_input_tokens":1000,"cache_creation_input_tokens":200,"output_tokens":50}}}
+{"type":"user","timestamp":"2026-01-09T14:00:20.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"exit 1","is_error":true}]}}
+{"type":"system","subtype":"compact_boundary","timestamp":"2026-01-09T14:00:30.000Z","compactMetadata":{"trigger":"auto","preTokens":1350}}
+{"type":"user","timestamp":"2026-01-09T14:01:00.000Z","message":{"role":"user","content":"try again"}}
+{"type":"assistant","timestamp":"2026-01-09T14:02:00.000Z","message":{"role":"assistant","model":"claude-sonnet-4","content":[{"type":"tool_use","id":"t2","name":"Edit","input":{}}],"usage":{"input_tokens":10,"cache_read_input_tokens":0,"cache_creation_input_tokens":0,"output_tokens":40}}}
+{"type":"assistant","timestamp":"2026-01-09T14:02:05.000Z","message":{"role":"assistant","model":"claude-sonnet-4","content":[{"type":"tool_use","id":"t3","name":"Bash","input":{}}],"usage":{"input_tokens":0,"cache_read_input_tokens":0,"cache_creation_input_tokens":0,"output_tokens":0}}}
+`
+	if err := os.WriteFile(sessionFile, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	stats, err := ParseSessionFile(sessionFile)
+	if err != nil {
+		t.Fatalf("ParseSessionFile failed: %v", err)
+	}
+
+	p := ProfileSession(stats)
+	if p.Turns != 2 || p.ToolErrors != 1 || p.Compacts != 1 {
+		t.Errorf("Turns %d, tool errors %d, compacts %d; want 2, 1, 1", p.Turns, p.ToolErrors, p.Compacts)
+	}
+	if p.InputTokens != 110 || p.OutputTokens != 90 || p.CacheCreation != 200 || p.CacheRead != 1000 || p.TotalTokens() != 1400 {
+		t.Errorf("Tokens: %+v", p)
+	}
+	if got := strings.Join(p.Models, ","); got != "claude-opus-4,claude-sonnet-4" {
+		t.Errorf("Models = %q", got)
+	}
+	if got := strings.Join(p.ToolNames(), ","); got != "Bash,Edit" || p.ToolCalls["Bash"] != 2 {
+		t.Errorf("Tools = %q, %v", got, p.ToolCalls)
+	}
+	if p.Cost <= 0 {
+		t.Errorf("Cost = %v, want > 0", p.Cost)
+	}
+
+	// The curve has a point for each response that used tokens
+	if len(p.Curve) != 2 || p.Curve[0].Elapsed != 10*time.Second || p.Curve[1].Tokens != 1400 {
+		t.Errorf("Curve = %+v", p.Curve)
+	}
+	for elapsed, want := range map[time.Duration]int{0: 0, 10 * time.Second: 1350, time.Minute: 1350, time.Hour: 1400} {
+		if got := p.TokensAt(elapsed); got != want {
+			t.Errorf("TokensAt(%v) = %d, want %d", elapsed, got, want)
+		}
+	}
+}
+
+// TestProfileSessionMultiBlock tests that a response written as several
+// entries repeating its usage is counted once
+func TestProfileSessionMultiBlock(t *testing.T) {
+	sessionFile := filepath.Join(t.TempDir(), "session.jsonl")
+	data := `{"type":"user","timestamp":"2026-01-09T14:00:00.000Z","message":{"role":"user","content":"fix the build"}}
+{"type":"assistant","timestamp":"2026-01-09T14:00:10.000Z","message":{"id":"msg_1","role":"assistant","model":"claude-opus-4","content":[{"type":"text","text":"Running the build."}],"usage":{"input_tokens":100,"output_tokens":50}}}
+{"type":"assistant","timestamp":"2026-01-09T14:00:11.000Z","message":{"id":"msg_1","role":"assistant","model":"claude-opus-4","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{}}],"usage":{"input_tokens":100,"output_tokens":50}}}
+{"type":"user","timestamp":"2026-01-09T14:00:20.000Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}
+{"type":"assistant","timestamp":"2026-01-09T14:00:30.000Z","message":{"id":"msg_2","role":"assistant","model":"claude-opus-4","content":[{"type":"text","text":"Fixed."}],"usage":{"input_tokens":10,"output_tokens":5}}}
+`
+	if err := os.WriteFile(sessionFile, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	stats, err := ParseSessionFile(sessionFile)
+	if err != nil {
+		t.Fatalf("ParseSessionFile failed: %v", err)
+	}
+
+	p := ProfileSession(stats)
+	if p.InputTokens != 110 || p.OutputTokens != 55 {
+		t.Errorf("Tokens: input %d, output %d; want 110, 55", p.InputTokens, p.OutputTokens)
+	}
+	want, _ := MessageCost(Message{Model: "claude-opus-4", InputTokens: 110, OutputTokens: 55})
+	if diff := p.Cost - want; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Cost = %v, want %v", p.Cost, want)
+	}
+	if len(p.Curve) != 2 || p.Curve[1].Tokens != 165 {
+		t.Errorf("Curve = %+v", p.Curve)
+	}
+}
@@ -17,6 +17,7 @@ type Session struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 	Title     string    `json:"title"`
 	FilePath  string    // Full path to the session file
+	Root      string    // Label of the data root the session came from
 }
 
 // SessionInfo represents session metadata
@@ -27,25 +28,32 @@ type SessionInfo struct {
 	Title     string    `json:"title"`
 }
 
-// FindSessionsForDirectory finds all sessions for a given working directory
+// FindSessionsForDirectory finds all sessions for a given working directory across all data roots
 func FindSessionsForDirectory(workingDir string) ([]Session, error) {
-	// Convert working directory path to the format used in .claude/projects
-	// /Users/thies/Projects/foo -> -Users-thies-Projects-foo
-	dirName := convertPathToSessionDirName(workingDir)
-
-	// Look for sessions in ~/.claude/projects/<dirName>/
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
-	}
+	var sessions []Session
 
-	sessionDir := filepath.Join(homeDir, ".claude", "projects", dirName)
+	for _, root := range DataRoots() {
+		// Map the working directory to its project in this root
+		project := root.Resolver().Resolve(workingDir)
+		if project.Status != ProjectFound {
+			continue // No sessions in this root, but not an error
+		}
 
-	// Check if directory exists
-	if _, err := os.Stat(sessionDir); os.IsNotExist(err) {
-		return nil, nil // No sessions found, but not an error
+		rootSessions, err := FindSessionsInProject(project.Dir)
+		if err != nil {
+			return nil, err
+		}
+		for i := range rootSessions {
+			rootSessions[i].Root = root.Label
+		}
+		sessions = append(sessions, rootSessions...)
 	}
 
+	return sessions, nil
+}
+
+// FindSessionsInProject reads all session files in a project directory
+func FindSessionsInProject(sessionDir string) ([]Session, error) {
 	// Read all .jsonl files in the directory
 	entries, err := os.ReadDir(sessionDir)
 	if err != nil {
@@ -147,13 +155,6 @@ func readSessionFile(filePath string) (Session, error) {
 	return session, nil
 }
 
-// convertPathToSessionDirName converts a file path to the session directory format
-// /Users/thies/Projects/foo -> -Users-thies-Projects-foo
-func convertPathToSessionDirName(path string) string {
-	// Replace all slashes with dashes (including the leading one)
-	return strings.ReplaceAll(path, "/", "-")
-}
-
 // GetSessionInfo extracts human-readable info about a session
 func (s Session) GetSessionInfo() string {
 	if s.Title != "" {
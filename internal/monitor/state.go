@@ -0,0 +1,68 @@
+package monitor
+
+import "time"
+
+// SessionState is what a running session is doing, judged from its last entries
+type SessionState int
+
+const (
+	StateUnknown           SessionState = iota // No session file
+	StateIdle                                  // Quiet for longer than the idle limit
+	StateWorking                               // Claude is generating or a tool is running
+	StateWaitingUser                           // Claude replied and waits for the next prompt
+	StateWaitingPermission                     // A tool call waits on a permission prompt
+)
+
+// String returns the state as shown in the status column
+func (s SessionState) String() string {
+	switch s {
+	case StateIdle:
+		return "idle"
+	case StateWorking:
+		return "working"
+	case StateWaitingUser:
+		return "waiting for user"
+	case StateWaitingPermission:
+		return "waiting for permission"
+	}
+	return "-"
+}
+
+// Urgency orders states by how soon the user should switch to the session,
+// most urgent highest
+func (s SessionState) Urgency() int {
+	switch s {
+	case StateWaitingPermission:
+		return 3
+	case StateWaitingUser:
+		return 2
+	case StateWorking:
+		return 1
+	}
+	return 0
+}
+
+// State classifies the session at now; treeCPU is the CPU use of the process
+// running it and its tools. A trailing assistant reply without tool calls
+// waits for the user once the file has been quiet for rules.TurnSettle. A
+// permission request waits for permission, as does a tool call without
+// result after rules.PermissionWait unless the process tree is busy running
+// it. A session that is not waiting and has been quiet for idleAfter is
+// idle; a waiting session stays waiting however long it has been quiet.
+func (l LiveSession) State(now time.Time, rules EventRules, idleAfter time.Duration, treeCPU float64) SessionState {
+	if l.LastRole == "" {
+		return StateIdle
+	}
+	quiet := now.Sub(l.LastWrite)
+	switch {
+	case l.AsksPermission:
+		return StateWaitingPermission
+	case rules.awaitsPermission(l, now, treeCPU):
+		return StateWaitingPermission
+	case (l.TurnEnded || l.APIError != "") && quiet >= rules.TurnSettle:
+		return StateWaitingUser
+	case idleAfter > 0 && quiet >= idleAfter:
+		return StateIdle
+	}
+	return StateWorking
+}
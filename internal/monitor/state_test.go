@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSessionState tests classifying a session from the end of its file
+func TestSessionState(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Now()
+	idleAfter := 30 * time.Minute
+
+	prompt := `{"type":"user","uuid":"p1","message":{"role":"user","content":"build it"}}`
+	toolCall := `{"type":"assistant","uuid":"a1","message":{"role":"assistant","stop_reason":"tool_use","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"make"}}]}}`
+	toolResult := `{"type":"user","uuid":"r1","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`
+	reply := `{"type":"assistant","uuid":"a2","message":{"role":"assistant","stop_reason":"end_turn","content":[{"type":"text","text":"Done."}]}}`
+	permission := `{"type":"system","subtype":"informational","uuid":"s1","content":"Claude requested permissions to use Bash"}`
+	modeChange := `{"type":"system","subtype":"informational","uuid":"s2","content":"Permission mode changed to acceptEdits"}`
+
+	tests := []struct {
+		name     string
+		lines    []string
+		after    time.Duration
+		treeCPU  float64
+		expected SessionState
+	}{
+		{"Empty session", nil, time.Second, 0, StateIdle},
+		{"Prompt sent", []string{prompt}, 20 * time.Second, 0, StateWorking},
+		{"Tool running", []string{prompt, toolCall}, 2 * time.Second, 0, StateWorking},
+		{"Tool pending", []string{prompt, toolCall}, 20 * time.Second, 0, StateWaitingPermission},
+		{"Tool running long", []string{prompt, toolCall}, 5 * time.Minute, 95, StateWorking},
+		{"Permission requested", []string{prompt, toolCall, permission}, time.Second, 0, StateWaitingPermission},
+		{"Permission granted", []string{prompt, toolCall, permission, toolResult}, time.Second, 0, StateWorking},
+		{"Permission mode changed", []string{prompt, toolCall, toolResult, modeChange}, time.Second, 0, StateWorking},
+		{"Reply settling", []string{prompt, toolCall, toolResult, reply}, time.Second, 0, StateWorking},
+		{"Reply finished", []string{prompt, toolCall, toolResult, reply}, 5 * time.Second, 0, StateWaitingUser},
+		{"Reply long ago", []string{prompt, reply}, time.Hour, 0, StateWaitingUser},
+		{"Permission long ago", []string{prompt, toolCall, permission}, time.Hour, 0, StateWaitingPermission},
+		{"Tool pending long ago", []string{prompt, toolCall}, time.Hour, 0, StateWaitingPermission},
+		{"Prompt long ago", []string{prompt}, time.Hour, 0, StateIdle},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, string(rune('a'+i))+".jsonl")
+			content := strings.Join(tt.lines, "\n")
+			if content != "" {
+				content += "\n"
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+			if err := os.Chtimes(path, base, base); err != nil {
+				t.Fatalf("Failed to set test file time: %v", err)
+			}
+
+			live, err := ReadLiveSession(path)
+			if err != nil {
+				t.Fatalf("Failed to read live session: %v", err)
+			}
+			if got := live.State(base.Add(tt.after), DefaultEventRules, idleAfter, tt.treeCPU); got != tt.expected {
+				t.Errorf("got state %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestStateUrgency tests that states needing the user sort first
+func TestStateUrgency(t *testing.T) {
+	order := []SessionState{StateWaitingPermission, StateWaitingUser, StateWorking, StateIdle}
+	for i := 1; i < len(order); i++ {
+		if order[i-1].Urgency() <= order[i].Urgency() {
+			t.Errorf("%q should be more urgent than %q", order[i-1], order[i])
+		}
+	}
+	if StateUnknown.Urgency() != StateIdle.Urgency() {
+		t.Errorf("Unknown state should be as urgent as idle")
+	}
+}
@@ -0,0 +1,317 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ToolCall is one tool_use of a session, paired with its tool_result
+type ToolCall struct {
+	Name       string
+	Model      string // Model that made the call
+	Project    string // Working directory of the session
+	Time       time.Time
+	Latency    time.Duration // Time until the result arrived (HasResult only)
+	HasResult  bool
+	IsError    bool
+	InputSize  int // Bytes of JSON input
+	ResultSize int // Bytes of result text
+}
+
+// SessionToolCalls pairs the tool calls of a session with their results
+func SessionToolCalls(stats *SessionStats) []ToolCall {
+	var calls []ToolCall
+	byID := make(map[string]int)
+	for _, msg := range stats.MessageHistory {
+		switch {
+		case msg.ToolName != "":
+			if msg.ToolUseID != "" {
+				byID[msg.ToolUseID] = len(calls)
+			}
+			calls = append(calls, ToolCall{
+				Name:      msg.ToolName,
+				Model:     msg.Model,
+				Project:   msg.WorkingDir,
+				Time:      msg.Timestamp,
+				InputSize: len(msg.ToolInput),
+			})
+		case msg.Type == "tool_result" && msg.ToolUseID != "":
+			i, ok := byID[msg.ToolUseID]
+			if !ok {
+				continue
+			}
+			call := &calls[i]
+			call.HasResult = true
+			call.IsError = msg.IsError
+			call.ResultSize = len(msg.Content)
+			if !msg.Timestamp.IsZero() && msg.Timestamp.After(call.Time) {
+				call.Latency = msg.Timestamp.Sub(call.Time)
+			}
+		}
+	}
+	return calls
+}
+
+// LoadToolCalls parses session files and returns all their tool calls
+func LoadToolCalls(sessionFiles []string) ([]ToolCall, error) {
+	var calls []ToolCall
+	for _, path := range sessionFiles {
+		stats, err := ParseSessionFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+		}
+		calls = append(calls, SessionToolCalls(stats)...)
+	}
+	return calls, nil
+}
+
+// AllSessionFiles lists the session files of every project in every data
+// root. Files last modified before since are skipped; a zero since keeps all.
+func AllSessionFiles(since time.Time) []string {
+	var files []string
+	for _, root := range DataRoots() {
+		matches, _ := filepath.Glob(filepath.Join(root.ProjectsDir(), "*", "*.jsonl"))
+		for _, path := range matches {
+			if !since.IsZero() {
+				if info, err := os.Stat(path); err != nil || info.ModTime().Before(since) {
+					continue
+				}
+			}
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// MCPServer splits an MCP tool name such as "mcp__github__search_issues"
+// into its server and tool
+func MCPServer(toolName string) (server, tool string, ok bool) {
+	rest, found := strings.CutPrefix(toolName, "mcp__")
+	if !found {
+		return "", "", false
+	}
+	server, tool, ok = strings.Cut(rest, "__")
+	return server, tool, ok && server != ""
+}
+
+// ToolFilter selects the calls a tool report covers. Empty fields match everything.
+type ToolFilter struct {
+	Project string // Working directory, or a directory name such as "promptwatch"
+	Model   string // Substring of the model name, e.g. "opus"
+	Since   time.Time
+	Until   time.Time
+}
+
+// Match reports whether a call passes the filter
+func (f ToolFilter) Match(c ToolCall) bool {
+	if f.Project != "" && c.Project != f.Project && filepath.Base(c.Project) != f.Project &&
+		!strings.HasPrefix(c.Project, strings.TrimSuffix(f.Project, "/")+"/") {
+		return false
+	}
+	if f.Model != "" && !strings.Contains(strings.ToLower(c.Model), strings.ToLower(f.Model)) {
+		return false
+	}
+	if !f.Since.IsZero() && c.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !c.Time.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// ToolStats aggregates the calls of one tool, or of all tools of an MCP server
+type ToolStats struct {
+	Name          string // Tool name; the server name for server totals
+	Server        string // MCP server, empty for built-in tools
+	ServerTotal   bool   // Totals of all tools of Server
+	Calls         int
+	Errors        int
+	MedianLatency time.Duration
+	P95Latency    time.Duration
+	AvgInputSize  int
+	AvgResultSize int
+}
+
+// ErrorRate returns the share of calls that failed, 0-100
+func (s ToolStats) ErrorRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.Errors) * 100 / float64(s.Calls)
+}
+
+// ToolReport is the tool usage of a set of calls
+type ToolReport struct {
+	Calls   int
+	Tools   []ToolStats // Per tool, MCP tools included
+	Servers []ToolStats // Per MCP server
+}
+
+// ToolSortKeys lists the orders a tool report can be sorted in
+var ToolSortKeys = []string{"calls", "errors", "latency", "result", "name"}
+
+// AggregateToolCalls builds the report of the calls passing filter
+func AggregateToolCalls(calls []ToolCall, filter ToolFilter) ToolReport {
+	byTool := make(map[string][]ToolCall)
+	byServer := make(map[string][]ToolCall)
+	var report ToolReport
+	for _, c := range calls {
+		if !filter.Match(c) {
+			continue
+		}
+		report.Calls++
+		byTool[c.Name] = append(byTool[c.Name], c)
+		if server, _, ok := MCPServer(c.Name); ok {
+			byServer[server] = append(byServer[server], c)
+		}
+	}
+
+	for name, list := range byTool {
+		stats := summarizeCalls(name, list)
+		if server, tool, ok := MCPServer(name); ok {
+			stats.Name, stats.Server = tool, server
+		}
+		report.Tools = append(report.Tools, stats)
+	}
+	for server, list := range byServer {
+		stats := summarizeCalls(server, list)
+		stats.Server, stats.ServerTotal = server, true
+		report.Servers = append(report.Servers, stats)
+	}
+	SortToolStats(report.Tools, "calls")
+	SortToolStats(report.Servers, "calls")
+	return report
+}
+
+// Grouped lists the built-in tools sorted by one of ToolSortKeys, then each
+// MCP server's total followed by the server's tools, servers sorted the same way
+func (r ToolReport) Grouped(by string) []ToolStats {
+	var rows []ToolStats
+	byServer := make(map[string][]ToolStats)
+	for _, tool := range r.Tools {
+		if tool.Server == "" {
+			rows = append(rows, tool)
+		} else {
+			byServer[tool.Server] = append(byServer[tool.Server], tool)
+		}
+	}
+	SortToolStats(rows, by)
+
+	servers := append([]ToolStats{}, r.Servers...)
+	SortToolStats(servers, by)
+	for _, server := range servers {
+		tools := byServer[server.Server]
+		SortToolStats(tools, by)
+		rows = append(append(rows, server), tools...)
+	}
+	return rows
+}
+
+// summarizeCalls computes counts, latency percentiles and average sizes
+func summarizeCalls(name string, calls []ToolCall) ToolStats {
+	stats := ToolStats{Name: name, Calls: len(calls)}
+	var latencies []time.Duration
+	inputTotal, resultTotal, results := 0, 0, 0
+	for _, c := range calls {
+		inputTotal += c.InputSize
+		if c.IsError {
+			stats.Errors++
+		}
+		if c.HasResult {
+			results++
+			resultTotal += c.ResultSize
+			latencies = append(latencies, c.Latency)
+		}
+	}
+
+	stats.AvgInputSize = inputTotal / len(calls)
+	if results > 0 {
+		stats.AvgResultSize = resultTotal / results
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	stats.MedianLatency = percentile(latencies, 50)
+	stats.P95Latency = percentile(latencies, 95)
+	return stats
+}
+
+// percentile returns the nearest-rank percentile p of sorted values
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	return sorted[max(rank-1, 0)]
+}
+
+// SortToolStats orders tools by one of ToolSortKeys, largest first
+func SortToolStats(tools []ToolStats, by string) {
+	less := func(a, b ToolStats) bool {
+		switch by {
+		case "errors":
+			if a.ErrorRate() != b.ErrorRate() {
+				return a.ErrorRate() > b.ErrorRate()
+			}
+		case "latency":
+			if a.P95Latency != b.P95Latency {
+				return a.P95Latency > b.P95Latency
+			}
+		case "result":
+			if a.AvgResultSize != b.AvgResultSize {
+				return a.AvgResultSize > b.AvgResultSize
+			}
+		case "name":
+		default:
+			if a.Calls != b.Calls {
+				return a.Calls > b.Calls
+			}
+		}
+		if a.Server != b.Server {
+			return a.Server < b.Server
+		}
+		return a.Name < b.Name
+	}
+	sort.SliceStable(tools, func(i, j int) bool { return less(tools[i], tools[j]) })
+}
+
+// Models returns the distinct models that made the calls, sorted
+func Models(calls []ToolCall) []string {
+	seen := make(map[string]bool)
+	var models []string
+	for _, c := range calls {
+		if c.Model != "" && !seen[c.Model] {
+			seen[c.Model] = true
+			models = append(models, c.Model)
+		}
+	}
+	sort.Strings(models)
+	return models
+}
+
+// FormatLatency renders a tool latency compactly, e.g. "350ms", "4.2s", "2m05s"
+func FormatLatency(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "-"
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// FormatSize renders a byte count compactly, e.g. "512 B", "3.4 KB"
+func FormatSize(bytes int) string {
+	switch {
+	case bytes < 1024:
+		return fmt.Sprintf("%d B", bytes)
+	case bytes < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(bytes)/1024)
+	}
+	return fmt.Sprintf("%.1f MB", float64(bytes)/(1024*1024))
+}
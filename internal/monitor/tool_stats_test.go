@@ -0,0 +1,77 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestToolStats tests pairing tool calls with results and aggregating them
+func TestToolStats(t *testing.T) {
+	dir := t.TempDir()
+	sessionFile := filepath.Join(dir, "tools.jsonl")
+
+	data := `{"type":"assistant","timestamp":"2026-01-09T14:00:00.000Z","cwd":"/work/api","message":{"role":"assistant","model":"claude-opus-4","content":[{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test"}}]}}
+{"type":"user","timestamp":"2026-01-09T14:00:04.000Z","cwd":"/work/api","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}
+{"type":"assistant","timestamp":"2026-01-09T14:01:00.000Z","cwd":"/work/api","message":{"role":"assistant","model":"claude-opus-4","content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"false"}}]}}
+{"type":"user","timestamp":"2026-01-09T14:01:10.000Z","cwd":"/work/api","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t2","content":"exit 1","is_error":true}]}}
+{"type":"assistant","timestamp":"2026-01-10T09:00:00.000Z","cwd":"/work/api","message":{"role":"assistant","model":"claude-sonnet-4","content":[{"type":"tool_use","id":"t3","name":"mcp__github__search_issues","input":{"q":"bug"}}]}}
+{"type":"user","timestamp":"2026-01-10T09:00:02.000Z","cwd":"/work/api","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t3","content":[{"type":"text","text":"[]"}]}]}}
+{"type":"assistant","timestamp":"2026-01-10T09:01:00.000Z","cwd":"/work/api","message":{"role":"assistant","model":"claude-sonnet-4","content":[{"type":"tool_use","id":"t4","name":"mcp__github__get_pr","input":{"n":1}}]}}
+`
+	if err := os.WriteFile(sessionFile, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	calls, err := LoadToolCalls([]string{sessionFile})
+	if err != nil {
+		t.Fatalf("LoadToolCalls failed: %v", err)
+	}
+	if len(calls) != 4 {
+		t.Fatalf("Expected 4 calls, got %d", len(calls))
+	}
+
+	report := AggregateToolCalls(calls, ToolFilter{})
+	if len(report.Tools) != 3 || len(report.Servers) != 1 {
+		t.Fatalf("Expected 3 tools and 1 server, got %+v", report)
+	}
+	bash, server := report.Tools[0], report.Servers[0]
+
+	tests := []struct {
+		name     string
+		got      interface{}
+		expected interface{}
+	}{
+		{"Bash first", bash.Name, "Bash"},
+		{"Bash calls", bash.Calls, 2},
+		{"Bash error rate", bash.ErrorRate(), 50.0},
+		{"Bash median", bash.MedianLatency, 4 * time.Second},
+		{"Bash p95", bash.P95Latency, 10 * time.Second},
+		{"Bash result size", bash.AvgResultSize, 4}, // "ok" and "exit 1"
+		{"Server", server.Name, "github"},
+		{"Server calls", server.Calls, 2},
+		{"Server median", server.MedianLatency, 2 * time.Second}, // get_pr has no result
+		{"Model filter", AggregateToolCalls(calls, ToolFilter{Model: "opus"}).Calls, 2},
+		{"Project filter", AggregateToolCalls(calls, ToolFilter{Project: "api"}).Calls, 4},
+		{"Other project", AggregateToolCalls(calls, ToolFilter{Project: "/work/web"}).Calls, 0},
+		{"Date filter", AggregateToolCalls(calls, ToolFilter{Since: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)}).Calls, 2},
+		{"Until filter", AggregateToolCalls(calls, ToolFilter{Until: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)}).Calls, 2},
+		{"Models", len(Models(calls)), 2},
+		{"Grouped rows", len(report.Grouped("name")), 4},
+		{"Grouped server total", report.Grouped("name")[1].ServerTotal, true},
+		{"Grouped server tool", report.Grouped("name")[2].Name, "get_pr"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.expected)
+		}
+	}
+
+	for _, tool := range report.Tools {
+		if tool.Server == "github" && tool.Name != "search_issues" && tool.Name != "get_pr" {
+			t.Errorf("Unexpected MCP tool name %q", tool.Name)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package notify delivers session notifications through pluggable backends:
+// the terminal bell, OSC 9/777 escape sequences, a command such as
+// notify-send, and a generic webhook
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Notification is one message to deliver
+type Notification struct {
+	Kind  string `json:"kind"` // Event kind, e.g. "turn_done"
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// Notifier delivers notifications
+type Notifier interface {
+	Notify(n Notification) error
+}
+
+// Bell rings the terminal bell
+type Bell struct {
+	W io.Writer
+}
+
+// Notify writes BEL
+func (b Bell) Notify(Notification) error {
+	_, err := io.WriteString(b.W, "\a")
+	return err
+}
+
+// OSC shows a desktop notification through the terminal: OSC 9 (iTerm2,
+// Windows Terminal, kitty, WezTerm) or OSC 777 (rxvt, foot, Ghostty)
+type OSC struct {
+	W    io.Writer
+	Code int // 9 or 777
+}
+
+// Notify writes the escape sequence
+func (o OSC) Notify(n Notification) error {
+	var seq string
+	if o.Code == 777 {
+		seq = fmt.Sprintf("\x1b]777;notify;%s;%s\a", sanitize(n.Title), sanitize(n.Body))
+	} else {
+		seq = fmt.Sprintf("\x1b]9;%s: %s\a", sanitize(n.Title), sanitize(n.Body))
+	}
+	_, err := io.WriteString(o.W, seq)
+	return err
+}
+
+// sanitize removes characters that would end or break an escape sequence
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ';':
+			return ','
+		case r < 0x20 || r == 0x7f:
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// Command runs a program with the title and body appended as arguments,
+// e.g. notify-send
+type Command struct {
+	Args []string // Program and leading arguments
+}
+
+// Notify runs the command and waits for it
+func (c Command) Notify(n Notification) error {
+	if len(c.Args) == 0 {
+		return errors.New("no notification command")
+	}
+	args := append(append([]string{}, c.Args[1:]...), n.Title, n.Body)
+	if out, err := exec.Command(c.Args[0], args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", c.Args[0], err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// Webhook posts the notification as JSON:
+// {"kind": ..., "title": ..., "body": ..., "text": "title: body"}.
+// The text field suits Slack and Mattermost incoming webhooks.
+type Webhook struct {
+	URL    string
+	Client *http.Client // http.DefaultClient with a 10s timeout when nil
+}
+
+// Notify posts to the webhook and checks for a 2xx response
+func (w Webhook) Notify(n Notification) error {
+	payload, err := json.Marshal(struct {
+		Notification
+		Text string `json:"text"`
+	}{n, n.Title + ": " + n.Body})
+	if err != nil {
+		return err
+	}
+
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Post(w.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Multi delivers to every notifier and joins their errors
+type Multi []Notifier
+
+// Notify delivers n to each notifier
+func (m Multi) Notify(n Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
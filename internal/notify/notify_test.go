@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTerminalBackends tests the bell and OSC escape sequences
+func TestTerminalBackends(t *testing.T) {
+	n := Notification{Kind: "turn_done", Title: "promptwatch: api", Body: "Turn finished; waiting\n"}
+
+	tests := []struct {
+		name     string
+		notifier func(w io.Writer) Notifier
+		expected string
+	}{
+		{"Bell", func(w io.Writer) Notifier { return Bell{W: w} }, "\a"},
+		{"OSC 9", func(w io.Writer) Notifier { return OSC{W: w, Code: 9} }, "\x1b]9;promptwatch: api: Turn finished, waiting \a"},
+		{"OSC 777", func(w io.Writer) Notifier { return OSC{W: w, Code: 777} }, "\x1b]777;notify;promptwatch: api;Turn finished, waiting \a"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := tt.notifier(&buf).Notify(n); err != nil {
+			t.Fatalf("%s: Notify failed: %v", tt.name, err)
+		}
+		if buf.String() != tt.expected {
+			t.Errorf("%s: got %q, want %q", tt.name, buf.String(), tt.expected)
+		}
+	}
+}
+
+// TestCommandAndWebhook tests running a command and posting to a webhook
+func TestCommandAndWebhook(t *testing.T) {
+	n := Notification{Kind: "permission", Title: "promptwatch: web", Body: "Bash call pending"}
+
+	out := filepath.Join(t.TempDir(), "out")
+	command := Command{Args: []string{"sh", "-c", `printf '%s|%s' "$0" "$1" > "` + out + `"`}}
+	if err := command.Notify(n); err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if got, _ := os.ReadFile(out); string(got) != "promptwatch: web|Bash call pending" {
+		t.Errorf("Command got arguments %q", got)
+	}
+	if err := (Command{Args: []string{"false"}}).Notify(n); err == nil {
+		t.Errorf("Failing command reported no error")
+	}
+
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode webhook body: %v", err)
+		}
+	}))
+	defer server.Close()
+	if err := (Webhook{URL: server.URL}).Notify(n); err != nil {
+		t.Fatalf("Webhook failed: %v", err)
+	}
+	if received["kind"] != "permission" || received["text"] != "promptwatch: web: Bash call pending" {
+		t.Errorf("Unexpected webhook payload %v", received)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer failing.Close()
+	var buf bytes.Buffer
+	err := Multi{Bell{W: &buf}, Webhook{URL: failing.URL}}.Notify(n)
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected 403 error, got %v", err)
+	}
+	if buf.String() != "\a" {
+		t.Errorf("Multi stopped at the failing notifier")
+	}
+}
@@ -0,0 +1,360 @@
+package store
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// IngestStats counts what an ingest added
+type IngestStats struct {
+	Files     int // Files with new lines
+	Unchanged int // Files skipped because nothing was appended
+	Rewritten int // Files that shrank and were ingested again from the start
+	Lines     int
+	Messages  int
+	ToolCalls int
+}
+
+// entry is the part of a session line the database stores
+type entry struct {
+	Type        string `json:"type"`
+	Subtype     string `json:"subtype"`
+	Timestamp   string `json:"timestamp"`
+	UUID        string `json:"uuid"`
+	ParentUUID  string `json:"parentUuid"`
+	Cwd         string `json:"cwd"`
+	Version     string `json:"version"`
+	GitBranch   string `json:"gitBranch"`
+	IsSidechain bool   `json:"isSidechain"`
+	Message     *struct {
+		ID      string          `json:"id"`
+		Role    string          `json:"role"`
+		Model   string          `json:"model"`
+		Content json.RawMessage `json:"content"`
+		Usage   *struct {
+			InputTokens              int `json:"input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// block is an item of an array message content
+type block struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+	ToolUseID string          `json:"tool_use_id"`
+	Content   interface{}     `json:"content"`
+	IsError   bool            `json:"is_error"`
+}
+
+// sessionInfo holds the session fields taken from the first entry that has them
+type sessionInfo struct {
+	project, version, gitBranch, firstPrompt string
+}
+
+// Ingest adds the lines appended to the session files since the last ingest.
+// A file that shrank was rewritten, so its rows are replaced. A trailing line
+// without a newline is still being written and is left for the next ingest.
+func (s *DB) Ingest(sessionFiles []string) (IngestStats, error) {
+	var stats IngestStats
+	for _, path := range sessionFiles {
+		if err := s.ingestFile(path, &stats); err != nil {
+			return stats, fmt.Errorf("cannot ingest %s: %w", path, err)
+		}
+	}
+	return stats, nil
+}
+
+// ingestFile ingests the new lines of one session file in a transaction
+func (s *DB) ingestFile(path string, stats *IngestStats) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	var offset int64
+	err = s.db.QueryRow("SELECT offset FROM files WHERE path = ?", path).Scan(&offset)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if info.Size() == offset {
+		stats.Unchanged++
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	sessionID := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+	if info.Size() < offset {
+		if err := deleteSession(tx, sessionID, path); err != nil {
+			return err
+		}
+		offset = 0
+		stats.Rewritten++
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	var session sessionInfo
+	reader := bufio.NewReaderSize(file, 512*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break // An incomplete last line is read again next time
+		} else if err != nil {
+			return err
+		}
+		if err := ingestLine(tx, sessionID, path, offset, line, &session, stats); err != nil {
+			return err
+		}
+		offset += int64(len(line))
+		stats.Lines++
+	}
+	stats.Files++
+
+	root := ""
+	if r, ok := monitor.RootForPath(path); ok {
+		root = r.Label
+	}
+	if err := updateSession(tx, sessionID, path, root, session); err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO files (path, root, offset, size, mtime, ingested_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(path) DO UPDATE SET offset = excluded.offset, size = excluded.size,
+			mtime = excluded.mtime, ingested_at = excluded.ingested_at`,
+		path, root, offset, info.Size(), formatTime(info.ModTime()), formatTime(time.Now()))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteSession removes the rows of a session before its file is ingested again
+func deleteSession(tx *sql.Tx, sessionID, path string) error {
+	for _, stmt := range []string{
+		"DELETE FROM usage WHERE session_id = ?",
+		"DELETE FROM tool_calls WHERE session_id = ?",
+		"DELETE FROM messages WHERE session_id = ?",
+		"DELETE FROM sessions WHERE id = ?",
+	} {
+		if _, err := tx.Exec(stmt, sessionID); err != nil {
+			return err
+		}
+	}
+	_, err := tx.Exec("DELETE FROM files WHERE path = ?", path)
+	return err
+}
+
+// ingestLine stores the message, tool calls, tool result and usage of a line.
+// Lines that are not JSON, or have no message or system event, are skipped.
+func ingestLine(tx *sql.Tx, sessionID, path string, offset int64, line []byte, info *sessionInfo, stats *IngestStats) error {
+	var e entry
+	if json.Unmarshal(line, &e) != nil {
+		return nil
+	}
+	if e.Type != "user" && e.Type != "assistant" && e.Type != "system" {
+		return nil
+	}
+
+	uuid := e.UUID
+	if uuid == "" {
+		uuid = fmt.Sprintf("%s:%d", sessionID, offset)
+	}
+	timestamp := parseTimestamp(e.Timestamp)
+
+	var role, model, kind, text string
+	var calls []block
+	var results []block
+	if e.Message != nil {
+		role, model = e.Message.Role, e.Message.Model
+		var content string
+		var blocks []block
+		if json.Unmarshal(e.Message.Content, &content) == nil {
+			text = content
+		} else if json.Unmarshal(e.Message.Content, &blocks) == nil {
+			var texts []string
+			for _, b := range blocks {
+				switch b.Type {
+				case "text":
+					texts = append(texts, b.Text)
+				case "tool_use":
+					calls = append(calls, b)
+				case "tool_result":
+					results = append(results, b)
+					if result, ok := monitor.ToolResultText(b.Content); ok {
+						texts = append(texts, result)
+					}
+				}
+			}
+			text = strings.Join(texts, "\n")
+		}
+	}
+	switch {
+	case e.Type == "system":
+		kind = e.Subtype
+	case len(results) > 0:
+		kind = "tool_result"
+	case role == "user":
+		kind = "prompt"
+	default:
+		kind = "assistant_response"
+	}
+
+	if info.project == "" {
+		info.project = e.Cwd
+	}
+	if info.version == "" {
+		info.version = e.Version
+	}
+	if info.gitBranch == "" {
+		info.gitBranch = e.GitBranch
+	}
+	if info.firstPrompt == "" && kind == "prompt" {
+		info.firstPrompt = text
+	}
+
+	res, err := tx.Exec(`INSERT OR IGNORE INTO messages (uuid, session_id, file, offset, parent_uuid, type, kind,
+			role, timestamp, model, text, is_sidechain, cwd, git_branch, version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		uuid, sessionID, path, offset, nullable(e.ParentUUID), e.Type, kind,
+		nullable(role), timestamp, nullable(model), text, e.IsSidechain, nullable(e.Cwd), nullable(e.GitBranch), nullable(e.Version))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil // Already ingested, e.g. history copied into a resumed session
+	}
+	stats.Messages++
+
+	for _, call := range calls {
+		server, _, _ := monitor.MCPServer(call.Name)
+		id := call.ID
+		if id == "" {
+			id = fmt.Sprintf("%s:%s", uuid, call.Name)
+		}
+		_, err := tx.Exec(`INSERT OR IGNORE INTO tool_calls (id, session_id, message_uuid, name, server, input, timestamp, model)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, sessionID, uuid, call.Name, nullable(server), nullable(string(call.Input)), timestamp, nullable(model))
+		if err != nil {
+			return err
+		}
+		stats.ToolCalls++
+	}
+
+	for _, result := range results {
+		resultText, _ := monitor.ToolResultText(result.Content)
+		_, err := tx.Exec(`UPDATE tool_calls SET result_time = ?1, is_error = ?2, result_size = ?3,
+				latency_ms = max(CAST(round((julianday(?1) - julianday(timestamp)) * 86400000) AS INTEGER), 0)
+			WHERE id = ?4`,
+			timestamp, result.IsError, len(resultText), result.ToolUseID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if e.Message != nil && e.Message.Usage != nil {
+		u := e.Message.Usage
+		cost, _ := monitor.MessageCost(monitor.Message{
+			Model:         model,
+			InputTokens:   u.InputTokens,
+			OutputTokens:  u.OutputTokens,
+			CacheCreation: u.CacheCreationInputTokens,
+			CacheRead:     u.CacheReadInputTokens,
+		})
+		_, err := tx.Exec(`INSERT OR IGNORE INTO usage (message_uuid, session_id, request_id, timestamp, model,
+				input_tokens, output_tokens, cache_write_tokens, cache_read_tokens, cost)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			uuid, sessionID, nullable(e.Message.ID), timestamp, nullable(model),
+			u.InputTokens, u.OutputTokens, u.CacheCreationInputTokens, u.CacheReadInputTokens, cost)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateSession creates the session row and recomputes its totals from the
+// ingested rows. Descriptive fields keep the value first seen. Claude writes
+// an entry per content block of a response, each repeating the response's
+// usage, so token and cost totals count the first entry of each request only.
+func updateSession(tx *sql.Tx, sessionID, path, root string, info sessionInfo) error {
+	_, err := tx.Exec(`INSERT INTO sessions (id, file, root, project, version, git_branch, first_prompt)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			project = coalesce(sessions.project, excluded.project),
+			version = coalesce(sessions.version, excluded.version),
+			git_branch = coalesce(sessions.git_branch, excluded.git_branch),
+			first_prompt = coalesce(sessions.first_prompt, excluded.first_prompt)`,
+		sessionID, path, root, nullable(info.project), nullable(info.version), nullable(info.gitBranch), nullable(info.firstPrompt))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE sessions SET
+			started = (SELECT min(timestamp) FROM messages WHERE session_id = ?1),
+			ended = (SELECT max(timestamp) FROM messages WHERE session_id = ?1),
+			messages = (SELECT count(*) FROM messages WHERE session_id = ?1 AND type != 'system'),
+			prompts = (SELECT count(*) FROM messages WHERE session_id = ?1 AND kind = 'prompt'),
+			compacts = (SELECT count(*) FROM messages WHERE session_id = ?1 AND kind = 'compact_boundary'),
+			tool_calls = (SELECT count(*) FROM tool_calls WHERE session_id = ?1),
+			input_tokens = (SELECT coalesce(sum(input_tokens), 0) FROM usage WHERE `+requestUsage+`),
+			output_tokens = (SELECT coalesce(sum(output_tokens), 0) FROM usage WHERE `+requestUsage+`),
+			cache_write_tokens = (SELECT coalesce(sum(cache_write_tokens), 0) FROM usage WHERE `+requestUsage+`),
+			cache_read_tokens = (SELECT coalesce(sum(cache_read_tokens), 0) FROM usage WHERE `+requestUsage+`),
+			cost = (SELECT coalesce(sum(cost), 0) FROM usage WHERE `+requestUsage+`)
+		WHERE id = ?1`, sessionID)
+	return err
+}
+
+// requestUsage selects the first usage row of each request of session ?1;
+// entries without an API message id count on their own
+const requestUsage = `rowid IN (SELECT min(rowid) FROM usage WHERE session_id = ?1
+	GROUP BY coalesce(request_id, message_uuid))`
+
+// parseTimestamp converts a session timestamp to the stored form, or NULL
+func parseTimestamp(s string) interface{} {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return nil
+	}
+	return formatTime(t)
+}
+
+// formatTime formats a time as UTC RFC 3339 with milliseconds, which SQLite's
+// date functions parse
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// nullable stores empty strings as NULL
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,232 @@
+// Package store keeps a SQLite database of sessions, messages, tool calls
+// and token usage, filled incrementally from the session JSONL files
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite" // Registers the "sqlite" driver
+)
+
+// schemaVersion is stored in PRAGMA user_version. The database only holds
+// data derived from the session files, so a database with another version is
+// dropped and ingested again.
+const schemaVersion = 2 // 2: session totals count each request once
+
+// schema creates the tables. Times are RFC 3339 text in UTC so SQLite's date
+// functions work on them.
+const schema = `
+CREATE TABLE files (
+	path        TEXT PRIMARY KEY,
+	root        TEXT NOT NULL,    -- Label of the data root
+	offset      INTEGER NOT NULL, -- Bytes ingested; ingestion resumes here
+	size        INTEGER NOT NULL,
+	mtime       TEXT NOT NULL,
+	ingested_at TEXT NOT NULL
+);
+
+CREATE TABLE sessions (
+	id                 TEXT PRIMARY KEY,
+	file               TEXT NOT NULL,
+	root               TEXT NOT NULL,
+	project            TEXT,    -- Working directory
+	version            TEXT,    -- Claude version of the first entry
+	git_branch         TEXT,
+	first_prompt       TEXT,
+	started            TEXT,
+	ended              TEXT,
+	messages           INTEGER NOT NULL DEFAULT 0,
+	prompts            INTEGER NOT NULL DEFAULT 0,
+	tool_calls         INTEGER NOT NULL DEFAULT 0,
+	input_tokens       INTEGER NOT NULL DEFAULT 0,
+	output_tokens      INTEGER NOT NULL DEFAULT 0,
+	cache_write_tokens INTEGER NOT NULL DEFAULT 0,
+	cache_read_tokens  INTEGER NOT NULL DEFAULT 0,
+	cost               REAL NOT NULL DEFAULT 0, -- USD
+	compacts           INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE messages (
+	uuid         TEXT PRIMARY KEY,
+	session_id   TEXT NOT NULL,
+	file         TEXT NOT NULL,
+	offset       INTEGER NOT NULL, -- Byte offset of the line in the file
+	parent_uuid  TEXT,
+	type         TEXT NOT NULL,    -- Entry type: user, assistant, system, ...
+	kind         TEXT,             -- prompt, assistant_response or tool_result
+	role         TEXT,
+	timestamp    TEXT,
+	model        TEXT,
+	text         TEXT,
+	is_sidechain INTEGER NOT NULL DEFAULT 0,
+	cwd          TEXT,
+	git_branch   TEXT,
+	version      TEXT
+);
+CREATE INDEX messages_session ON messages(session_id, timestamp);
+
+CREATE TABLE tool_calls (
+	id           TEXT PRIMARY KEY, -- tool_use id
+	session_id   TEXT NOT NULL,
+	message_uuid TEXT NOT NULL,
+	name         TEXT NOT NULL,
+	server       TEXT,             -- MCP server of mcp__server__tool calls
+	input        TEXT,             -- JSON
+	timestamp    TEXT,
+	model        TEXT,
+	result_time  TEXT,             -- NULL until the tool_result is ingested
+	latency_ms   INTEGER,
+	is_error     INTEGER,
+	result_size  INTEGER
+);
+CREATE INDEX tool_calls_session ON tool_calls(session_id);
+CREATE INDEX tool_calls_name ON tool_calls(name);
+
+CREATE TABLE usage (
+	message_uuid       TEXT PRIMARY KEY,
+	session_id         TEXT NOT NULL,
+	request_id         TEXT, -- API message id; shared by the entries of one response
+	timestamp          TEXT,
+	model              TEXT,
+	input_tokens       INTEGER NOT NULL,
+	output_tokens      INTEGER NOT NULL,
+	cache_write_tokens INTEGER NOT NULL,
+	cache_read_tokens  INTEGER NOT NULL,
+	cost               REAL NOT NULL -- USD at the configured prices
+);
+CREATE INDEX usage_session ON usage(session_id);
+`
+
+// tables lists the tables in the order they are dropped
+var tables = []string{"usage", "tool_calls", "messages", "sessions", "files"}
+
+// DB is an open session database
+type DB struct {
+	db      *sql.DB
+	Path    string
+	Dropped bool // Open dropped data ingested with another schema version
+}
+
+// Open opens the database at path for ingesting, creating it and its
+// directory when missing. A database of another schema version is dropped.
+func Open(path string) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	// One connection keeps transactions and pragmas on the same handle
+	db.SetMaxOpenConns(1)
+
+	s := &DB{db: db, Path: path}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("cannot open %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// OpenReadOnly opens an existing database for queries. A database of another
+// schema version is an error: only ingest may drop and rebuild it.
+func OpenReadOnly(path string) (*DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("cannot open %s: %w", path, err)
+	}
+	if version != schemaVersion {
+		db.Close()
+		return nil, fmt.Errorf("%s has schema version %d, want %d; run 'promptwatch ingest' to rebuild it", path, version, schemaVersion)
+	}
+	return &DB{db: db, Path: path}, nil
+}
+
+// Close closes the database
+func (s *DB) Close() error {
+	return s.db.Close()
+}
+
+// migrate creates the schema, replacing it when it has another version
+func (s *DB) migrate() error {
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version == schemaVersion {
+		return nil
+	}
+	s.Dropped = version != 0
+	return s.Reset()
+}
+
+// Reset drops all ingested data so the next ingest starts from scratch
+func (s *DB) Reset() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, table := range tables {
+		if _, err := tx.Exec("DROP TABLE IF EXISTS " + table); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(schema); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", schemaVersion)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Result holds the rows of a query as text; NULL is an empty string
+type Result struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// Query runs an SQL statement. Statements that return no rows return an
+// empty result.
+func (s *DB) Query(query string) (Result, error) {
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return Result{}, err
+	}
+	defer rows.Close()
+
+	var result Result
+	if result.Columns, err = rows.Columns(); err != nil {
+		return Result{}, err
+	}
+	values := make([]sql.NullString, len(result.Columns))
+	dest := make([]any, len(values))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return Result{}, err
+		}
+		row := make([]string, len(values))
+		for i, v := range values {
+			row[i] = v.String
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, rows.Err()
+}
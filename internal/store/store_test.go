@@ -0,0 +1,160 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestIngestIncremental tests ingesting a session, then only the lines
+// appended to it, and replacing a rewritten file
+func TestIngestIncremental(t *testing.T) {
+	dir := t.TempDir()
+	sessionFile := filepath.Join(dir, "s1.jsonl")
+	db, err := Open(filepath.Join(dir, "db", "sessions.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer db.Close()
+
+	first := `{"type":"user","uuid":"u1","timestamp":"2026-01-09T14:00:00.000Z","cwd":"/work/api","version":"2.1.0","gitBranch":"main","message":{"role":"user","content":"Run the tests"}}
+{"type":"assistant","uuid":"a1","parentUuid":"u1","timestamp":"2026-01-09T14:00:02.000Z","cwd":"/work/api","message":{"id":"msg_1","role":"assistant","model":"claude-opus-4","content":[{"type":"text","text":"Running them"},{"type":"tool_use","id":"t1","name":"Bash","input":{"command":"go test"}}],"usage":{"input_tokens":100,"output_tokens":20,"cache_read_input_tokens":1000}}}
+{"type":"progress","timestamp":"2026-01-09T14:00:03.000Z"}
+`
+	// The result line is still being written
+	partial := `{"type":"user","uuid":"u2","timestamp":"2026-01-09T14:00:06.500Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"FAIL","is_error":true}]}}`
+	if err := os.WriteFile(sessionFile, []byte(first+partial), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	stats, err := db.Ingest([]string{sessionFile})
+	if err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+	if stats.Lines != 3 || stats.Messages != 2 || stats.ToolCalls != 1 {
+		t.Errorf("First ingest = %+v, want 3 lines, 2 messages, 1 tool call", stats)
+	}
+	query(t, db, "SELECT result_time IS NULL FROM tool_calls", [][]string{{"1"}})
+
+	// Completing the line and appending a compaction ingests only those
+	rest := "\n" + `{"type":"system","subtype":"compact_boundary","uuid":"c1","timestamp":"2026-01-09T14:05:00.000Z"}` + "\n"
+	f, err := os.OpenFile(sessionFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open test file: %v", err)
+	}
+	f.WriteString(rest)
+	f.Close()
+
+	stats, err = db.Ingest([]string{sessionFile})
+	if err != nil {
+		t.Fatalf("Second ingest failed: %v", err)
+	}
+	if stats.Lines != 2 || stats.Messages != 2 || stats.ToolCalls != 0 {
+		t.Errorf("Second ingest = %+v, want 2 lines, 2 messages", stats)
+	}
+	query(t, db, "SELECT name, is_error, latency_ms, result_size FROM tool_calls", [][]string{{"Bash", "1", "4500", "4"}})
+	query(t, db, "SELECT id, project, version, git_branch, first_prompt, messages, prompts, tool_calls, input_tokens, cache_read_tokens, compacts, started, ended FROM sessions",
+		[][]string{{"s1", "/work/api", "2.1.0", "main", "Run the tests", "3", "1", "1", "100", "1000", "1", "2026-01-09T14:00:00.000Z", "2026-01-09T14:05:00.000Z"}})
+	query(t, db, "SELECT kind, text FROM messages ORDER BY offset", [][]string{
+		{"prompt", "Run the tests"}, {"assistant_response", "Running them"}, {"tool_result", "FAIL"}, {"compact_boundary", ""},
+	})
+
+	stats, err = db.Ingest([]string{sessionFile})
+	if err != nil || stats.Unchanged != 1 || stats.Lines != 0 {
+		t.Errorf("Unchanged ingest = %+v, %v", stats, err)
+	}
+
+	// A shorter file was rewritten and replaces the session's rows
+	if err := os.WriteFile(sessionFile, []byte(`{"type":"user","uuid":"u9","timestamp":"2026-02-01T10:00:00.000Z","message":{"role":"user","content":"Start over"}}`+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to rewrite test file: %v", err)
+	}
+	stats, err = db.Ingest([]string{sessionFile})
+	if err != nil || stats.Rewritten != 1 {
+		t.Fatalf("Rewritten ingest = %+v, %v", stats, err)
+	}
+	query(t, db, "SELECT (SELECT count(*) FROM messages), (SELECT count(*) FROM tool_calls), first_prompt FROM sessions",
+		[][]string{{"1", "0", "Start over"}})
+}
+
+// TestIngestMultiBlockResponse tests that a response written as one entry per
+// content block counts its usage once in the session totals
+func TestIngestMultiBlockResponse(t *testing.T) {
+	dir := t.TempDir()
+	sessionFile := filepath.Join(dir, "s2.jsonl")
+	db, err := Open(filepath.Join(dir, "sessions.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer db.Close()
+
+	data := `{"type":"user","uuid":"u1","timestamp":"2026-01-09T14:00:00.000Z","message":{"role":"user","content":"Fix it"}}
+{"type":"assistant","uuid":"a1","timestamp":"2026-01-09T14:00:02.000Z","message":{"id":"msg_1","role":"assistant","model":"claude-opus-4","content":[{"type":"text","text":"Looking"}],"usage":{"input_tokens":100,"output_tokens":20,"cache_read_input_tokens":1000}}}
+{"type":"assistant","uuid":"a2","timestamp":"2026-01-09T14:00:02.100Z","message":{"id":"msg_1","role":"assistant","model":"claude-opus-4","content":[{"type":"tool_use","id":"t1","name":"Read","input":{}}],"usage":{"input_tokens":100,"output_tokens":20,"cache_read_input_tokens":1000}}}
+{"type":"assistant","uuid":"a3","timestamp":"2026-01-09T14:00:05.000Z","message":{"id":"msg_2","role":"assistant","model":"claude-opus-4","content":[{"type":"text","text":"Done"}],"usage":{"input_tokens":50,"output_tokens":10}}}
+`
+	if err := os.WriteFile(sessionFile, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if _, err := db.Ingest([]string{sessionFile}); err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+
+	query(t, db, "SELECT count(*) FROM usage", [][]string{{"3"}})
+	query(t, db, "SELECT input_tokens, output_tokens, cache_read_tokens, cost = (SELECT sum(cost) FROM usage WHERE message_uuid != 'a2') FROM sessions",
+		[][]string{{"150", "30", "1000", "1"}})
+}
+
+// query runs a query and compares its rows
+func query(t *testing.T, db *DB, sql string, want [][]string) {
+	t.Helper()
+	result, err := db.Query(sql)
+	if err != nil {
+		t.Fatalf("Query %q failed: %v", sql, err)
+	}
+	if !reflect.DeepEqual(result.Rows, want) {
+		t.Errorf("Query %q = %v, want %v", sql, result.Rows, want)
+	}
+}
+
+// TestOpenReadOnly tests that queries cannot write and that a database of
+// another schema version is reported instead of dropped
+func TestOpenReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	db.Close()
+
+	ro, err := OpenReadOnly(path)
+	if err != nil {
+		t.Fatalf("OpenReadOnly failed: %v", err)
+	}
+	if _, err := ro.Query("DELETE FROM files"); err == nil {
+		t.Error("Read-only database accepted a write")
+	}
+	ro.Close()
+
+	// Pretend the database was made with an older schema
+	db, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if _, err := db.db.Exec("PRAGMA user_version = 1"); err != nil {
+		t.Fatalf("Failed to set version: %v", err)
+	}
+	db.Close()
+
+	if _, err := OpenReadOnly(path); err == nil {
+		t.Error("OpenReadOnly accepted another schema version")
+	}
+	db, err = Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer db.Close()
+	if !db.Dropped {
+		t.Error("Open did not report dropping the old schema")
+	}
+}
@@ -4,12 +4,20 @@ import "time"
 
 // ClaudeProcess represents a monitored Claude instance with its metrics
 type ClaudeProcess struct {
-	PID        int32
-	CPUPercent float64
-	MemoryMB   float64
-	WorkingDir string
-	Command    string
-	Uptime     time.Duration
-	StartTime  time.Time
-	IsHelper   bool // MCP helper vs main instance
+	PID         int32
+	CPUPercent  float64
+	TreeCPU     float64 // CPU percent of the process and the tools it runs since the last refresh
+	MemoryMB    float64
+	WorkingDir  string
+	Command     string
+	Uptime      time.Duration
+	StartTime   time.Time
+	IsHelper    bool   // MCP helper vs main instance
+	Launcher    string // Matcher that identified the process (native, node, npx, ...)
+	Root        string // Label of the Claude data root the project lives in
+	ProjectDir  string // Project directory in <root>/projects (empty before the first session)
+	HasSessions bool   // Whether the project has any session transcripts yet
+
+	SessionFile    string  // Most recently written session of the project
+	ContextPercent float64 // Context window use of that session, 0-100 (0 = unknown)
 }
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// alertCell lists the rules that fired for a process
+func (m *Model) alertCell(alerts []monitor.Alert) table.StyledCell {
+	if len(alerts) == 0 {
+		return table.NewStyledCell("", m.styles.Muted)
+	}
+	rules := make([]string, len(alerts))
+	for i, alert := range alerts {
+		rules[i] = alert.Rule
+	}
+	return table.NewStyledCell("⚠ "+strings.Join(rules, ","), m.styles.Error)
+}
+
+// stateCell shows a session state coloured by urgency
+func (m *Model) stateCell(state monitor.SessionState) table.StyledCell {
+	style := m.styles.Muted
+	switch state {
+	case monitor.StateWaitingPermission:
+		style = m.styles.Error
+	case monitor.StateWaitingUser:
+		style = m.styles.Warning
+	case monitor.StateWorking:
+		style = m.styles.OK
+	}
+	return table.NewStyledCell(state.String(), style)
+}
+
+// renderAlertLine explains the alerts of the selected process, or is empty
+func (m Model) renderAlertLine() string {
+	if m.selectedProcIdx >= len(m.processes) {
+		return ""
+	}
+	alerts := m.alerts[m.processes[m.selectedProcIdx].PID]
+	if len(alerts) == 0 {
+		return ""
+	}
+	reasons := make([]string, len(alerts))
+	for i, alert := range alerts {
+		reasons[i] = alert.Rule + ": " + alert.Rationale
+	}
+	return m.styles.Warning.Render("⚠ " + strings.Join(reasons, "  |  "))
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/monitor"
+	"github.com/thieso2/promptwatch/internal/types"
+)
+
+// TestProcessAlerts tests flagging processes and explaining the selected one's alerts
+func TestProcessAlerts(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var model tea.Model = NewModel(config.Default(), false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+
+	model, _ = model.Update(processesMsg{
+		processes: []types.ClaudeProcess{{PID: 1}, {PID: 2}},
+		alerts: map[int32][]monitor.Alert{2: {
+			{Rule: monitor.RuleRepeat, Rationale: "Bash called 6 times in a row with identical input"},
+			{Rule: monitor.RuleErrors, Rationale: "last 6 tool calls failed"},
+		}},
+	})
+	view := model.View()
+	if !strings.Contains(view, "⚠ loop,errors") {
+		t.Errorf("Process view missing alert column:\n%s", view)
+	}
+	if strings.Contains(view, "identical input") {
+		t.Errorf("Rationale shown for a process without alerts:\n%s", view)
+	}
+
+	model, _ = model.Update(keyPress("down"))
+	if view := model.View(); !strings.Contains(view, "loop: Bash called 6 times in a row with identical input") {
+		t.Errorf("Rationale of selected process missing:\n%s", view)
+	}
+}
+
+// TestProcessStates tests the status column and sorting processes by urgency
+func TestProcessStates(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var model tea.Model = NewModel(config.Default(), false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+
+	model, _ = model.Update(processesMsg{
+		processes: []types.ClaudeProcess{{PID: 1}, {PID: 2}, {PID: 3}},
+		states: map[int32]monitor.SessionState{
+			1: monitor.StateWorking,
+			2: monitor.StateIdle,
+			3: monitor.StateWaitingPermission,
+		},
+	})
+	view := model.View()
+	for _, state := range []string{"working", "idle", "waiting for permission"} {
+		if !strings.Contains(view, state) {
+			t.Errorf("Process view missing state %q:\n%s", state, view)
+		}
+	}
+
+	order := func(m tea.Model) []int32 {
+		var pids []int32
+		for _, proc := range m.(Model).processes {
+			pids = append(pids, proc.PID)
+		}
+		return pids
+	}
+	if got := order(model); got[0] != 1 || got[2] != 3 {
+		t.Errorf("Default order: got %v, want by PID", got)
+	}
+
+	model, _ = model.Update(keyPress("s"))
+	if got := order(model); got[0] != 3 || got[1] != 1 || got[2] != 2 {
+		t.Errorf("Urgency order: got %v, want [3 1 2]", got)
+	}
+	if m := model.(Model); m.processes[m.selectedProcIdx].PID != 1 {
+		t.Errorf("Selection did not follow process 1 when sorting")
+	}
+}
+
+// TestTerminalNotices tests that OSC notifications are drawn with the frame,
+// once, instead of being written to the terminal from a background command
+func TestTerminalNotices(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	cfg := config.Default()
+	cfg.Notify.Backends = []string{"osc9"}
+	var model tea.Model = NewModel(cfg, false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+
+	model, cmd := model.Update(processesMsg{
+		processes: []types.ClaudeProcess{{PID: 1}},
+		events:    []monitor.SessionEvent{{Kind: monitor.EventTurnDone, WorkingDir: "/work/api", Text: "Turn finished"}},
+	})
+	if view := model.View(); !strings.HasPrefix(view, "\x1b]9;promptwatch: api: Turn finished\a") {
+		t.Fatalf("Frame does not lead with the notice: %q", view[:min(len(view), 60)])
+	}
+	if cmd == nil {
+		t.Fatal("No command clears the notice")
+	}
+
+	model, _ = model.Update(cmd())
+	if view := model.View(); strings.Contains(view, "\x1b]9;") {
+		t.Errorf("Notice still drawn after the hold")
+	}
+}
@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// editedColumn is a row of the column editor
+type editedColumn struct {
+	Key   string
+	Title string
+	Shown bool
+}
+
+// openColumnEditor lists the columns of the current table, visible ones
+// first in display order, then the hidden ones
+func (m *Model) openColumnEditor() {
+	m.columnTable = tableOf(m.viewMode)
+	visible, all := m.columnKeys(m.columnTable)
+	titles := make(map[string]string)
+	for _, spec := range slices.Concat(processColumns, projectColumns, sessionColumns(ColumnWidths{})) {
+		titles[spec.Key] = spec.Title
+	}
+
+	m.editedColumns = nil
+	for _, k := range visible {
+		m.editedColumns = append(m.editedColumns, editedColumn{Key: k, Title: titles[k], Shown: true})
+	}
+	for _, k := range all {
+		if !slices.Contains(visible, k) {
+			m.editedColumns = append(m.editedColumns, editedColumn{Key: k, Title: titles[k]})
+		}
+	}
+	m.selectedColumnIdx = 0
+	m.editingColumns = true
+}
+
+// updateColumnEditor handles a key while the column editor is open. Left and
+// right move the selected column, enter applies and saves the columns.
+func (m Model) updateColumnEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	cols, i := slices.Clone(m.editedColumns), m.selectedColumnIdx
+	m.editedColumns = cols
+	switch {
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Columns):
+		m.editingColumns = false
+	case key.Matches(msg, m.keys.Up):
+		m.selectedColumnIdx = max(i-1, 0)
+	case key.Matches(msg, m.keys.Down):
+		m.selectedColumnIdx = min(i+1, len(cols)-1)
+	case key.Matches(msg, m.keys.ToggleColumn):
+		cols[i].Shown = !cols[i].Shown
+	case key.Matches(msg, m.keys.Prev) && i > 0:
+		cols[i-1], cols[i] = cols[i], cols[i-1]
+		m.selectedColumnIdx--
+	case key.Matches(msg, m.keys.Next) && i < len(cols)-1:
+		cols[i], cols[i+1] = cols[i+1], cols[i]
+		m.selectedColumnIdx++
+	case key.Matches(msg, m.keys.Open):
+		m.applyColumns()
+	}
+	return m, nil
+}
+
+// applyColumns shows the edited columns and writes them to the config file.
+// Columns of an active saved view only change until the view is left.
+func (m *Model) applyColumns() {
+	var keys []string
+	for _, col := range m.editedColumns {
+		if col.Shown {
+			keys = append(keys, col.Key)
+		}
+	}
+	if len(keys) == 0 {
+		m.statusMessage = "Show at least one column"
+		m.statusIsError = true
+		return
+	}
+	m.editingColumns = false
+
+	switch m.columnTable {
+	case "processes":
+		m.columns.Processes = keys
+	case "projects":
+		m.columns.Projects = keys
+	case "sessions":
+		m.columns.Sessions = keys
+	}
+	m.rebuildTables()
+
+	if v, ok := m.config.View(m.activeView); ok && v.Mode == m.columnTable && len(v.Columns) > 0 {
+		m.statusMessage = fmt.Sprintf("Columns changed for view %s; edit its [[view]] table to keep them", v.Name)
+		m.statusIsError = false
+		return
+	}
+	if err := m.config.SaveColumns(m.columnTable, keys); err != nil {
+		m.statusMessage = "Columns not saved: " + err.Error()
+		m.statusIsError = true
+		return
+	}
+	m.statusMessage = fmt.Sprintf("Saved %s columns to the config file", m.columnTable)
+	m.statusIsError = false
+}
+
+// renderColumnEditor renders the columns of the edited table as an overlay
+func (m Model) renderColumnEditor() string {
+	var lines []string
+	for i, col := range m.editedColumns {
+		box := "[ ]"
+		if col.Shown {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("%s %-10s %s", box, col.Title, col.Key)
+		switch {
+		case i == m.selectedColumnIdx:
+			line = m.styles.SelectedRow.Render("▸ " + line)
+		case col.Shown:
+			line = m.styles.Text.Render("  " + line)
+		default:
+			line = m.styles.Muted.Render("  " + line)
+		}
+		lines = append(lines, line)
+	}
+
+	title := m.styles.Title.Render("Columns: " + m.columnTable)
+	hint := m.styles.Muted.Render(formatHints([]key.Help{
+		{Key: firstKey(m.keys.ToggleColumn), Desc: "Show/hide"},
+		{Key: firstKey(m.keys.Prev) + "/" + firstKey(m.keys.Next), Desc: "Move"},
+		{Key: firstKey(m.keys.Open), Desc: "Apply"},
+		{Key: firstKey(m.keys.Back), Desc: "Cancel"},
+	}))
+	box := m.styles.Box.Render(lipgloss.JoinVertical(lipgloss.Left, title, "", strings.Join(lines, "\n"), "", hint))
+
+	return lipgloss.Place(m.termWidth, m.termHeight, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// commitsMsg carries the git commits made during a session
+type commitsMsg struct {
+	commits []monitor.Commit
+	err     error
+}
+
+// commitCountsMsg carries the number of commits of session files (-1 = unknown)
+type commitCountsMsg struct {
+	counts map[string]int
+}
+
+// countVisibleCommits looks up the commits of the session rows on the current
+// page that have not been counted. Each lookup runs git, so sessions are
+// counted once they are shown rather than while the list loads.
+func (m *Model) countVisibleCommits() tea.Cmd {
+	if m.viewMode != ViewSessions || len(m.sessions) == 0 {
+		return nil
+	}
+	if visible, _ := m.columnKeys("sessions"); !slices.Contains(visible, "commits") {
+		return nil
+	}
+	if m.commitsPending == nil {
+		m.commitsPending = make(map[string]bool)
+	}
+	start, end := m.sessionTable.VisibleIndices()
+	var paths []string
+	for i := max(start, 0); i <= end && i < len(m.sessions); i++ {
+		path := m.sessions[i].Path
+		if !m.sessions[i].CommitsCounted && !m.commitsPending[path] {
+			m.commitsPending[path] = true
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		counts := make(map[string]int, len(paths))
+		for _, path := range paths {
+			counts[path] = -1
+			if commits, err := monitor.SessionFileCommits(path); err == nil {
+				counts[path] = len(commits)
+			}
+		}
+		return commitCountsMsg{counts: counts}
+	}
+}
+
+// openCommits switches to the commits made during the open session
+func (m *Model) openCommits() tea.Cmd {
+	stats, ok := m.sessionStats.(*monitor.SessionStats)
+	if !ok {
+		return nil
+	}
+	m.viewMode = ViewCommits
+	m.commits = nil
+	m.commitsError = ""
+	m.commitLines = nil
+	m.commitsScroll = 0
+	return func() tea.Msg {
+		commits, err := monitor.SessionFileCommits(stats.FilePath)
+		return commitsMsg{commits: commits, err: err}
+	}
+}
+
+// layoutCommits renders the commit list: one header per commit followed by
+// its files, with the files the session edited highlighted
+func (m *Model) layoutCommits() {
+	width := m.termWidth - 4
+	if width < 20 {
+		width = 20
+	}
+
+	var workingDir string
+	if stats, ok := m.sessionStats.(*monitor.SessionStats); ok {
+		for _, msg := range stats.MessageHistory {
+			if msg.WorkingDir != "" {
+				workingDir = msg.WorkingDir
+				break
+			}
+		}
+	}
+
+	m.commitLines = nil
+	for _, c := range m.commits {
+		header := m.styles.Accent.Render(c.ShortHash()) + " " +
+			m.styles.Muted.Render(c.Time.Local().Format("2006-01-02 15:04")+" "+c.Author) + " " +
+			m.styles.Text.Render(c.Subject)
+		m.commitLines = append(m.commitLines, ansi.Truncate(header, width, "…"))
+		for _, file := range c.Files {
+			path := file
+			if rel, err := filepath.Rel(workingDir, file); workingDir != "" && err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+			line := ansi.Truncate("    "+path, width-2, "…")
+			if c.IsEdited(file) {
+				m.commitLines = append(m.commitLines, m.styles.Highlight.Render(line+" ✎"))
+			} else {
+				m.commitLines = append(m.commitLines, m.styles.Faint.Render(line))
+			}
+		}
+		m.commitLines = append(m.commitLines, "")
+	}
+}
+
+// renderCommitsView displays the git commits made during the open session
+func (m Model) renderCommitsView() string {
+	title := "Commits"
+	if m.selectedSession != nil {
+		title += ": " + truncatePath(m.selectedSession.Title, 50)
+	}
+
+	var summary, content string
+	switch {
+	case m.commitsError != "":
+		content = m.styles.Error.Render("Error: " + m.commitsError)
+	case m.commits == nil:
+		content = m.styles.Muted.Render("Reading git log…")
+	case len(m.commits) == 0:
+		content = m.styles.Muted.Render("No commits were made during this session")
+	default:
+		edited := 0
+		for _, c := range m.commits {
+			if len(c.Edited) > 0 {
+				edited++
+			}
+		}
+		summary = fmt.Sprintf("%d commits · %d touch files the session edited (✎)", len(m.commits), edited)
+
+		pageHeight := m.historyPageHeight()
+		start := min(m.commitsScroll, len(m.commitLines))
+		end := min(start+pageHeight, len(m.commitLines))
+		content = strings.Join(m.commitLines[start:end], "\n")
+	}
+	return m.renderHistoryPage(title, summary, content)
+}
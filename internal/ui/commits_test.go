@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// TestCommitsView tests listing commits with the files the session edited marked
+func TestCommitsView(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var model tea.Model = NewModel(config.Default(), false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	m := model.(Model)
+	m.viewMode = ViewCommits
+	m.sessionStats = &monitor.SessionStats{MessageHistory: []monitor.Message{{WorkingDir: "/repo"}}}
+	model, _ = m.Update(commitsMsg{commits: []monitor.Commit{{
+		Hash:    "0123456789abcdef",
+		Author:  "Dev",
+		Time:    time.Date(2026, 1, 9, 14, 10, 0, 0, time.UTC),
+		Subject: "Fix parser",
+		Files:   []string{"/repo/parser.go", "/repo/README.md"},
+		Edited:  []string{"/repo/parser.go"},
+	}}})
+
+	view := model.(Model).View()
+	for _, want := range []string{"01234567", "Fix parser", "parser.go ✎", "1 commits · 1 touch files"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "README.md ✎") {
+		t.Errorf("README.md was not edited but is marked:\n%s", view)
+	}
+
+	model, _ = model.Update(commitsMsg{})
+	if view := model.(Model).View(); !strings.Contains(view, "No commits") {
+		t.Errorf("Expected empty message, got:\n%s", view)
+	}
+
+	model, _ = model.Update(keyPress("esc"))
+	if model.(Model).viewMode != ViewSessionDetail {
+		t.Errorf("Back returns to %v, want session detail", model.(Model).viewMode)
+	}
+}
+
+// TestCommitCounts tests that commits are counted only for the session rows
+// on screen, and each session once
+func TestCommitCounts(t *testing.T) {
+	var model tea.Model = NewModel(config.Default(), false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 160, Height: 12})
+	m := model.(Model)
+	m.viewMode = ViewSessions
+	var sessions []SessionInfo
+	for i := range 6 {
+		sessions = append(sessions, SessionInfo{ID: fmt.Sprint(i), Path: fmt.Sprintf("/missing/%d.jsonl", i), Commits: -1, LastMessageTime: int64(10 - i)})
+	}
+
+	model, cmd := m.Update(sessionsMsg{sessions: sessions})
+	loaded := model.(Model)
+	pageSize := loaded.sessionTable.PageSize()
+	if cmd == nil {
+		t.Fatal("No commits counted for the first page")
+	}
+	counts := cmd().(commitCountsMsg).counts
+	if len(counts) != pageSize || counts["/missing/0.jsonl"] != -1 {
+		t.Fatalf("Counted %v, want the %d sessions of the first page", counts, pageSize)
+	}
+	if _, cmd = model.Update(keyPress("down")); cmd != nil {
+		t.Error("Pending sessions counted again")
+	}
+
+	model, _ = model.Update(commitCountsMsg{counts: counts})
+	if m := model.(Model); !m.sessions[0].CommitsCounted || m.sessions[pageSize].CommitsCounted {
+		t.Errorf("Counted flags: %+v", m.sessions)
+	}
+	model, cmd = model.Update(keyPress("end"))
+	if cmd == nil {
+		t.Fatal("No commits counted for the last page")
+	}
+	if counts := cmd().(commitCountsMsg).counts; len(counts) != len(sessions)-pageSize {
+		t.Errorf("Counted %v on the last page", counts)
+	}
+}
@@ -0,0 +1,262 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// compareMsg carries the profiles of the sessions being compared
+type compareMsg struct {
+	profiles []monitor.SessionProfile
+	err      error
+}
+
+// compareSymbols mark each session's token curve, in marking order
+var compareSymbols = []string{"●", "▲", "■", "◆", "✚", "★"}
+
+// compareLabelWidth and compareColumnWidth size the comparison table
+const (
+	compareLabelWidth  = 16
+	compareColumnWidth = 18
+	compareChartHeight = 10
+	compareTools       = 8 // Tools listed in the tool mix
+)
+
+// toggleMark marks or unmarks the selected session for comparison
+func (m *Model) toggleMark() {
+	if m.selectedSessionIdx >= len(m.sessions) {
+		return
+	}
+	session := m.sessions[m.selectedSessionIdx]
+	if i := slices.IndexFunc(m.markedSessions, func(s SessionInfo) bool { return s.Path == session.Path }); i >= 0 {
+		m.markedSessions = slices.Delete(slices.Clone(m.markedSessions), i, i+1)
+	} else {
+		m.markedSessions = append(slices.Clone(m.markedSessions), session)
+	}
+	m.updateSessionTable()
+}
+
+// isMarked reports whether a session is marked for comparison
+func (m *Model) isMarked(path string) bool {
+	return slices.ContainsFunc(m.markedSessions, func(s SessionInfo) bool { return s.Path == path })
+}
+
+// openComparison switches to the side-by-side comparison of the marked sessions
+func (m *Model) openComparison() tea.Cmd {
+	if len(m.markedSessions) < 2 {
+		m.statusMessage = fmt.Sprintf("Mark at least two sessions with %s to compare them", firstKey(m.keys.Mark))
+		m.statusIsError = true
+		return nil
+	}
+	if len(m.markedSessions) > len(compareSymbols) {
+		m.statusMessage = fmt.Sprintf("At most %d sessions can be compared", len(compareSymbols))
+		m.statusIsError = true
+		return nil
+	}
+	m.viewMode = ViewCompare
+	m.compareProfiles = nil
+	m.compareError = ""
+	m.compareLines = nil
+	m.compareScroll = 0
+
+	paths := make([]string, len(m.markedSessions))
+	for i, s := range m.markedSessions {
+		paths[i] = s.Path
+	}
+	return func() tea.Msg {
+		profiles := make([]monitor.SessionProfile, len(paths))
+		for i, path := range paths {
+			stats, err := monitor.ParseSessionFile(path)
+			if err != nil {
+				return compareMsg{err: err}
+			}
+			profiles[i] = monitor.ProfileSession(stats)
+		}
+		return compareMsg{profiles: profiles}
+	}
+}
+
+// compareStyle returns the colour of the i-th compared session
+func (m Model) compareStyle(i int) lipgloss.Style {
+	styles := []lipgloss.Style{m.styles.Accent, m.styles.Warning, m.styles.OK, m.styles.Info, m.styles.Error, m.styles.Assistant}
+	return styles[i%len(styles)]
+}
+
+// layoutComparison renders the legend, the figures of each session side by
+// side, the tool mix and the cumulative token curves
+func (m *Model) layoutComparison() {
+	profiles := m.compareProfiles
+	m.compareLines = nil
+	if len(profiles) == 0 {
+		return
+	}
+	add := func(lines ...string) { m.compareLines = append(m.compareLines, lines...) }
+
+	for i, s := range m.markedSessions[:len(profiles)] {
+		title := s.Title
+		if title == "" {
+			title = s.FirstPrompt
+		}
+		legend := fmt.Sprintf("%s %c  %s  %s", compareSymbols[i], 'A'+i, profiles[i].Started.Local().Format("2006-01-02 15:04"), title)
+		add(m.compareStyle(i).Render(ansi.Truncate(legend, m.termWidth-4, "…")))
+	}
+	add("")
+
+	header := fmt.Sprintf("%-*s", compareLabelWidth, "")
+	for i := range profiles {
+		header += m.compareStyle(i).Render(fmt.Sprintf("%*s", compareColumnWidth, fmt.Sprintf("%s %c", compareSymbols[i], 'A'+i)))
+	}
+	add(header)
+
+	row := func(label string, value func(p monitor.SessionProfile) string) {
+		line := m.styles.Muted.Render(fmt.Sprintf("%-*s", compareLabelWidth, label))
+		for _, p := range profiles {
+			line += m.styles.Text.Render(fmt.Sprintf("%*s", compareColumnWidth, ansi.Truncate(value(p), compareColumnWidth-1, "…")))
+		}
+		add(line)
+	}
+	count := func(n func(p monitor.SessionProfile) int) func(p monitor.SessionProfile) string {
+		return func(p monitor.SessionProfile) string { return fmt.Sprintf("%d", n(p)) }
+	}
+	tokens := func(n func(p monitor.SessionProfile) int) func(p monitor.SessionProfile) string {
+		return func(p monitor.SessionProfile) string { return formatTokenCount(n(p)) }
+	}
+
+	row("Duration", func(p monitor.SessionProfile) string { return formatUptime(p.Duration) })
+	row("Turns", count(func(p monitor.SessionProfile) int { return p.Turns }))
+	row("Input tokens", tokens(func(p monitor.SessionProfile) int { return p.InputTokens }))
+	row("Output tokens", tokens(func(p monitor.SessionProfile) int { return p.OutputTokens }))
+	row("Cache write", tokens(func(p monitor.SessionProfile) int { return p.CacheCreation }))
+	row("Cache read", tokens(func(p monitor.SessionProfile) int { return p.CacheRead }))
+	row("Total tokens", tokens(monitor.SessionProfile.TotalTokens))
+	row("Cost", func(p monitor.SessionProfile) string { return fmt.Sprintf("$%.2f", p.Cost) })
+	row("Tool errors", count(func(p monitor.SessionProfile) int { return p.ToolErrors }))
+	row("API errors", count(func(p monitor.SessionProfile) int { return p.APIErrors }))
+	row("Compacts", count(func(p monitor.SessionProfile) int { return p.Compacts }))
+	for i := range slices.Max(modelCounts(profiles)) {
+		label := ""
+		if i == 0 {
+			label = "Models"
+		}
+		row(label, func(p monitor.SessionProfile) string {
+			if i < len(p.Models) {
+				return strings.TrimPrefix(p.Models[i], "claude-")
+			}
+			return ""
+		})
+	}
+
+	add("", m.styles.Title.Render("Tool mix"))
+	tools := compareToolNames(profiles)
+	for i, name := range tools {
+		if i == compareTools {
+			row(fmt.Sprintf("%d more", len(tools)-i), func(p monitor.SessionProfile) string {
+				others := 0
+				for _, name := range tools[i:] {
+					others += p.ToolCalls[name]
+				}
+				return fmt.Sprintf("%d", others)
+			})
+			break
+		}
+		row(ansi.Truncate(name, compareLabelWidth-1, "…"), count(func(p monitor.SessionProfile) int { return p.ToolCalls[name] }))
+	}
+	if len(tools) == 0 {
+		add(m.styles.Muted.Render("No tool calls"))
+	}
+
+	add("", m.styles.Title.Render("Cumulative tokens"))
+	add(m.tokenCurves(profiles, min(max(m.termWidth-compareLabelWidth-4, 20), 120))...)
+}
+
+// modelCounts returns the number of models each session used
+func modelCounts(profiles []monitor.SessionProfile) []int {
+	counts := []int{1}
+	for _, p := range profiles {
+		counts = append(counts, len(p.Models))
+	}
+	return counts
+}
+
+// compareToolNames returns the tools any session called, most called overall first
+func compareToolNames(profiles []monitor.SessionProfile) []string {
+	total := monitor.SessionProfile{ToolCalls: make(map[string]int)}
+	for _, p := range profiles {
+		for name, n := range p.ToolCalls {
+			total.ToolCalls[name] += n
+		}
+	}
+	return total.ToolNames()
+}
+
+// tokenCurves overlays the cumulative token use of the sessions over the time
+// since each started. Every column is a slice of the longest session; a
+// session's curve ends when it does.
+func (m Model) tokenCurves(profiles []monitor.SessionProfile, width int) []string {
+	var longest time.Duration
+	peak := 0
+	for _, p := range profiles {
+		longest = max(longest, p.Duration)
+		peak = max(peak, p.TotalTokens())
+	}
+	if peak == 0 {
+		return []string{m.styles.Muted.Render("No token usage recorded")}
+	}
+
+	grid := make([][]string, compareChartHeight)
+	for y := range grid {
+		grid[y] = slices.Repeat([]string{" "}, width)
+	}
+	for i, p := range profiles {
+		for x := range width {
+			elapsed := longest * time.Duration(x) / time.Duration(max(width-1, 1))
+			if elapsed > p.Duration && x > 0 {
+				break
+			}
+			level := p.TokensAt(elapsed) * (compareChartHeight - 1) / peak
+			grid[compareChartHeight-1-level][x] = m.compareStyle(i).Render(compareSymbols[i])
+		}
+	}
+
+	lines := make([]string, 0, compareChartHeight+1)
+	for y, cells := range grid {
+		label := ""
+		switch y {
+		case 0:
+			label = formatTokenCount(peak)
+		case compareChartHeight - 1:
+			label = "0"
+		}
+		lines = append(lines, m.styles.Muted.Render(fmt.Sprintf("%*s │", compareLabelWidth-2, label))+strings.Join(cells, ""))
+	}
+	end := formatUptime(longest)
+	axis := fmt.Sprintf("%*s └0%s%s", compareLabelWidth-2, "", strings.Repeat("─", max(width-1-len(end), 1)), end)
+	return append(lines, m.styles.Muted.Render(axis))
+}
+
+// renderCompareView displays the side-by-side comparison of the marked sessions
+func (m Model) renderCompareView() string {
+	title := fmt.Sprintf("Comparing %d sessions", len(m.markedSessions))
+
+	var summary, content string
+	switch {
+	case m.compareError != "":
+		content = m.styles.Error.Render("Error: " + m.compareError)
+	case m.compareProfiles == nil:
+		content = m.styles.Muted.Render("Reading sessions…")
+	default:
+		summary = "Curves show cumulative tokens over the time since each session started"
+		pageHeight := m.historyPageHeight()
+		start := min(m.compareScroll, len(m.compareLines))
+		end := min(start+pageHeight, len(m.compareLines))
+		content = strings.Join(m.compareLines[start:end], "\n")
+	}
+	return m.renderHistoryPage(title, summary, content)
+}
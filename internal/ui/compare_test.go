@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thieso2/promptwatch/internal/config"
+)
+
+// TestCompareSessions tests marking sessions and comparing them side by side
+func TestCompareSessions(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	dir := t.TempDir()
+	write := func(name, model, tool string, output int) string {
+		path := filepath.Join(dir, name)
+		data := `{"type":"user","timestamp":"2026-01-09T14:00:00.000Z","message":{"role":"user","content":"fix the build"}}
+{"type":"assistant","timestamp":"2026-01-09T14:05:00.000Z","message":{"role":"assistant","model":"` + model + `","content":[{"type":"tool_use","id":"t1","name":"` + tool + `","input":{}}],"usage":{"input_tokens":100,"cache_read_input_tokens":0,"cache_creation_input_tokens":0,"output_tokens":` + strings.Repeat("9", output) + `}}}
+`
+		os.WriteFile(path, []byte(data), 0644)
+		return path
+	}
+	a := write("a.jsonl", "claude-opus-4", "Bash", 3)
+	b := write("b.jsonl", "claude-sonnet-4", "Edit", 4)
+
+	var model tea.Model = NewModel(config.Default(), false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	m := model.(Model)
+	m.viewMode = ViewSessions
+	model, _ = m.Update(sessionsMsg{sessions: []SessionInfo{
+		{ID: "a", Path: a, Title: "prompt A", LastMessageTime: 2},
+		{ID: "b", Path: b, Title: "prompt B", LastMessageTime: 1},
+	}})
+
+	model, _ = model.Update(keyPress("x"))
+	model, cmd := model.Update(keyPress("="))
+	if m := model.(Model); cmd != nil || m.viewMode != ViewSessions || !m.statusIsError {
+		t.Fatalf("Compared a single session: mode %v, status %q", m.viewMode, m.statusMessage)
+	}
+
+	model, _ = model.Update(keyPress("down"))
+	model, _ = model.Update(keyPress("x"))
+	if view := model.View(); !strings.Contains(view, "2 marked") {
+		t.Errorf("Session list does not count marks:\n%s", view)
+	}
+	model, cmd = model.Update(keyPress("="))
+	if cmd == nil || model.(Model).viewMode != ViewCompare {
+		t.Fatalf("Comparison not opened")
+	}
+	model, _ = model.Update(cmd())
+
+	view := model.View()
+	for _, want := range []string{"● A", "▲ B", "prompt A", "opus-4", "sonnet-4", "Output tokens", "1k", "9k", "Bash", "Edit", "Cumulative tokens", "5m"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Comparison missing %q:\n%s", want, view)
+		}
+	}
+
+	model, _ = model.Update(keyPress("esc"))
+	if m := model.(Model); m.viewMode != ViewSessions || len(m.markedSessions) != 2 {
+		t.Errorf("Back: mode %v, %d marked", m.viewMode, len(m.markedSessions))
+	}
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// sparkLevels are the bar heights of the context chart, lowest first
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// compactMarker marks a compaction in the context chart
+const compactMarker = "┃"
+
+// contextCell formats a process's context window use
+func (m *Model) contextCell(percent float64) table.StyledCell {
+	if percent <= 0 {
+		return table.NewStyledCell("-", m.styles.Muted)
+	}
+	return table.NewStyledCell(fmt.Sprintf("%.0f%%", percent), m.contextStyle(percent))
+}
+
+// formatTokenCount renders a token count compactly, e.g. "950", "12k", "1.2M"
+func formatTokenCount(tokens int) string {
+	switch {
+	case tokens < 1000:
+		return fmt.Sprintf("%d", tokens)
+	case tokens < 1_000_000:
+		return fmt.Sprintf("%dk", tokens/1000)
+	}
+	return fmt.Sprintf("%.1fM", float64(tokens)/1_000_000)
+}
+
+// renderContextLine summarises the open session's context window use: a chart
+// of every turn with compactions marked, the current use and the turns left
+// before the next auto-compact. It is empty for sessions without usage data.
+func (m Model) renderContextLine() string {
+	usage := m.contextUsage
+	if len(usage.Samples) == 0 {
+		return ""
+	}
+
+	summary := fmt.Sprintf(" %s/%s (%.0f%%)", formatTokenCount(usage.Current()), formatTokenCount(usage.Limit), usage.Percent())
+	switch turns := usage.TurnsUntilCompact(); {
+	case turns == 0:
+		summary += " · auto-compact due"
+	case turns > 0:
+		summary += fmt.Sprintf(" · ~%d turns to auto-compact at %s", turns, formatTokenCount(usage.CompactAt()))
+	}
+	if len(usage.Compacts) > 0 {
+		summary += fmt.Sprintf(" · %d compacts", len(usage.Compacts))
+	}
+
+	width := max(m.termWidth-len("Context ")-len([]rune(summary))-2, 10)
+	return m.styles.Muted.Render("Context ") + m.contextChart(usage, min(width, 60)) + m.styles.Muted.Render(summary)
+}
+
+// contextChart draws a sparkline of the context use per turn scaled to the
+// context window, at most width columns wide. Turns are merged into columns
+// by their largest value when there are more turns than columns.
+func (m Model) contextChart(usage monitor.ContextUsage, width int) string {
+	n := len(usage.Samples)
+	columns := min(n, width)
+	compactsAt := make(map[int]bool)
+	for _, idx := range usage.CompactIndexes() {
+		compactsAt[idx*columns/max(n, 1)] = true
+	}
+
+	var b strings.Builder
+	for col := 0; col < columns; col++ {
+		if compactsAt[col] {
+			b.WriteString(m.styles.Accent.Render(compactMarker))
+		}
+		peak := 0
+		for i := col * n / columns; i < (col+1)*n/columns; i++ {
+			peak = max(peak, usage.Samples[i].Tokens)
+		}
+		percent := float64(peak) * 100 / float64(usage.Limit)
+		level := min(int(percent*float64(len(sparkLevels))/100), len(sparkLevels)-1)
+		b.WriteString(m.contextStyle(percent).Render(string(sparkLevels[level])))
+	}
+	if compactsAt[columns] {
+		b.WriteString(m.styles.Accent.Render(compactMarker))
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/monitor"
+	"github.com/thieso2/promptwatch/internal/types"
+)
+
+// TestContextUtilisation tests the context chart in the session header and the process column
+func TestContextUtilisation(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var model tea.Model = NewModel(config.Default(), false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	model, _ = model.Update(processesMsg{processes: []types.ClaudeProcess{
+		{PID: 1, WorkingDir: "/work/api", ContextPercent: 85.4},
+		{PID: 2, WorkingDir: "/work/web"},
+	}})
+	view := model.View()
+	if !strings.Contains(view, "CTX%") || !strings.Contains(view, "85%") {
+		t.Errorf("Process view missing context column:\n%s", view)
+	}
+
+	start := time.Date(2026, 1, 9, 14, 0, 0, 0, time.UTC)
+	stats := &monitor.SessionStats{Compacts: []monitor.CompactEvent{{Time: start.Add(150 * time.Second), Trigger: "manual"}}}
+	for i, tokens := range []int{20_000, 40_000, 60_000, 10_000, 30_000, 50_000} {
+		stats.MessageHistory = append(stats.MessageHistory, monitor.Message{
+			Role:      "assistant",
+			Type:      "assistant_response",
+			Content:   "reply",
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			CacheRead: tokens,
+		})
+	}
+
+	m := model.(Model)
+	m.viewMode = ViewSessionDetail
+	model, _ = m.Update(sessionDetailMsg{stats: stats})
+	view = model.View()
+	for _, want := range []string{"Context ▁▂▃┃▁▂▃", "50k/200k (25%)", "~7 turns to auto-compact at 184k", "1 compacts"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Session header missing %q:\n%s", want, view)
+		}
+	}
+}
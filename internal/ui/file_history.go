@@ -0,0 +1,262 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/evertras/bubble-table/table"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// historyFileColumns lists the tracked files table columns
+var historyFileColumns = []columnSpec{
+	{Key: "path", Title: "FILE", Width: 30, Flex: 1},
+	{Key: "checkpoints", Title: "CHECKPOINTS", Width: 13},
+	{Key: "versions", Title: "VERSIONS", Width: 10},
+	{Key: "state", Title: "LAST STATE", Width: 12},
+}
+
+// checkpointColumns lists the checkpoints table columns of one file
+var checkpointColumns = []columnSpec{
+	{Key: "mark", Title: " ", Width: 3},
+	{Key: "num", Title: "#", Width: 5},
+	{Key: "time", Title: "TIME", Width: 20},
+	{Key: "version", Title: "VERSION", Width: 9},
+	{Key: "state", Title: "STATE", Width: 8},
+	{Key: "prompt", Title: "PROMPT", Width: 20, Flex: 1},
+}
+
+// createHistoryFilesTableWithWidth creates the tracked files table sized for the width
+func createHistoryFilesTableWithWidth(width int) table.Model {
+	return newTable(layoutColumns(historyFileColumns, width-6))
+}
+
+// createCheckpointsTableWithWidth creates the checkpoints table sized for the width
+func createCheckpointsTableWithWidth(width int) table.Model {
+	return newTable(layoutColumns(checkpointColumns, width-6))
+}
+
+// openFileHistory switches to the tracked files of the open session
+func (m *Model) openFileHistory() {
+	stats, ok := m.sessionStats.(*monitor.SessionStats)
+	if !ok {
+		return
+	}
+	m.fileHistory = monitor.NewFileHistory(stats)
+	m.historyFile = ""
+	m.selectedHistoryFileIdx = 0
+	m.viewMode = ViewFileHistory
+	m.updateHistoryFilesTable()
+}
+
+// openCheckpoints switches to the checkpoints of the selected tracked file
+func (m *Model) openCheckpoints() {
+	files := m.fileHistory.Files()
+	if m.selectedHistoryFileIdx >= len(files) {
+		return
+	}
+	m.historyFile = files[m.selectedHistoryFileIdx]
+	m.historyVersions = m.fileHistory.Versions(m.historyFile)
+	m.selectedVersionIdx = len(m.historyVersions) - 1 // Latest first in focus
+	m.markedVersionIdx = -1
+	m.viewMode = ViewCheckpoints
+	m.updateCheckpointsTable()
+}
+
+// updateHistoryFilesTable refills the tracked files table
+func (m *Model) updateHistoryFilesTable() {
+	if m.fileHistory == nil {
+		return
+	}
+	files := m.fileHistory.Files()
+	rows := make([]table.Row, len(files))
+	for i, path := range files {
+		versions := m.fileHistory.Versions(path)
+		distinct := 0
+		for j, v := range versions {
+			if j == 0 || v.Backup.BackupFileName != versions[j-1].Backup.BackupFileName {
+				distinct++
+			}
+		}
+		rows[i] = table.NewRow(table.RowData{
+			"path":        truncatePath(path, 80),
+			"checkpoints": fmt.Sprintf("%d", len(versions)),
+			"versions":    fmt.Sprintf("%d", distinct),
+			"state":       backupState(versions[len(versions)-1].Backup),
+		})
+	}
+
+	m.selectedHistoryFileIdx = clampIndex(m.selectedHistoryFileIdx, len(files))
+	m.historyFilesTable = m.historyFilesTable.WithRows(rows).WithHighlightedRow(m.selectedHistoryFileIdx)
+}
+
+// updateCheckpointsTable refills the checkpoints table of the open file
+func (m *Model) updateCheckpointsTable() {
+	rows := make([]table.Row, len(m.historyVersions))
+	for i, v := range m.historyVersions {
+		mark := ""
+		if i == m.markedVersionIdx {
+			mark = "◆"
+		}
+		rows[i] = table.NewRow(table.RowData{
+			"mark":    mark,
+			"num":     fmt.Sprintf("%d", v.Checkpoint+1),
+			"time":    m.fileHistory.Checkpoints[v.Checkpoint].Timestamp.Local().Format("2006-01-02 15:04:05"),
+			"version": fmt.Sprintf("v%d", v.Backup.Version),
+			"state":   backupState(v.Backup),
+			"prompt":  strings.Join(strings.Fields(m.fileHistory.Prompts[v.Checkpoint]), " "),
+		})
+	}
+
+	m.selectedVersionIdx = clampIndex(m.selectedVersionIdx, len(m.historyVersions))
+	m.checkpointsTable = m.checkpointsTable.WithRows(rows).WithHighlightedRow(m.selectedVersionIdx)
+}
+
+// backupState describes whether a file existed at a checkpoint
+func backupState(b monitor.FileBackup) string {
+	if b.Exists() {
+		return "saved"
+	}
+	return "absent"
+}
+
+// showCheckpoint shows the open file at the checkpoint version idx. With diff
+// set it shows the changes from the marked version, or from the previous one.
+func (m *Model) showCheckpoint(idx int, diff bool) {
+	if idx < 0 || idx >= len(m.historyVersions) {
+		return
+	}
+	m.selectedVersionIdx = idx
+	m.checkpointsTable = m.checkpointsTable.WithHighlightedRow(idx)
+	m.historyDiff = diff
+	m.historyScroll = 0
+	m.viewMode = ViewCheckpointContent
+	m.layoutCheckpoint()
+}
+
+// layoutCheckpoint renders the content or diff shown in ViewCheckpointContent
+func (m *Model) layoutCheckpoint() {
+	if m.fileHistory == nil || m.selectedVersionIdx >= len(m.historyVersions) {
+		m.historyLines = nil
+		return
+	}
+	width := m.termWidth - 4
+	if width < 20 {
+		width = 20
+	}
+	version := m.historyVersions[m.selectedVersionIdx]
+	content, exists, err := m.fileHistory.Content(m.historyFile, version.Checkpoint)
+	if err != nil {
+		m.historyLines = []string{m.styles.Error.Render("Error: " + err.Error())}
+		return
+	}
+
+	if !m.historyDiff {
+		note := fmt.Sprintf("at checkpoint %d (v%d)", version.Checkpoint+1, version.Backup.Version)
+		m.historyLines = []string{m.renderFileHeader(m.historyFile, note), ""}
+		if !exists {
+			m.historyLines = append(m.historyLines, m.styles.Muted.Render("(file did not exist yet)"))
+			return
+		}
+		code := highlightCode(expandTabs(content), m.historyFile, m.theme.Syntax)
+		gutter := len(fmt.Sprint(len(code)))
+		for i, line := range code {
+			number := m.styles.Muted.Render(fmt.Sprintf("%*d │ ", gutter, i+1))
+			m.historyLines = append(m.historyLines, ansi.Truncate(number+line, width, "…"))
+		}
+		return
+	}
+
+	base := m.markedVersionIdx
+	if base < 0 || base == m.selectedVersionIdx {
+		base = m.selectedVersionIdx - 1
+	}
+	if base < 0 {
+		m.historyLines = []string{m.renderFileHeader(m.historyFile, ""), "",
+			m.styles.Muted.Render("(first tracked checkpoint: nothing to compare with)")}
+		return
+	}
+	from := m.historyVersions[base]
+	oldContent, _, err := m.fileHistory.Content(m.historyFile, from.Checkpoint)
+	if err != nil {
+		m.historyLines = []string{m.styles.Error.Render("Error: " + err.Error())}
+		return
+	}
+	note := fmt.Sprintf("checkpoint %d → %d", from.Checkpoint+1, version.Checkpoint+1)
+	m.historyLines = append([]string{m.renderFileHeader(m.historyFile, note), ""},
+		m.renderDiff(oldContent, content, width)...)
+}
+
+// historyPageHeight returns the number of content lines shown per page
+func (m Model) historyPageHeight() int {
+	// Title (1) + blank (1) + blank (1) + scroll info (1) + footer (1) + margin
+	if h := m.termHeight - 8; h > 5 {
+		return h
+	}
+	return 5
+}
+
+// renderFileHistoryView displays the tracked files of a session
+func (m Model) renderFileHistoryView() string {
+	title := "File history"
+	if m.selectedSession != nil {
+		title += ": " + truncatePath(m.selectedSession.Title, 50)
+	}
+	summary := ""
+	var content string
+	switch {
+	case m.fileHistory == nil || len(m.fileHistory.Checkpoints) == 0:
+		content = m.styles.Muted.Render("This session has no file-history checkpoints")
+	case len(m.fileHistory.Files()) == 0:
+		content = m.styles.Muted.Render("No files were tracked in this session")
+	default:
+		summary = fmt.Sprintf("%d checkpoints · %d tracked files", len(m.fileHistory.Checkpoints), len(m.fileHistory.Files()))
+		if m.fileHistory.Dir == "" {
+			summary += " · backups not found"
+		}
+		content = m.historyFilesTable.View()
+	}
+	return m.renderHistoryPage(title, summary, content)
+}
+
+// renderCheckpointsView displays the checkpoints of one tracked file
+func (m Model) renderCheckpointsView() string {
+	summary := fmt.Sprintf("%d checkpoints", len(m.historyVersions))
+	if m.markedVersionIdx >= 0 && m.markedVersionIdx < len(m.historyVersions) {
+		summary += fmt.Sprintf(" · diff base: checkpoint %d", m.historyVersions[m.markedVersionIdx].Checkpoint+1)
+	}
+	return m.renderHistoryPage("📄 "+m.historyFile, summary, m.checkpointsTable.View())
+}
+
+// renderCheckpointContentView displays a file at a checkpoint, or a diff of two checkpoints
+func (m Model) renderCheckpointContentView() string {
+	pageHeight := m.historyPageHeight()
+	end := m.historyScroll + pageHeight
+	if end > len(m.historyLines) {
+		end = len(m.historyLines)
+	}
+	start := m.historyScroll
+	if start > end {
+		start = end
+	}
+
+	info := fmt.Sprintf("Lines %d-%d of %d", start+1, end, len(m.historyLines))
+	if m.historyDiff {
+		info += " · diff"
+	}
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		strings.Join(m.historyLines[start:end], "\n"), "", m.styles.Muted.Render(info))
+	return m.renderHistoryPage("File history", "", content)
+}
+
+// renderHistoryPage lays out a file history view: title, summary, content and footer
+func (m Model) renderHistoryPage(title, summary, content string) string {
+	header := m.styles.Title.Render(title)
+	if summary != "" {
+		header = lipgloss.JoinVertical(lipgloss.Left, header, m.styles.Muted.Render(summary))
+	}
+	footer := m.styles.Muted.Render(formatHints(m.keys.ShortHelp(m.viewMode)))
+	return lipgloss.JoinVertical(lipgloss.Left, header, "", content, "", footer)
+}
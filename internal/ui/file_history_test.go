@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// TestFileHistoryViews tests browsing checkpoints of a file and diffing them
+func TestFileHistoryViews(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	root := t.TempDir()
+	monitor.SetDataRoots([]monitor.DataRoot{monitor.NewDataRoot("test", root)})
+	defer monitor.SetDataRoots(nil)
+
+	backupDir := filepath.Join(root, "file-history", "sess-1")
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	for name, content := range map[string]string{"a@v1": "alpha\n", "a@v2": "alpha\nbeta\n", "a@v3": "gamma\nbeta\n"} {
+		if err := os.WriteFile(filepath.Join(backupDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write backup: %v", err)
+		}
+	}
+
+	at := time.Date(2026, 1, 9, 14, 0, 0, 0, time.UTC)
+	checkpoint := func(n int, backup string) monitor.FileCheckpoint {
+		return monitor.FileCheckpoint{
+			MessageID: "p" + backup,
+			Timestamp: at.Add(time.Duration(n) * time.Minute),
+			Backups:   map[string]monitor.FileBackup{"notes.txt": {Path: "notes.txt", BackupFileName: backup, Version: n}},
+		}
+	}
+
+	var model tea.Model = NewModel(config.Default(), false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m := model.(Model)
+	m.viewMode = ViewSessionDetail
+	m.sessionStats = &monitor.SessionStats{
+		FilePath:    filepath.Join(root, "projects", "-repo", "sess-1.jsonl"),
+		Checkpoints: []monitor.FileCheckpoint{checkpoint(1, "a@v1"), checkpoint(2, "a@v2"), checkpoint(3, "a@v3")},
+	}
+
+	model, _ = m.Update(keyPress("H"))
+	if view := model.(Model).View(); !strings.Contains(view, "notes.txt") || !strings.Contains(view, "3 checkpoints") {
+		t.Errorf("File history view missing file or summary:\n%s", view)
+	}
+
+	// The latest checkpoint is selected first
+	model, _ = model.Update(keyPress("enter"))
+	model, _ = model.Update(keyPress("enter"))
+	content := strings.Join(model.(Model).historyLines, "\n")
+	if !strings.Contains(content, "gamma") || !strings.Contains(content, "checkpoint 3") {
+		t.Errorf("Expected checkpoint 3 content:\n%s", content)
+	}
+
+	model, _ = model.Update(keyPress("left"))
+	if content := strings.Join(model.(Model).historyLines, "\n"); !strings.Contains(content, "alpha") || strings.Contains(content, "gamma") {
+		t.Errorf("Expected checkpoint 2 content after stepping back:\n%s", content)
+	}
+
+	// Mark checkpoint 1 and diff checkpoint 3 against it
+	model, _ = model.Update(keyPress("esc"))
+	model, _ = model.Update(keyPress("home"))
+	model, _ = model.Update(keyPress("x"))
+	model, _ = model.Update(keyPress("end"))
+	model, _ = model.Update(keyPress("D"))
+	diff := strings.Join(model.(Model).historyLines, "\n")
+	for _, want := range []string{"checkpoint 1 → 3", "-alpha", "+gamma", "+beta"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("Diff missing %q:\n%s", want, diff)
+		}
+	}
+
+	for _, want := range []ViewMode{ViewCheckpoints, ViewFileHistory, ViewSessionDetail} {
+		model, _ = model.Update(keyPress("esc"))
+		if got := model.(Model).viewMode; got != want {
+			t.Errorf("Back: got view %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/evertras/bubble-table/table"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// fileActivityMsg carries a loaded file activity report
+type fileActivityMsg struct {
+	report monitor.FileActivityReport
+	err    error
+}
+
+// fileColumns lists the file activity table columns
+var fileColumns = []columnSpec{
+	{Key: "path", Title: "FILE", Width: 30, Flex: 1},
+	{Key: "reads", Title: "READS", Width: 7},
+	{Key: "edits", Title: "EDITS", Width: 7},
+	{Key: "added", Title: "+LINES", Width: 8},
+	{Key: "removed", Title: "-LINES", Width: 8},
+	{Key: "sessions", Title: "SESSIONS", Width: 10},
+	{Key: "lasttouch", Title: "LAST TOUCH", Width: 18},
+}
+
+// createFilesTableWithWidth creates the file activity table sized for the width
+func createFilesTableWithWidth(width int) table.Model {
+	return newTable(layoutColumns(fileColumns, width-6))
+}
+
+// openFileActivity switches to the file activity view of the current session,
+// or of every listed session when opened from the session list
+func (m *Model) openFileActivity() tea.Cmd {
+	m.filesSourceMode = m.viewMode
+	m.viewMode = ViewFiles
+	m.selectedFileIdx = 0
+	m.fileReport = nil
+	m.filesError = ""
+
+	if m.filesSourceMode == ViewSessionDetail {
+		stats, _ := m.sessionStats.(*monitor.SessionStats)
+		m.filesTitle = "Files in session"
+		if m.selectedSession != nil {
+			m.filesTitle += ": " + truncatePath(m.selectedSession.Title, 50)
+		}
+		return func() tea.Msg {
+			if stats == nil {
+				return fileActivityMsg{err: fmt.Errorf("no session loaded")}
+			}
+			return fileActivityMsg{report: monitor.SessionFileActivity(stats)}
+		}
+	}
+
+	m.filesTitle = fmt.Sprintf("Files in %d sessions", len(m.sessions))
+	paths := make([]string, len(m.sessions))
+	for i, s := range m.sessions {
+		paths[i] = s.Path
+	}
+	return func() tea.Msg {
+		report, err := monitor.LoadFileActivity(paths)
+		return fileActivityMsg{report: report, err: err}
+	}
+}
+
+// fileRows returns the rows of the file activity view: files, or directories when grouped
+func (m Model) fileRows() []monitor.FileActivity {
+	if m.fileReport == nil {
+		return nil
+	}
+	rows := m.fileReport.Files
+	if m.filesDepth > 0 {
+		rows = m.fileReport.ByDirectory(m.filesDepth)
+	}
+	monitor.SortFileActivity(rows, monitor.FileSortKeys[m.filesSort])
+	return rows
+}
+
+// updateFilesTable refills the file activity table
+func (m *Model) updateFilesTable() {
+	files := m.fileRows()
+	rows := make([]table.Row, len(files))
+	for i, file := range files {
+		path := file.Path
+		if m.filesDepth == 0 {
+			path = m.fileReport.RelativePath(path)
+		}
+		rows[i] = table.NewRow(table.RowData{
+			"path":      truncatePath(path, 80),
+			"reads":     fmt.Sprintf("%d", file.Reads),
+			"edits":     fmt.Sprintf("%d", file.Edits),
+			"added":     table.NewStyledCell(fmt.Sprintf("+%d", file.LinesAdded), m.styles.OK),
+			"removed":   table.NewStyledCell(fmt.Sprintf("-%d", file.LinesRemoved), m.styles.Error),
+			"sessions":  fmt.Sprintf("%d", file.Sessions),
+			"lasttouch": file.LastTouch.Local().Format("2006-01-02 15:04"),
+		})
+	}
+
+	m.selectedFileIdx = clampIndex(m.selectedFileIdx, len(files))
+	m.filesTable = m.filesTable.WithRows(rows).WithHighlightedRow(m.selectedFileIdx)
+}
+
+// renderFilesView displays the file activity report
+func (m Model) renderFilesView() string {
+	headerTitle := m.styles.Title.Render(m.filesTitle)
+
+	grouping := "files"
+	if m.filesDepth > 0 {
+		grouping = fmt.Sprintf("directories (depth %d)", m.filesDepth)
+	}
+	summary := fmt.Sprintf("by %s · %s", monitor.FileSortKeys[m.filesSort], grouping)
+	if m.fileReport != nil {
+		summary = fmt.Sprintf("%d files · %s", len(m.fileReport.Files), summary)
+		if m.fileReport.WorkingDir != "" {
+			summary += " · relative to " + truncatePath(m.fileReport.WorkingDir, 40)
+		}
+	}
+	headerLine := lipgloss.JoinVertical(lipgloss.Left, headerTitle, m.styles.Muted.Render(summary))
+
+	footer := m.styles.Muted.Render(formatHints(m.keys.ShortHelp(m.viewMode)))
+
+	var content string
+	switch {
+	case m.filesError != "":
+		content = m.styles.Error.Render("Error: " + m.filesError)
+	case m.fileReport == nil:
+		content = m.styles.Muted.Render("Loading file activity…")
+	case len(m.fileReport.Files) == 0:
+		content = m.styles.Muted.Render("No files were read or changed")
+	default:
+		content = m.filesTable.View()
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, headerLine, "", content, "", footer)
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// TestFileActivityView tests opening the file activity view from session detail, sorting and grouping
+func TestFileActivityView(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var model tea.Model = NewModel(config.Default(), false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	at := time.Date(2026, 1, 9, 14, 0, 0, 0, time.UTC)
+	m := model.(Model)
+	m.viewMode = ViewSessionDetail
+	m.selectedSession = &SessionInfo{Title: "Refactor"}
+	m.sessionStats = &monitor.SessionStats{MessageHistory: []monitor.Message{
+		{Role: "assistant", ToolName: "Read", ToolInput: `{"file_path":"/repo/cmd/main.go"}`, WorkingDir: "/repo", Timestamp: at},
+		{Role: "assistant", ToolName: "Edit", ToolInput: `{"file_path":"/repo/internal/ui/view.go","old_string":"a","new_string":"b\nc"}`, WorkingDir: "/repo", Timestamp: at},
+		{Role: "assistant", ToolName: "Read", ToolInput: `{"file_path":"/repo/internal/ui/view.go"}`, WorkingDir: "/repo", Timestamp: at},
+	}}
+
+	model, cmd := m.Update(keyPress("F"))
+	if model.(Model).viewMode != ViewFiles {
+		t.Fatalf("Expected file activity view, got %v", model.(Model).viewMode)
+	}
+	model, _ = model.Update(cmd())
+
+	view := model.(Model).View()
+	for _, want := range []string{"Files in session: Refactor", "internal/ui/view.go", "cmd/main.go", "by churn"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View missing %q:\n%s", want, view)
+		}
+	}
+	if rows := model.(Model).fileRows(); rows[0].Path != "/repo/internal/ui/view.go" {
+		t.Errorf("Expected edited file first by churn, got %s", rows[0].Path)
+	}
+
+	model, _ = model.Update(keyPress("s"))
+	if !strings.Contains(model.(Model).View(), "by edits") {
+		t.Errorf("Sort key does not cycle to edits")
+	}
+
+	model, _ = model.Update(keyPress("d"))
+	rows := model.(Model).fileRows()
+	if len(rows) != 2 || rows[0].Path != "internal/" || rows[1].Path != "cmd/" {
+		t.Errorf("Expected internal/ and cmd/ at depth 1, got %+v", rows)
+	}
+
+	model, _ = model.Update(keyPress("esc"))
+	if model.(Model).viewMode != ViewSessionDetail {
+		t.Errorf("Back returns to %v, want session detail", model.(Model).viewMode)
+	}
+}
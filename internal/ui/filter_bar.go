@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/thieso2/promptwatch/internal/filter"
+)
+
+// openFilterBar starts editing the filter of the session list or session detail view
+func (m *Model) openFilterBar() {
+	m.filterInput = textinput.New()
+	m.filterInput.Prompt = "/"
+	m.filterInput.Placeholder = "tool:Bash error:true, model:opus cost>0.5, after:7d …"
+	m.filterInput.SetCursorMode(textinput.CursorStatic)
+	if m.viewMode == ViewSessions {
+		m.filterInput.SetValue(m.sessionFilter.String())
+	} else {
+		m.filterInput.SetValue(m.messageFilter.String())
+	}
+	m.filterInput.Focus()
+	m.filterInput.CursorEnd()
+	m.filterEditing = true
+	m.filterError = ""
+}
+
+// updateFilterBar handles a key while the filter bar is open. Enter applies
+// the expression, esc closes the bar without changing the filter.
+func (m Model) updateFilterBar(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.filterEditing = false
+		m.filterError = ""
+		return m, nil
+	case tea.KeyEnter:
+		if err := m.applyFilter(m.filterInput.Value()); err != nil {
+			m.filterError = err.Error()
+			return m, nil
+		}
+		m.filterEditing = false
+		m.filterError = ""
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	m.filterError = ""
+	return m, cmd
+}
+
+// applyFilter parses an expression and filters the current view with it
+func (m *Model) applyFilter(src string) error {
+	if m.viewMode == ViewSessions {
+		expr, err := filter.ParseSessions(src)
+		if err != nil {
+			return err
+		}
+		m.sessionFilter = expr
+		m.selectedSessionIdx = 0
+		m.updateSessionTable()
+		return nil
+	}
+
+	expr, err := filter.ParseMessages(src)
+	if err != nil {
+		return err
+	}
+	m.messageFilter = expr
+	m.selectedMessageIdx = 0
+	m.updateMessageTable()
+	if expr.IsEmpty() {
+		m.messageError = fmt.Sprintf("Showing all %d messages", m.filteredMessageCount)
+	} else {
+		m.messageError = fmt.Sprintf("Showing %d messages matching %s", m.filteredMessageCount, expr)
+	}
+	return nil
+}
+
+// renderFilterBar renders the filter prompt and the parse error, if any
+func (m Model) renderFilterBar() string {
+	bar := m.filterInput.View()
+	if m.filterError != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, bar, m.styles.Error.Render(m.filterError))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, bar, m.styles.Muted.Render("enter apply · esc cancel · empty clears"))
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/filter"
+)
+
+// TestSessionFilterBar tests entering, rejecting and clearing a session filter
+func TestSessionFilterBar(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var model tea.Model = NewModel(config.Default(), false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	m := model.(Model)
+	m.viewMode = ViewSessions
+	model, _ = m.Update(sessionsMsg{sessions: []SessionInfo{
+		{ID: "s1", Title: "Migration", LastMessageTime: 2, Summary: filter.Session{Tools: []string{"Bash", "Edit"}, Cost: 2}},
+		{ID: "s2", Title: "Docs", LastMessageTime: 1, Summary: filter.Session{Tools: []string{"Read"}, Cost: 0.1}},
+	}})
+
+	steps := []struct {
+		key  string
+		want string
+	}{
+		{"/", "enter apply · esc cancel"},
+		{"cots>1", "cots>1"},
+		{"enter", `unknown field "cots"`},
+		{"ctrl+u", ""},
+		{"tool:Bash cost>1", "tool:Bash cost>1"},
+		{"enter", "Filter: tool:Bash cost>1 · 1 of 2 sessions"},
+		{"/", "/tool:Bash cost>1"},
+		{"esc", "1 of 2 sessions"},
+	}
+	for _, step := range steps {
+		model, _ = model.Update(keyPress(step.key))
+		if view := model.(Model).View(); !strings.Contains(view, step.want) {
+			t.Errorf("After %q: view missing %q:\n%s", step.key, step.want, view)
+		}
+	}
+
+	m = model.(Model)
+	if m.filterEditing || m.viewMode != ViewSessions {
+		t.Errorf("Esc should close the bar and stay in the session list")
+	}
+	if len(m.sessions) != 1 || m.sessions[0].ID != "s1" {
+		t.Errorf("Filtered sessions = %+v, want s1", m.sessions)
+	}
+
+	// An empty expression shows every session again
+	model, _ = model.Update(keyPress("/"))
+	model, _ = model.Update(keyPress("ctrl+u"))
+	model, _ = model.Update(keyPress("enter"))
+	if got := len(model.(Model).sessions); got != 2 {
+		t.Errorf("Clearing the filter shows %d sessions, want 2", got)
+	}
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// highlightCode highlights code with the chroma style of the theme, picking
+// the language from filename (or the content when the name gives no hint).
+// It returns one string per line; with an empty style the lines are plain.
+func highlightCode(code, filename, style string) []string {
+	code = strings.TrimSuffix(code, "\n")
+	if style == "" {
+		return strings.Split(code, "\n")
+	}
+
+	lexer := lexers.Match(filename)
+	if lexer == nil {
+		lexer = lexers.Analyse(code)
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	return highlightWith(chroma.Coalesce(lexer), code, style)
+}
+
+// highlightLanguage highlights code written in a named language, e.g. "bash"
+func highlightLanguage(code, language, style string) []string {
+	code = strings.TrimSuffix(code, "\n")
+	lexer := lexers.Get(language)
+	if style == "" || lexer == nil {
+		return strings.Split(code, "\n")
+	}
+	return highlightWith(chroma.Coalesce(lexer), code, style)
+}
+
+// highlightWith formats each line separately so colours survive splitting
+// tokens that span lines, such as block comments
+func highlightWith(lexer chroma.Lexer, code, style string) []string {
+	iterator, err := lexer.Tokenise(nil, code)
+	if err != nil {
+		return strings.Split(code, "\n")
+	}
+	formatter := formatters.Get("terminal256")
+
+	var lines []string
+	for _, tokens := range chroma.SplitTokensIntoLines(iterator.Tokens()) {
+		// The line break stays in the last token; the caller joins lines itself
+		if last := len(tokens) - 1; last >= 0 {
+			tokens[last].Value = strings.TrimSuffix(tokens[last].Value, "\n")
+		}
+		var sb strings.Builder
+		if err := formatter.Format(&sb, styles.Get(style), chroma.Literator(tokens...)); err != nil {
+			return strings.Split(code, "\n")
+		}
+		lines = append(lines, sb.String())
+	}
+	return lines
+}
@@ -0,0 +1,377 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyMap holds the key bindings of every view
+type KeyMap struct {
+	Quit key.Binding
+	Back key.Binding
+	Open key.Binding
+	Help key.Binding
+
+	// Navigation
+	Up       key.Binding
+	Down     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Home     key.Binding
+	End      key.Binding
+	Prev     key.Binding // Previous message in message detail
+	Next     key.Binding // Next message in message detail
+
+	// Process and project views
+	Refresh        key.Binding
+	ToggleHelpers  key.Binding
+	ToggleProjects key.Binding
+	Tools          key.Binding // Also in the session view
+	UsageHistory   key.Binding
+
+	// Session list and session detail views
+	FileActivity key.Binding
+	Filter       key.Binding // Open the filter expression prompt
+	Views        key.Binding // Saved view picker, also in the process and project views
+
+	// Process, project, session list and session detail views
+	Sort        key.Binding // Sort by the next column; also in the files and tools views
+	ReverseSort key.Binding
+	Columns     key.Binding // Column editor (not in the session detail view)
+
+	// Column editor
+	ToggleColumn key.Binding
+
+	// Session list
+	Compare key.Binding // Compare the sessions marked with Mark
+
+	// Session detail view
+	FileHistory     key.Binding
+	Commits         key.Binding
+	FilterUser      key.Binding
+	FilterAssistant key.Binding
+	FilterAll       key.Binding
+
+	// Message detail view
+	ToggleMarkdown key.Binding
+
+	// File activity view
+	GroupDirs key.Binding
+
+	// Tools view
+	FilterModel key.Binding
+	FilterRange key.Binding
+
+	// File history views
+	Mark key.Binding // Mark a checkpoint as diff base, or a session to compare
+	Diff key.Binding // Diff checkpoints, or the two marked sessions
+}
+
+// defaultPreset holds the keys of every action
+var defaultPreset = map[string][]string{
+	"quit":             {"q", "ctrl+c"},
+	"back":             {"esc"},
+	"open":             {"enter"},
+	"help":             {"?"},
+	"up":               {"up", "k"},
+	"down":             {"down", "j"},
+	"page_up":          {"pgup"},
+	"page_down":        {"pgdown"},
+	"home":             {"home"},
+	"end":              {"end"},
+	"prev":             {"left"},
+	"next":             {"right"},
+	"refresh":          {"r"},
+	"toggle_helpers":   {"f"},
+	"toggle_projects":  {"p"},
+	"filter_user":      {"u"},
+	"filter_assistant": {"a"},
+	"filter_all":       {"b"},
+	"filter":           {"/"},
+	"views":            {"v"},
+	"sort":             {"s"},
+	"reverse_sort":     {"S"},
+	"columns":          {"c"},
+	"toggle_column":    {" "},
+	"toggle_markdown":  {"m"},
+	"file_activity":    {"F"},
+	"group_dirs":       {"d"},
+	"file_history":     {"H"},
+	"commits":          {"C"},
+	"tools":            {"T"},
+	"usage_history":    {"U"},
+	"filter_model":     {"M"},
+	"filter_range":     {"R"},
+	"mark":             {"x"},
+	"diff":             {"D"},
+	"compare":          {"="},
+}
+
+// keyPresets holds the actions each preset binds differently from the default
+var keyPresets = map[string]map[string][]string{
+	"default": {},
+	"vim": {
+		"back":      {"esc", "backspace"},
+		"page_up":   {"ctrl+u", "ctrl+b", "pgup"},
+		"page_down": {"ctrl+d", "ctrl+f", "pgdown"},
+		"home":      {"g", "home"},
+		"end":       {"G", "end"},
+		"prev":      {"h", "left"},
+		"next":      {"l", "right"},
+	},
+	"emacs": {
+		"back":      {"ctrl+g", "esc"},
+		"up":        {"ctrl+p", "up"},
+		"down":      {"ctrl+n", "down"},
+		"page_up":   {"alt+v", "pgup"},
+		"page_down": {"ctrl+v", "pgdown"},
+		"home":      {"alt+<", "home"},
+		"end":       {"alt+>", "end"},
+		"prev":      {"ctrl+b", "left"},
+		"next":      {"ctrl+f", "right"},
+	},
+}
+
+// actionHelp describes each action in the help overlay
+var actionHelp = map[string]string{
+	"quit":             "quit",
+	"back":             "back",
+	"open":             "open",
+	"help":             "toggle help",
+	"up":               "up",
+	"down":             "down",
+	"page_up":          "page up",
+	"page_down":        "page down",
+	"home":             "first",
+	"end":              "last",
+	"prev":             "previous message",
+	"next":             "next message",
+	"refresh":          "refresh",
+	"toggle_helpers":   "toggle MCP helpers",
+	"toggle_projects":  "processes/projects",
+	"filter_user":      "user prompts only",
+	"filter_assistant": "Claude responses only",
+	"filter_all":       "all messages",
+	"filter":           "filter expression",
+	"views":            "saved views",
+	"sort":             "cycle sort column",
+	"reverse_sort":     "reverse sort",
+	"columns":          "edit columns",
+	"toggle_column":    "show/hide column",
+	"toggle_markdown":  "raw/rendered markdown",
+	"file_activity":    "file activity",
+	"group_dirs":       "group by directory",
+	"file_history":     "file history",
+	"commits":          "git commits",
+	"tools":            "tool usage",
+	"usage_history":    "resource usage history",
+	"filter_model":     "cycle model filter",
+	"filter_range":     "cycle date range",
+	"mark":             "mark diff base or session",
+	"diff":             "diff checkpoints or marked sessions",
+	"compare":          "compare marked sessions",
+}
+
+// keySymbols shortens key names in help text
+var keySymbols = map[string]string{
+	"up":     "↑",
+	"down":   "↓",
+	"left":   "←",
+	"right":  "→",
+	"pgup":   "PgUp",
+	"pgdown": "PgDn",
+	"home":   "Home",
+	"end":    "End",
+	" ":      "space",
+}
+
+// NewKeyMap builds the bindings of a preset ("default", "vim" or "emacs").
+// Overrides replace the keys of individual actions; an empty list unbinds one.
+func NewKeyMap(preset string, overrides map[string][]string) KeyMap {
+	var k KeyMap
+	for action, binding := range k.actions() {
+		keys := defaultPreset[action]
+		if presetKeys, ok := keyPresets[preset][action]; ok {
+			keys = presetKeys
+		}
+		if overrideKeys, ok := overrides[action]; ok {
+			keys = overrideKeys
+		}
+
+		*binding = key.NewBinding(
+			key.WithKeys(keys...),
+			key.WithHelp(helpKey(keys), actionHelp[action]),
+		)
+		if len(keys) == 0 {
+			binding.SetEnabled(false)
+		}
+	}
+	return k
+}
+
+// actions maps config action names to the bindings they configure
+func (k *KeyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"quit":             &k.Quit,
+		"back":             &k.Back,
+		"open":             &k.Open,
+		"help":             &k.Help,
+		"up":               &k.Up,
+		"down":             &k.Down,
+		"page_up":          &k.PageUp,
+		"page_down":        &k.PageDown,
+		"home":             &k.Home,
+		"end":              &k.End,
+		"prev":             &k.Prev,
+		"next":             &k.Next,
+		"refresh":          &k.Refresh,
+		"toggle_helpers":   &k.ToggleHelpers,
+		"toggle_projects":  &k.ToggleProjects,
+		"filter_user":      &k.FilterUser,
+		"filter_assistant": &k.FilterAssistant,
+		"filter_all":       &k.FilterAll,
+		"filter":           &k.Filter,
+		"views":            &k.Views,
+		"sort":             &k.Sort,
+		"reverse_sort":     &k.ReverseSort,
+		"columns":          &k.Columns,
+		"toggle_column":    &k.ToggleColumn,
+		"toggle_markdown":  &k.ToggleMarkdown,
+		"file_activity":    &k.FileActivity,
+		"group_dirs":       &k.GroupDirs,
+		"file_history":     &k.FileHistory,
+		"commits":          &k.Commits,
+		"tools":            &k.Tools,
+		"usage_history":    &k.UsageHistory,
+		"filter_model":     &k.FilterModel,
+		"filter_range":     &k.FilterRange,
+		"mark":             &k.Mark,
+		"diff":             &k.Diff,
+		"compare":          &k.Compare,
+	}
+}
+
+// helpKey renders keys for help text, e.g. "↑/k"
+func helpKey(keys []string) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		if symbol, ok := keySymbols[k]; ok {
+			k = symbol
+		}
+		names[i] = k
+	}
+	return strings.Join(names, "/")
+}
+
+// firstKey returns the display name of a binding's primary key
+func firstKey(b key.Binding) string {
+	if len(b.Keys()) == 0 {
+		return ""
+	}
+	return helpKey(b.Keys()[:1])
+}
+
+// FullHelp returns the bindings active in mode, grouped into columns for the help overlay
+func (k KeyMap) FullHelp(mode ViewMode) [][]key.Binding {
+	navigation := []key.Binding{k.Up, k.Down, k.PageUp, k.PageDown, k.Home, k.End}
+	general := []key.Binding{k.Help, k.Quit}
+
+	switch mode {
+	case ViewProcesses:
+		return [][]key.Binding{navigation, {k.Open, k.Refresh, k.ToggleHelpers, k.ToggleProjects, k.Tools, k.UsageHistory, k.Views}, {k.Sort, k.ReverseSort, k.Columns}, general}
+	case ViewProjects:
+		return [][]key.Binding{navigation, {k.Open, k.ToggleProjects, k.Tools, k.UsageHistory, k.Views}, {k.Sort, k.ReverseSort, k.Columns}, general}
+	case ViewSessions:
+		return [][]key.Binding{navigation, {k.Open, k.Filter, k.Views, k.FileActivity, k.Tools, k.Back}, {k.Sort, k.ReverseSort, k.Columns, k.Mark, k.Compare, k.Diff}, general}
+	case ViewSessionDetail:
+		return [][]key.Binding{navigation, {k.Open, k.FileActivity, k.FileHistory, k.Commits, k.Back}, {k.Filter, k.FilterUser, k.FilterAssistant, k.FilterAll, k.Sort, k.ReverseSort, k.Views}, general}
+	case ViewMessageDetail:
+		return [][]key.Binding{navigation, {k.Prev, k.Next, k.ToggleMarkdown, k.Back}, general}
+	case ViewFiles:
+		return [][]key.Binding{navigation, {k.Sort, k.GroupDirs, k.Back}, general}
+	case ViewFileHistory:
+		return [][]key.Binding{navigation, {k.Open, k.Back}, general}
+	case ViewCheckpoints:
+		return [][]key.Binding{navigation, {k.Open, k.Mark, k.Diff, k.Back}, general}
+	case ViewCheckpointContent:
+		return [][]key.Binding{navigation, {k.Prev, k.Next, k.Diff, k.Back}, general}
+	case ViewCommits:
+		return [][]key.Binding{navigation, {k.Back}, general}
+	case ViewTools:
+		return [][]key.Binding{navigation, {k.Sort, k.FilterModel, k.FilterRange, k.Back}, general}
+	case ViewUsageHistory:
+		return [][]key.Binding{navigation, {k.FilterRange, k.Refresh, k.Back}, general}
+	case ViewCompare:
+		return [][]key.Binding{navigation, {k.Back}, general}
+	case ViewSessionDiff:
+		return [][]key.Binding{navigation, {k.Prev, k.Next, k.Back}, general}
+	}
+	return [][]key.Binding{general}
+}
+
+// ShortHelp returns the footer hints for mode, using each action's primary key
+func (k KeyMap) ShortHelp(mode ViewMode) []key.Help {
+	hint := func(b key.Binding, desc string) key.Help {
+		return key.Help{Key: firstKey(b), Desc: desc}
+	}
+	pair := func(a, b key.Binding, desc string) key.Help {
+		var keys []string
+		for _, k := range []string{firstKey(a), firstKey(b)} {
+			if k != "" {
+				keys = append(keys, k)
+			}
+		}
+		return key.Help{Key: strings.Join(keys, "/"), Desc: desc}
+	}
+	navigate := pair(k.Up, k.Down, "Navigate")
+	scroll := []key.Help{pair(k.Up, k.Down, "Scroll"), pair(k.PageUp, k.PageDown, "Page"), pair(k.Home, k.End, "Jump")}
+
+	var hints []key.Help
+	switch mode {
+	case ViewProcesses:
+		hints = []key.Help{navigate, hint(k.Open, "View sessions"), hint(k.ToggleProjects, "Projects"),
+			hint(k.Sort, "Sort"), hint(k.Refresh, "Refresh"), hint(k.ToggleHelpers, "Toggle helpers")}
+	case ViewProjects:
+		hints = []key.Help{navigate, hint(k.Open, "View sessions"), hint(k.ToggleProjects, "Processes"), hint(k.Sort, "Sort")}
+	case ViewSessions:
+		hints = []key.Help{navigate, hint(k.Open, "Open"), hint(k.Filter, "Filter"), hint(k.Sort, "Sort"), hint(k.Mark, "Mark"), hint(k.Compare, "Compare"), hint(k.Diff, "Diff"),
+			hint(k.Views, "Views"), hint(k.FileActivity, "Files"), hint(k.Tools, "Tools"), hint(k.Back, "Back")}
+	case ViewSessionDetail:
+		hints = append(scroll[:3:3], hint(k.Filter, "Filter"), hint(k.FilterUser, "User"), hint(k.FilterAssistant, "Assistant"),
+			hint(k.FilterAll, "Both"), hint(k.Sort, "Sort"), hint(k.FileActivity, "Files"), hint(k.FileHistory, "History"), hint(k.Commits, "Commits"), hint(k.Back, "Back"))
+	case ViewMessageDetail:
+		hints = []key.Help{scroll[0], pair(k.Prev, k.Next, "Prev/Next"), scroll[1], scroll[2],
+			hint(k.ToggleMarkdown, "Raw"), hint(k.Back, "Back")}
+	case ViewFiles:
+		hints = []key.Help{navigate, hint(k.Sort, "Sort"), hint(k.GroupDirs, "Group dirs"), hint(k.Back, "Back")}
+	case ViewFileHistory:
+		hints = []key.Help{navigate, hint(k.Open, "Checkpoints"), hint(k.Back, "Back")}
+	case ViewCheckpoints:
+		hints = []key.Help{navigate, hint(k.Open, "View"), hint(k.Mark, "Mark"), hint(k.Diff, "Diff"), hint(k.Back, "Back")}
+	case ViewCheckpointContent:
+		hints = []key.Help{scroll[0], pair(k.Prev, k.Next, "Prev/Next"), scroll[1], hint(k.Diff, "Diff/Content"), hint(k.Back, "Back")}
+	case ViewCommits, ViewCompare:
+		hints = []key.Help{scroll[0], scroll[1], scroll[2], hint(k.Back, "Back")}
+	case ViewSessionDiff:
+		hints = []key.Help{scroll[0], pair(k.Prev, k.Next, "Prev/Next change"), scroll[1], scroll[2], hint(k.Back, "Back")}
+	case ViewTools:
+		hints = []key.Help{navigate, hint(k.Sort, "Sort"), hint(k.FilterModel, "Model"), hint(k.FilterRange, "Range"), hint(k.Back, "Back")}
+	case ViewUsageHistory:
+		hints = []key.Help{navigate, hint(k.FilterRange, "Range"), hint(k.Refresh, "Reload"), hint(k.Back, "Back")}
+	}
+	return append(hints, hint(k.Help, "Help"), hint(k.Quit, "Quit"))
+}
+
+// formatHints joins footer hints as "key: Desc  |  key: Desc", skipping unbound actions
+func formatHints(hints []key.Help) string {
+	var parts []string
+	for _, h := range hints {
+		if h.Key == "" {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", h.Key, h.Desc))
+	}
+	return strings.Join(parts, "  |  ")
+}
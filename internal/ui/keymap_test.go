@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/types"
+)
+
+// keyPress builds the key message bubbletea delivers for a key name
+func keyPress(name string) tea.KeyMsg {
+	named := map[string]tea.KeyType{
+		"up":     tea.KeyUp,
+		"down":   tea.KeyDown,
+		"left":   tea.KeyLeft,
+		"right":  tea.KeyRight,
+		" ":      tea.KeySpace,
+		"esc":    tea.KeyEsc,
+		"enter":  tea.KeyEnter,
+		"ctrl+n": tea.KeyCtrlN,
+		"ctrl+d": tea.KeyCtrlD,
+		"ctrl+u": tea.KeyCtrlU,
+	}
+	if t, ok := named[name]; ok {
+		return tea.KeyMsg{Type: t}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}
+}
+
+// TestNewKeyMap tests presets and per-action overrides
+func TestNewKeyMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		preset    string
+		overrides map[string][]string
+		binding   func(KeyMap) key.Binding
+		press     string
+		want      bool
+	}{
+		{"default arrow", "default", nil, func(k KeyMap) key.Binding { return k.Down }, "down", true},
+		{"default j", "default", nil, func(k KeyMap) key.Binding { return k.Down }, "j", true},
+		{"vim page down", "vim", nil, func(k KeyMap) key.Binding { return k.PageDown }, "ctrl+d", true},
+		{"vim keeps filters", "vim", nil, func(k KeyMap) key.Binding { return k.FilterUser }, "u", true},
+		{"emacs down", "emacs", nil, func(k KeyMap) key.Binding { return k.Down }, "ctrl+n", true},
+		{"emacs has no j", "emacs", nil, func(k KeyMap) key.Binding { return k.Down }, "j", false},
+		{"override replaces", "default", map[string][]string{"refresh": {"R"}},
+			func(k KeyMap) key.Binding { return k.Refresh }, "r", false},
+		{"override binds", "default", map[string][]string{"refresh": {"R"}},
+			func(k KeyMap) key.Binding { return k.Refresh }, "R", true},
+		{"empty override unbinds", "default", map[string][]string{"sort": {}},
+			func(k KeyMap) key.Binding { return k.Sort }, "s", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := NewKeyMap(tt.preset, tt.overrides)
+			if got := key.Matches(keyPress(tt.press), tt.binding(keys)); got != tt.want {
+				t.Errorf("Matches(%q): got %v, want %v", tt.press, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestHelpReflectsBindings tests that help text follows remapped keys
+func TestHelpReflectsBindings(t *testing.T) {
+	keys := NewKeyMap("default", map[string][]string{"refresh": {"R"}})
+
+	if got := keys.Refresh.Help().Key; got != "R" {
+		t.Errorf("Refresh help key: got %q, want %q", got, "R")
+	}
+	if got := keys.Down.Help().Key; got != "↓/j" {
+		t.Errorf("Down help key: got %q, want %q", got, "↓/j")
+	}
+
+	footer := formatHints(keys.ShortHelp(ViewProcesses))
+	want := "↑/↓: Navigate  |  enter: View sessions  |  p: Projects  |  s: Sort  |  R: Refresh  |  f: Toggle helpers  |  ?: Help  |  q: Quit"
+	if footer != want {
+		t.Errorf("Footer:\ngot  %q\nwant %q", footer, want)
+	}
+
+	// Only bindings of the current view appear in the overlay
+	for _, group := range keys.FullHelp(ViewProcesses) {
+		for _, b := range group {
+			if b.Help().Desc == actionHelp["mark"] {
+				t.Errorf("Mark binding listed in process view help")
+			}
+		}
+	}
+}
+
+// TestFooterHints tests that the footer lists the filter key and leaves out
+// pairs only when neither action is bound
+func TestFooterHints(t *testing.T) {
+	keys := NewKeyMap("default", map[string][]string{"prev": {}, "next": {}, "page_up": {}})
+
+	for _, mode := range []ViewMode{ViewSessions, ViewSessionDetail} {
+		if footer := formatHints(keys.ShortHelp(mode)); !strings.Contains(footer, "/: Filter") {
+			t.Errorf("Footer of %v lacks the filter hint: %q", mode, footer)
+		}
+	}
+
+	footer := formatHints(keys.ShortHelp(ViewSessionDiff))
+	if strings.Contains(footer, "Prev/Next") {
+		t.Errorf("Unbound pair listed: %q", footer)
+	}
+	if !strings.Contains(footer, "  PgDn: Page  ") {
+		t.Errorf("Half-bound pair missing: %q", footer)
+	}
+}
+
+// TestNavigationUsesKeymap tests that the update loop follows the configured preset
+func TestNavigationUsesKeymap(t *testing.T) {
+	cfg := config.Default()
+	cfg.UI.Keymap = "emacs"
+
+	var model tea.Model = NewModel(cfg, false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(processesMsg{processes: make([]types.ClaudeProcess, 3)})
+
+	for _, press := range []string{"ctrl+n", "ctrl+n", "j"} {
+		model, _ = model.Update(keyPress(press))
+	}
+	if got := model.(Model).selectedProcIdx; got != 2 {
+		t.Errorf("selectedProcIdx: got %d, want 2 (j is not bound in emacs preset)", got)
+	}
+
+	model, _ = model.Update(keyPress("?"))
+	if !strings.Contains(model.View(), "toggle MCP helpers") {
+		t.Errorf("Help overlay does not list process view bindings")
+	}
+	model, _ = model.Update(keyPress("esc"))
+	if model.(Model).showHelp {
+		t.Errorf("Help overlay still open after key press")
+	}
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+)
+
+// renderMarkdown renders markdown for the terminal, wrapped to width, using a
+// glamour standard style ("dark", "light" or "notty")
+func renderMarkdown(content string, width int, style string) (string, error) {
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(style),
+		glamour.WithWordWrap(width),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := renderer.Render(content)
+	if err != nil {
+		return "", err
+	}
+	// glamour pads the document with blank lines; the view adds its own spacing
+	return strings.Trim(out, "\n"), nil
+}
+
+// wrapWords wraps text at word boundaries so no line exceeds width,
+// keeping blank lines as paragraph breaks
+func wrapWords(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		currentLine := words[0]
+		for _, word := range words[1:] {
+			if len(currentLine)+1+len(word) <= width {
+				currentLine += " " + word
+			} else {
+				lines = append(lines, currentLine)
+				currentLine = word
+			}
+		}
+		lines = append(lines, currentLine)
+	}
+	return lines
+}
+
+// isMarkdownMessage reports whether a message's content is rendered as markdown
+func isMarkdownMessage(role, toolName string) bool {
+	return role == "assistant" && toolName == ""
+}
+
+// layoutDetail wraps or renders the content of the message detail view for
+// the current width. It runs whenever the message, width, theme or raw toggle changes.
+func (m *Model) layoutDetail() {
+	m.detailLines = nil
+	msg := m.detailMessage
+	if msg == nil {
+		return
+	}
+
+	// Use 80 chars or terminal width, whichever is smaller
+	maxWidth := 80
+	if m.termWidth > 0 && m.termWidth < 80 {
+		maxWidth = m.termWidth - 2
+	}
+
+	if msg.Type == "tool_result" {
+		m.detailLines = m.renderToolResult(msg, maxWidth)
+		return
+	}
+
+	// Add tool info if this is a tool call
+	if msg.ToolName != "" {
+		m.detailLines = append(m.detailLines, m.styles.Tool.Render("🔧 "+strings.ToUpper(msg.ToolName)))
+
+		if msg.ToolInput != "" {
+			m.detailLines = append(m.detailLines, "")
+			m.detailLines = append(m.detailLines, m.renderToolInput(msg.ToolName, msg.ToolInput, maxWidth)...)
+		}
+
+		// Add separator before content
+		if msg.Content != "" {
+			m.detailLines = append(m.detailLines, "")
+		}
+	}
+
+	if isMarkdownMessage(msg.Role, msg.ToolName) && !m.rawMarkdown {
+		rendered, err := renderMarkdown(msg.Content, maxWidth, m.theme.Markdown)
+		if err == nil {
+			m.detailLines = append(m.detailLines, strings.Split(rendered, "\n")...)
+			return
+		}
+		// Fall back to raw text if the markdown cannot be rendered
+	}
+
+	for _, line := range wrapWords(msg.Content, maxWidth) {
+		m.detailLines = append(m.detailLines, m.styles.Text.Render(line))
+	}
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+const sampleMarkdown = "# Migration plan\n\n" +
+	"Run the **schema** migration first, then backfill the new column for every existing row in the table.\n\n" +
+	"- add `user_id` column\n- backfill\n\n" +
+	"| step | time |\n|------|------|\n| add | 1s |\n\n" +
+	"```go\nfmt.Println(\"done\")\n```\n"
+
+// TestRenderMarkdown tests that markdown syntax is rendered and wrapped
+func TestRenderMarkdown(t *testing.T) {
+	rendered, err := renderMarkdown(sampleMarkdown, 40, "dark")
+	if err != nil {
+		t.Fatalf("Failed to render markdown: %v", err)
+	}
+	out := ansi.Strip(rendered)
+
+	for _, want := range []string{"Migration plan", "user_id", "fmt.Println"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Rendered output does not contain %q:\n%s", want, out)
+		}
+	}
+	for _, raw := range []string{"**schema**", "```", "|------|"} {
+		if strings.Contains(out, raw) {
+			t.Errorf("Rendered output still contains markdown syntax %q:\n%s", raw, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if w := lipgloss.Width(line); w > 40 {
+			t.Errorf("Line wider than 40 columns (%d): %q", w, line)
+		}
+	}
+}
+
+// TestLayoutDetail tests re-rendering on resize and the raw markdown toggle
+func TestLayoutDetail(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var model tea.Model = NewModel(config.Default(), false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	m := model.(Model)
+	m.viewMode = ViewMessageDetail
+	m.detailMessage = &monitor.Message{Role: "assistant", Content: sampleMarkdown}
+	m.layoutDetail()
+	wide := len(m.detailLines)
+
+	model, _ = m.Update(tea.WindowSizeMsg{Width: 30, Height: 40})
+	if narrow := len(model.(Model).detailLines); narrow <= wide {
+		t.Errorf("Expected more lines after narrowing the terminal: got %d, had %d", narrow, wide)
+	}
+
+	model, _ = model.Update(keyPress("m"))
+	raw := strings.Join(model.(Model).detailLines, "\n")
+	if !strings.Contains(raw, "```go") {
+		t.Errorf("Raw toggle does not show markdown source:\n%s", raw)
+	}
+
+	model, _ = model.Update(keyPress("m"))
+	if strings.Contains(strings.Join(model.(Model).detailLines, "\n"), "```go") {
+		t.Errorf("Second toggle does not render markdown again")
+	}
+}
@@ -1,16 +1,23 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/evertras/bubble-table/table"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/filter"
 	"github.com/thieso2/promptwatch/internal/monitor"
+	"github.com/thieso2/promptwatch/internal/notify"
 	"github.com/thieso2/promptwatch/internal/types"
 )
 
@@ -33,6 +40,11 @@ type SessionInfo struct {
 	OutputTokens    int    // Total output tokens
 	LastMessage     string // Last message in the session
 	LastMessageTime int64  // Unix timestamp of last message
+	Commits         int    // Git commits made during the session (-1 = unknown)
+	CommitsCounted  bool   // Commits has been looked up
+	Root            string // Label of the data root the session came from
+
+	Summary filter.Session // Values session filters test
 }
 
 // MessageRow represents a message for display in the message card view
@@ -63,63 +75,90 @@ const (
 	ViewSessions
 	ViewSessionDetail
 	ViewMessageDetail
+	ViewFiles
+	ViewFileHistory       // Tracked files of a session
+	ViewCheckpoints       // Checkpoints of one tracked file
+	ViewCheckpointContent // File content or diff at a checkpoint
+	ViewCommits           // Git commits made during a session
+	ViewTools             // Tool usage statistics
+	ViewUsageHistory      // Recorded CPU, memory and agents per project
+	ViewCompare           // Side-by-side comparison of marked sessions
+	ViewSessionDiff       // Turn-by-turn diff of two marked sessions
 )
 
 // ProjectDir represents a project directory with metadata
 type ProjectDir struct {
-	Name        string
-	Path        string
-	DisplayName string // Human-readable project name
-	Modified    time.Time
-	Sessions    int // Count of session files
+	Name         string
+	Path         string
+	DisplayName  string // Human-readable project name
+	OriginalPath string // Working directory the project was recorded for
+	Root         string // Label of the data root the project came from
+	Modified     time.Time
+	Sessions     int // Count of session files
 }
 
-type MessageFilter int
-
-const (
-	FilterAll MessageFilter = iota
-	FilterUserOnly
-	FilterAssistantOnly
-)
-
 // Model represents the main UI state
 type Model struct {
+	// Settings from config.toml
+	config        *config.Config
+	keys          KeyMap
+	theme         Theme
+	styles        Styles
+	renderers     *RendererRegistry
+	showHelp      bool   // Help overlay is open
+	statusMessage string // One-off notice such as a config reload result
+	statusIsError bool
+
 	// Main view
 	table          table.Model
 	processes      []types.ClaudeProcess
+	alerts         map[int32][]monitor.Alert      // Health alerts by PID
+	states         map[int32]monitor.SessionState // Session states by PID
+	watchdog       *monitor.Watchdog              // Shared by copies of the model
+	events         *monitor.EventWatcher          // Shared by copies of the model
+	notifier       notify.Notifier                // Command and webhook backends; nil when none is configured
+	terminalNotify bool                           // Bell or OSC backends are configured
+	notices        string                         // Their escape sequences, drawn with the next frames
+	noticesGen     int                            // Counts queued notices so only the latest clears them
 	lastUpdate     time.Time
 	updateInterval time.Duration
 	showHelpers    bool
 	quitting       bool
-	sortColumn     string
-	sortAscending  bool
+	processSort    tableSort
 
 	// Projects view
 	projectsTable   table.Model
 	projects        []ProjectDir
 	selectedProjIdx int
 	projectsError   string
+	projectSort     tableSort
 
 	// Session view
 	viewMode           ViewMode
 	selectedProcIdx    int
 	selectedProc       *types.ClaudeProcess
 	sessionTable       table.Model
-	sessions           []SessionInfo
+	sessions           []SessionInfo // Sessions passing sessionFilter
+	allSessions        []SessionInfo
+	sessionFilter      *filter.Expr
 	sessionError       string
 	selectedSessionIdx int
 	sessionSourceMode  ViewMode // Track whether ViewSessions came from ViewProcesses or ViewProjects
+	sessionsAll        bool     // The list holds the sessions of every project (saved sessions view)
+	sessionSort        tableSort
 
 	// Session detail view
 	selectedSession      *SessionInfo
 	sessionStats         interface{} // Will hold *monitor.SessionStats
+	contextUsage         monitor.ContextUsage
 	messageTable         table.Model
 	messages             []MessageRow
 	messageError         string
 	messageViewport      viewport.Model // Viewport for message card scrolling
-	messageFilter        MessageFilter  // Filter for messages
+	messageFilter        *filter.Expr   // Filter for messages; nil shows all
 	filteredMessageCount int            // Count of currently filtered messages
 	selectedMessageIdx   int            // Index of selected message for detail view
+	messageSort          tableSort      // Order of the message cards
 
 	// Terminal dimensions
 	termWidth  int
@@ -128,12 +167,92 @@ type Model struct {
 	// Message detail view
 	detailMessage      *monitor.Message // Full message being displayed
 	detailScrollOffset int              // Scroll position in message detail
+	detailLines        []string         // Wrapped or rendered content of detailMessage
+	rawMarkdown        bool             // Show assistant responses as raw markdown
+
+	// File activity view
+	filesTable      table.Model
+	fileReport      *monitor.FileActivityReport
+	filesTitle      string
+	filesSourceMode ViewMode // View to return to: ViewSessions or ViewSessionDetail
+	filesSort       int      // Index into monitor.FileSortKeys
+	filesDepth      int      // Directory grouping depth (0 = individual files)
+	selectedFileIdx int
+	filesError      string
+
+	// File history views
+	fileHistory            *monitor.FileHistory
+	historyFilesTable      table.Model
+	checkpointsTable       table.Model
+	selectedHistoryFileIdx int
+	historyFile            string                // Tracked file whose checkpoints are shown
+	historyVersions        []monitor.FileVersion // States of historyFile
+	selectedVersionIdx     int
+	markedVersionIdx       int      // Version diffs are taken from (-1 = previous version)
+	historyLines           []string // Rendered content or diff
+	historyScroll          int
+	historyDiff            bool // Content view shows a diff
+
+	// Commits view
+	commits        []monitor.Commit // nil while loading
+	commitsError   string
+	commitLines    []string
+	commitsScroll  int
+	commitsPending map[string]bool // Session files whose commits are being counted
+
+	// Tools view
+	toolsTable      table.Model
+	toolCalls       []monitor.ToolCall // nil while loading
+	toolsError      string
+	toolsTitle      string
+	toolsSourceMode ViewMode
+	toolsSort       int // Index into monitor.ToolSortKeys
+	toolsModelIdx   int // Index into toolModels(); 0 = all models
+	toolsRangeIdx   int // Index into toolRanges
+	selectedToolIdx int
+
+	// Resource history view
+	usageTable       table.Model
+	usageSamples     []monitor.Sample // nil while loading
+	usagePath        string           // Sample file read
+	usageError       string
+	usageSourceMode  ViewMode
+	usageRangeIdx    int // Index into usageRanges
+	selectedUsageIdx int
+
+	// Session comparison view
+	markedSessions  []SessionInfo // Marked in session lists, in marking order
+	compareProfiles []monitor.SessionProfile
+	compareError    string
+	compareLines    []string
+	compareScroll   int
+
+	// Session diff view
+	sessionDiffs      []monitor.TurnDiff // nil while loading
+	sessionDiffError  string
+	sessionDiffLines  []string
+	sessionDiffTurns  []int // Lines where the changed turns start
+	sessionDiffScroll int
+
+	// Saved views ([[view]] in the config)
+	columns         config.ColumnsConfig // Visible columns: [columns], or the active view's
+	activeView      string               // Name of the view applied last; "" for none
+	showViews       bool                 // View picker overlay is open
+	selectedViewIdx int
+
+	// Column editor overlay (process, project and session tables)
+	editingColumns    bool
+	columnTable       string // View mode name of the edited table
+	editedColumns     []editedColumn
+	selectedColumnIdx int
+
+	// Filter prompt bar (session list and session detail views)
+	filterInput   textinput.Model
+	filterEditing bool
+	filterError   string
 
 	// Scroll tracking
 	lastMessageIdx int // Track last selected message for stable scrolling
-
-	// Message sorting
-	messageSortNewestFirst bool // true = newest first, false = oldest first
 }
 
 // tickMsg is used for periodic updates
@@ -142,9 +261,22 @@ type tickMsg time.Time
 // processesMsg carries refreshed process data
 type processesMsg struct {
 	processes []types.ClaudeProcess
+	alerts    map[int32][]monitor.Alert      // Health alerts by PID
+	states    map[int32]monitor.SessionState // Session states by PID
+	events    []monitor.SessionEvent         // New events to announce
 	err       error
 }
 
+// notifyErrMsg reports a notification that could not be delivered
+type notifyErrMsg struct {
+	err error
+}
+
+// clearNoticesMsg removes the terminal notices of generation gen from the frame
+type clearNoticesMsg struct {
+	gen int
+}
+
 // sessionsMsg carries loaded session data
 type sessionsMsg struct {
 	sessions []SessionInfo
@@ -202,34 +334,85 @@ func (m *Model) scrollToSelection() {
 }
 
 // NewModel creates a new UI model
-func NewModel(updateInterval time.Duration, showHelpers bool) Model {
+func NewModel(cfg *config.Config, showHelpers bool) Model {
 	m := Model{
-		updateInterval:         updateInterval,
-		showHelpers:            showHelpers,
-		sortColumn:             "pid",
-		sortAscending:          true,
-		viewMode:               ViewProcesses,
-		selectedProcIdx:        0,
-		messageFilter:          FilterAll,
-		messageSortNewestFirst: true, // Default: show newest messages first
-		termWidth:              80,   // Default terminal width
-		termHeight:             24,   // Default terminal height
+		showHelpers:     showHelpers,
+		processSort:     defaultSorts["processes"],
+		projectSort:     defaultSorts["projects"],
+		sessionSort:     defaultSorts["sessions"],
+		messageSort:     defaultSorts["messages"], // Newest messages first
+		viewMode:        ViewProcesses,
+		selectedProcIdx: 0,
+		termWidth:       80, // Default terminal width
+		termHeight:      24, // Default terminal height
+		watchdog:        monitor.NewWatchdog(cfg.HealthRules()),
+		events:          monitor.NewEventWatcher(cfg.EventRules()),
 	}
 
-	m.table = createTableWithWidth(m.termWidth)
-	m.projectsTable = createProjectsTableWithWidth(m.termWidth)
-	m.sessionTable = createSessionTableWithWidth(m.termWidth)
-	m.messageTable = createMessageTableWithWidth(m.termWidth)
+	if cfg.UI.DefaultView == "projects" {
+		m.viewMode = ViewProjects
+	}
 
 	// Initialize viewport for message cards
 	m.messageViewport = viewport.New(m.termWidth, m.termHeight-8)
 	m.messageViewport.YPosition = 0
 
+	m.applyConfig(cfg)
+
 	return m
 }
 
+// rebuildTables recreates all tables for the current terminal size and column settings
+func (m *Model) rebuildTables() {
+	// Process table: header (1) + blank (1) + blank (1) + footer (1) = 4 lines
+	m.table = m.styleTable(createTableWithWidth(m.termWidth, m.columns.Processes, m.processSort)).WithPageSize(m.termHeight - 6)
+	// Projects table: header (2 lines) + blank (2 lines) + blank (1) + footer (1) = 6+ lines
+	// Use aggressive reduction to prevent clipping
+	m.projectsTable = m.styleTable(createProjectsTableWithWidth(m.termWidth, m.columns.Projects, m.projectSort)).WithPageSize(m.termHeight - 10)
+	// Session table: header info (~2) + blank (1) + blank (1) + footer (1) = ~5 lines
+	m.sessionTable = m.styleTable(createSessionTableWithWidth(m.termWidth, m.columns.Sessions, m.sessionSort)).WithPageSize(m.termHeight - 8)
+	// Message table: header (1) + time (1) + tool info (1) + blank (1) + blank (1) + scroll (1) + footer (1) = 7
+	m.messageTable = m.styleTable(createMessageTableWithWidth(m.termWidth)).WithPageSize(m.termHeight - 9)
+	// Files table: title (1) + summary (1) + blank (1) + blank (1) + footer (1) = 5 lines
+	m.filesTable = m.styleTable(createFilesTableWithWidth(m.termWidth)).WithPageSize(m.termHeight - 8)
+	// File history tables: same layout as the files table
+	m.historyFilesTable = m.styleTable(createHistoryFilesTableWithWidth(m.termWidth)).WithPageSize(m.termHeight - 8)
+	m.checkpointsTable = m.styleTable(createCheckpointsTableWithWidth(m.termWidth)).WithPageSize(m.termHeight - 8)
+	m.toolsTable = m.styleTable(createToolsTableWithWidth(m.termWidth)).WithPageSize(m.termHeight - 8)
+	m.usageTable = m.styleTable(createUsageTableWithWidth(m.termWidth)).WithPageSize(m.termHeight - 8)
+
+	// Refill tables with current data
+	m.updateTable()
+	m.updateProjectsTable()
+	m.updateSessionTable()
+	m.updateMessageTable()
+	m.updateFilesTable()
+	m.updateHistoryFilesTable()
+	m.updateCheckpointsTable()
+	m.updateToolsTable()
+	m.updateUsageTable()
+}
+
+// styleTable applies the theme to a table
+func (m *Model) styleTable(t table.Model) table.Model {
+	return t.WithBaseStyle(m.styles.Text).HighlightStyle(m.styles.SelectedRow)
+}
+
 // Init initializes the model and sets up background tasks
 func (m Model) Init() tea.Cmd {
+	if m.viewMode == ViewProjects {
+		return tea.Batch(
+			m.loadProjects(),
+			m.tick(),
+		)
+	}
+	if m.viewMode == ViewSessions {
+		// Started in a saved sessions or messages view
+		return tea.Batch(
+			m.loadAllSessions(),
+			m.tick(),
+		)
+	}
 	return tea.Batch(
 		m.refreshProcesses(),
 		m.tick(),
@@ -240,13 +423,73 @@ func (m Model) Init() tea.Cmd {
 func (m Model) refreshProcesses() tea.Cmd {
 	return func() tea.Msg {
 		processes, err := monitor.FindClaudeProcesses(m.showHelpers)
+		now := time.Now()
+		alerts := m.watchdog.Check(processes, now)
+
+		states := make(map[int32]monitor.SessionState)
+		for _, proc := range processes {
+			if live, err := monitor.ReadLiveSession(proc.SessionFile); err == nil {
+				states[proc.PID] = live.State(now, m.config.EventRules(), m.config.Health.IdleAfter, proc.TreeCPU)
+			}
+		}
+
+		var events []monitor.SessionEvent
+		for _, event := range m.events.Check(processes, alerts, now) {
+			if slices.Contains(m.config.Notify.Events, event.Kind) {
+				events = append(events, event)
+			}
+		}
 		return processesMsg{
 			processes: processes,
+			alerts:    alerts,
+			states:    states,
+			events:    events,
 			err:       err,
 		}
 	}
 }
 
+// noticeHold is how long terminal notices stay in the frame, long enough for
+// the renderer to draw them once
+const noticeHold = 200 * time.Millisecond
+
+// announce delivers session events through the configured notifiers. Bell and
+// OSC sequences must not race bubbletea's renderer for the terminal, so they
+// are queued to be drawn with the frame; the other backends run in the
+// background.
+func (m *Model) announce(events []monitor.SessionEvent) tea.Cmd {
+	if len(events) == 0 {
+		return nil
+	}
+	var cmds []tea.Cmd
+	if m.terminalNotify {
+		var sequences strings.Builder
+		terminal := terminalNotifier(m.config.Notify, &sequences)
+		for _, event := range events {
+			terminal.Notify(notify.Notification{Kind: event.Kind, Title: event.Title(), Body: event.Text})
+		}
+		m.notices += sequences.String()
+		m.noticesGen++
+		gen := m.noticesGen
+		cmds = append(cmds, tea.Tick(noticeHold, func(time.Time) tea.Msg { return clearNoticesMsg{gen: gen} }))
+	}
+
+	notifier := m.notifier
+	if notifier == nil {
+		return tea.Batch(cmds...)
+	}
+	return tea.Batch(append(cmds, func() tea.Msg {
+		var errs []error
+		for _, event := range events {
+			errs = append(errs, notifier.Notify(notify.Notification{Kind: event.Kind, Title: event.Title(), Body: event.Text}))
+		}
+		if err := errors.Join(errs...); err != nil {
+			return notifyErrMsg{err: err}
+		}
+		return nil
+	})...)
+}
+
 // tick sends a periodic timer message
 func (m Model) tick() tea.Cmd {
 	return tea.Tick(m.updateInterval, func(_ time.Time) tea.Msg {
@@ -305,7 +548,9 @@ func (m Model) loadSessions() tea.Cmd {
 			// Extract last message info
 			var lastMessage string
 			var lastMessageTime int64
+			var summary filter.Session
 			if stats, err := monitor.ParseSessionFile(s.FilePath); err == nil && len(stats.MessageHistory) > 0 {
+				summary = filter.SessionFromStats(stats)
 				lastMsg := stats.MessageHistory[len(stats.MessageHistory)-1]
 				lastMessageTime = lastMsg.Timestamp.Unix()
 				content := lastMsg.Content
@@ -334,6 +579,9 @@ func (m Model) loadSessions() tea.Cmd {
 				OutputTokens:    outputTokens,
 				LastMessage:     lastMessage,
 				LastMessageTime: lastMessageTime,
+				Commits:         -1,
+				Root:            s.Root,
+				Summary:         summary,
 			}
 		}
 
@@ -364,112 +612,139 @@ func (m Model) loadSessionDetail() tea.Cmd {
 // loadSessionsFromProject loads sessions for a specific project directory
 func (m Model) loadSessionsFromProject(project ProjectDir) tea.Cmd {
 	return func() tea.Msg {
-		entries, err := os.ReadDir(project.Path)
-		if err != nil {
-			return sessionsMsg{
-				err: fmt.Errorf("cannot read project directory: %w", err),
-			}
+		sessions, err := readProjectSessions(project)
+		return sessionsMsg{
+			sessions: sessions,
+			err:      err,
 		}
+	}
+}
 
+// loadAllSessions loads the sessions of every project, for saved session views
+func (m Model) loadAllSessions() tea.Cmd {
+	return func() tea.Msg {
+		projects, err := m.getProjectDirs()
+		if err != nil {
+			return sessionsMsg{err: err}
+		}
 		var sessions []SessionInfo
+		for _, project := range projects {
+			// Unreadable projects are skipped rather than failing the list
+			projectSessions, _ := readProjectSessions(project)
+			sessions = append(sessions, projectSessions...)
+		}
+		return sessionsMsg{sessions: sessions}
+	}
+}
 
-		for _, entry := range entries {
-			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jsonl") {
-				continue
-			}
+// readProjectSessions reads the sessions of a project directory, newest first
+func readProjectSessions(project ProjectDir) ([]SessionInfo, error) {
+	entries, err := os.ReadDir(project.Path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read project directory: %w", err)
+	}
 
-			// Get file info for modification time
-			info, err := entry.Info()
-			if err != nil {
-				continue
-			}
+	var sessions []SessionInfo
 
-			sessionPath := filepath.Join(project.Path, entry.Name())
-			// Use filename without extension as ID
-			sessionID := strings.TrimSuffix(entry.Name(), ".jsonl")
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jsonl") {
+			continue
+		}
 
-			// Extract metadata from session file
-			metadata, err := monitor.GetSessionMetadata(sessionPath)
-			var startedStr, durationStr string
-			var userPrompts, interruptions int
-			var gitBranch string
-			var isSidechain bool
-			var version string
-			var firstPrompt string
-			var totalTokens, inputTokens, outputTokens int
+		// Get file info for modification time
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
 
-			if err == nil {
-				startedStr = metadata.Started.Format("2006-01-02 15:04")
-				// Format duration nicely
-				hours := int(metadata.Duration.Hours())
-				minutes := int(metadata.Duration.Minutes()) % 60
-				if hours > 0 {
-					durationStr = fmt.Sprintf("%dh%dm", hours, minutes)
-				} else {
-					durationStr = fmt.Sprintf("%dm", minutes)
-				}
-				userPrompts = metadata.UserPrompts
-				interruptions = metadata.Interruptions
-				gitBranch = metadata.GitBranch
-				isSidechain = metadata.IsSidechain
-				version = metadata.Version
-				firstPrompt = metadata.FirstPrompt
-				totalTokens = metadata.TotalInputTokens + metadata.TotalOutputTokens
-				inputTokens = metadata.TotalInputTokens
-				outputTokens = metadata.TotalOutputTokens
-			}
+		sessionPath := filepath.Join(project.Path, entry.Name())
+		// Use filename without extension as ID
+		sessionID := strings.TrimSuffix(entry.Name(), ".jsonl")
 
-			// Extract last message info
-			var lastMessage string
-			var lastMessageTime int64
-			if stats, err := monitor.ParseSessionFile(sessionPath); err == nil && len(stats.MessageHistory) > 0 {
-				lastMsg := stats.MessageHistory[len(stats.MessageHistory)-1]
-				lastMessageTime = lastMsg.Timestamp.Unix()
-				content := lastMsg.Content
-				if len(content) > 100 {
-					content = content[:97] + "…"
-				}
-				content = strings.Join(strings.Fields(content), " ")
-				lastMessage = content
-			}
+		// Extract metadata from session file
+		metadata, err := monitor.GetSessionMetadata(sessionPath)
+		var startedStr, durationStr string
+		var userPrompts, interruptions int
+		var gitBranch string
+		var isSidechain bool
+		var version string
+		var firstPrompt string
+		var totalTokens, inputTokens, outputTokens int
 
-			sessions = append(sessions, SessionInfo{
-				ID:              sessionID,
-				Title:           sessionID, // Use ID as title for project sessions
-				Updated:         info.ModTime().Format("2006-01-02 15:04"),
-				Path:            sessionPath,
-				Started:         startedStr,
-				Duration:        durationStr,
-				UserPrompts:     userPrompts,
-				Interruptions:   interruptions,
-				GitBranch:       gitBranch,
-				IsSidechain:     isSidechain,
-				Version:         version,
-				FirstPrompt:     firstPrompt,
-				TotalTokens:     totalTokens,
-				InputTokens:     inputTokens,
-				OutputTokens:    outputTokens,
-				LastMessage:     lastMessage,
-				LastMessageTime: lastMessageTime,
-			})
+		if err == nil {
+			startedStr = metadata.Started.Format("2006-01-02 15:04")
+			// Format duration nicely
+			hours := int(metadata.Duration.Hours())
+			minutes := int(metadata.Duration.Minutes()) % 60
+			if hours > 0 {
+				durationStr = fmt.Sprintf("%dh%dm", hours, minutes)
+			} else {
+				durationStr = fmt.Sprintf("%dm", minutes)
+			}
+			userPrompts = metadata.UserPrompts
+			interruptions = metadata.Interruptions
+			gitBranch = metadata.GitBranch
+			isSidechain = metadata.IsSidechain
+			version = metadata.Version
+			firstPrompt = metadata.FirstPrompt
+			totalTokens = metadata.TotalInputTokens + metadata.TotalOutputTokens
+			inputTokens = metadata.TotalInputTokens
+			outputTokens = metadata.TotalOutputTokens
 		}
 
-		// Sort sessions by modification time (newest first)
-		for i := 0; i < len(sessions); i++ {
-			for j := i + 1; j < len(sessions); j++ {
-				// Parse times for sorting
-				t1, _ := time.Parse("2006-01-02 15:04", sessions[i].Updated)
-				t2, _ := time.Parse("2006-01-02 15:04", sessions[j].Updated)
-				if t2.After(t1) {
-					sessions[i], sessions[j] = sessions[j], sessions[i]
-				}
+		// Extract last message info
+		var lastMessage string
+		var lastMessageTime int64
+		var summary filter.Session
+		if stats, err := monitor.ParseSessionFile(sessionPath); err == nil && len(stats.MessageHistory) > 0 {
+			summary = filter.SessionFromStats(stats)
+			lastMsg := stats.MessageHistory[len(stats.MessageHistory)-1]
+			lastMessageTime = lastMsg.Timestamp.Unix()
+			content := lastMsg.Content
+			if len(content) > 100 {
+				content = content[:97] + "…"
 			}
+			content = strings.Join(strings.Fields(content), " ")
+			lastMessage = content
 		}
 
-		return sessionsMsg{
-			sessions: sessions,
+		sessions = append(sessions, SessionInfo{
+			ID:              sessionID,
+			Title:           sessionID, // Use ID as title for project sessions
+			Updated:         info.ModTime().Format("2006-01-02 15:04"),
+			Path:            sessionPath,
+			Started:         startedStr,
+			Duration:        durationStr,
+			UserPrompts:     userPrompts,
+			Interruptions:   interruptions,
+			GitBranch:       gitBranch,
+			IsSidechain:     isSidechain,
+			Version:         version,
+			FirstPrompt:     firstPrompt,
+			TotalTokens:     totalTokens,
+			InputTokens:     inputTokens,
+			OutputTokens:    outputTokens,
+			LastMessage:     lastMessage,
+			LastMessageTime: lastMessageTime,
+			Commits:         -1,
+			Root:            project.Root,
+			Summary:         summary,
+		})
+	}
+
+	// Sort sessions by modification time (newest first)
+	for i := 0; i < len(sessions); i++ {
+		for j := i + 1; j < len(sessions); j++ {
+			// Parse times for sorting
+			t1, _ := time.Parse("2006-01-02 15:04", sessions[i].Updated)
+			t2, _ := time.Parse("2006-01-02 15:04", sessions[j].Updated)
+			if t2.After(t1) {
+				sessions[i], sessions[j] = sessions[j], sessions[i]
+			}
 		}
 	}
+
+	return sessions, nil
 }
 
 // loadProjects kicks off an asynchronous project directory loading
@@ -483,17 +758,45 @@ func (m Model) loadProjects() tea.Cmd {
 	}
 }
 
-// getProjectDirs returns all project directories sorted by modification time (newest first)
+// getProjectDirs returns project directories from all data roots sorted by modification time (newest first)
 func (m Model) getProjectDirs() ([]ProjectDir, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("cannot get home directory: %w", err)
 	}
 
-	projectsPath := filepath.Join(home, ".claude", "projects")
+	var projects []ProjectDir
+	var readErrors []string
+
+	for _, root := range monitor.DataRoots() {
+		rootProjects, err := readProjectDirs(root, home)
+		if err != nil {
+			readErrors = append(readErrors, err.Error())
+			continue
+		}
+		projects = append(projects, rootProjects...)
+	}
+
+	// Only fail when no root could be read at all
+	if len(projects) == 0 && len(readErrors) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(readErrors, "; "))
+	}
+
+	// Sort by modification time (newest first)
+	sort.SliceStable(projects, func(i, j int) bool {
+		return projects[i].Modified.After(projects[j].Modified)
+	})
+
+	return projects, nil
+}
+
+// readProjectDirs lists the project directories of a single data root
+func readProjectDirs(root monitor.DataRoot, home string) ([]ProjectDir, error) {
+	projectsPath := root.ProjectsDir()
+	resolver := root.Resolver()
 	entries, err := os.ReadDir(projectsPath)
 	if err != nil {
-		return nil, fmt.Errorf("cannot read projects directory: %w", err)
+		return nil, fmt.Errorf("cannot read projects directory %s: %w", projectsPath, err)
 	}
 
 	var projects []ProjectDir
@@ -520,111 +823,29 @@ func (m Model) getProjectDirs() ([]ProjectDir, error) {
 			}
 		}
 
-		// Try to get the original path from sessions-index.json
-		// If not found, decode the directory name (which uses dashes for slashes)
-		displayName := decodeProjectName(entry.Name(), home)
-
-		indexPath := filepath.Join(dirPath, "sessions-index.json")
-		if indexData, err := os.ReadFile(indexPath); err == nil {
-			// Extract originalPath from JSON
-			if origPath := extractOriginalPath(string(indexData)); origPath != "" {
-				displayName = formatProjectPath(origPath, home)
-			}
-		}
+		// Recover the original path from the index, transcripts or filesystem
+		originalPath, _ := resolver.ProjectPath(entry.Name())
+		displayName := formatProjectPath(originalPath, home)
 
 		projects = append(projects, ProjectDir{
-			Name:        entry.Name(),
-			Path:        dirPath,
-			DisplayName: displayName,
-			Modified:    info.ModTime(),
-			Sessions:    sessionCount,
+			Name:         entry.Name(),
+			Path:         dirPath,
+			DisplayName:  displayName,
+			OriginalPath: originalPath,
+			Root:         root.Label,
+			Modified:     info.ModTime(),
+			Sessions:     sessionCount,
 		})
 	}
 
-	// Sort by modification time (newest first)
-	for i := 0; i < len(projects); i++ {
-		for j := i + 1; j < len(projects); j++ {
-			if projects[j].Modified.After(projects[i].Modified) {
-				projects[i], projects[j] = projects[j], projects[i]
-			}
-		}
-	}
-
 	return projects, nil
 }
 
-// extractOriginalPath extracts the originalPath value from a JSON string
-func extractOriginalPath(jsonStr string) string {
-	// Look for "originalPath": "..."
-	// Simple string search approach
-	idx := strings.Index(jsonStr, `"originalPath"`)
-	if idx < 0 {
-		return ""
-	}
-
-	// Find the opening quote after the colon
-	colonIdx := strings.Index(jsonStr[idx:], ":")
-	if colonIdx < 0 {
-		return ""
-	}
-
-	quoteIdx := strings.Index(jsonStr[idx+colonIdx:], `"`)
-	if quoteIdx < 0 {
-		return ""
-	}
-
-	// Find the closing quote
-	startIdx := idx + colonIdx + quoteIdx + 1
-	endIdx := strings.Index(jsonStr[startIdx:], `"`)
-	if endIdx < 0 {
-		return ""
-	}
-
-	return jsonStr[startIdx : startIdx+endIdx]
-}
-
 // formatProjectPath converts an absolute path to a user-friendly display format
 func formatProjectPath(path string, home string) string {
-	// Replace /Users/username with ~/
-	path = strings.ReplaceAll(path, home, "~")
-	return path
-}
-
-// decodeProjectName converts an encoded project directory name to a readable path
-// The encoding uses dashes for path separators
-func decodeProjectName(encodedName string, home string) string {
-	// If it doesn't contain dashes and slashes, it's likely already decoded or invalid
-	if !strings.Contains(encodedName, "-") {
-		return encodedName
-	}
-
-	// The encoded format is typically something like: -Users-thies-Projects-SaaS-Bonn-cloud
-	// We need to figure out the actual path. The pattern is that User's home directory is encoded as -Users-username-
-	// So we replace the leading -Users-username- with ~
-
-	// Extract username from home path (e.g., /Users/thies -> thies)
-	homeParts := strings.Split(home, string(filepath.Separator))
-	var username string
-	if len(homeParts) > 0 {
-		username = homeParts[len(homeParts)-1]
+	// Replace the home directory prefix with ~
+	if path == home || strings.HasPrefix(path, home+string(filepath.Separator)) {
+		return "~" + strings.TrimPrefix(path, home)
 	}
-
-	// Check if encoded name starts with the encoded home directory
-	encodedHome := "-Users-" + username + "-"
-	if strings.HasPrefix(encodedName, encodedHome) {
-		// Replace the encoded home with ~/
-		decoded := strings.TrimPrefix(encodedName, encodedHome)
-		decoded = "~/" + decoded
-		// Replace remaining dashes with slashes for the rest of the path
-		decoded = strings.ReplaceAll(decoded, "-", "/")
-		return decoded
-	}
-
-	// Fallback: just replace all dashes with slashes
-	decoded := strings.ReplaceAll(encodedName, "-", "/")
-	// If it doesn't start with /, add ~/
-	if !strings.HasPrefix(decoded, "/") && !strings.HasPrefix(decoded, "~") {
-		decoded = "~/" + decoded
-	}
-	return decoded
+	return path
 }
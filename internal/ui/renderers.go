@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// ToolRenderer lays out the calls of a tool as display lines no wider than
+// width. A nil function leaves that part to the generic layout.
+type ToolRenderer struct {
+	Input  func(m *Model, input map[string]interface{}, width int) []string
+	Result func(m *Model, input map[string]interface{}, result string, width int) []string
+}
+
+// RendererRegistry finds the renderer of a tool by name or glob pattern
+type RendererRegistry struct {
+	renderers map[string]ToolRenderer
+}
+
+// NewRendererRegistry returns a registry with the built-in renderers
+func NewRendererRegistry() *RendererRegistry {
+	r := &RendererRegistry{renderers: make(map[string]ToolRenderer)}
+	for pattern, renderer := range builtinRenderers {
+		r.Register(pattern, renderer)
+	}
+	return r
+}
+
+// builtinRenderers holds the renderers that ship with promptwatch
+var builtinRenderers = map[string]ToolRenderer{
+	"Edit":      {Input: (*Model).renderEditInput},
+	"MultiEdit": {Input: (*Model).renderMultiEditInput},
+	"Write":     {Input: (*Model).renderWriteInput},
+	"Bash":      {Input: (*Model).renderBashInput, Result: (*Model).renderBashResult},
+	"Read":      {Input: (*Model).renderReadInput, Result: (*Model).renderReadResult},
+	"Grep":      {Input: (*Model).renderGrepInput, Result: (*Model).renderGrepResult},
+	"Glob":      {Input: (*Model).renderGlobInput, Result: (*Model).renderGlobResult},
+	"WebFetch":  {Input: (*Model).renderWebFetchInput, Result: (*Model).renderWebFetchResult},
+	"TodoWrite": {Input: (*Model).renderTodoWriteInput},
+}
+
+// Register adds a renderer for a tool name or glob such as "mcp__github__*".
+// Functions it sets replace those already registered for the same pattern.
+func (r *RendererRegistry) Register(pattern string, renderer ToolRenderer) {
+	existing := r.renderers[pattern]
+	if renderer.Input != nil {
+		existing.Input = renderer.Input
+	}
+	if renderer.Result != nil {
+		existing.Result = renderer.Result
+	}
+	r.renderers[pattern] = existing
+}
+
+// Lookup returns the renderer of a tool. An exact name wins over globs;
+// among matching globs the longest, most specific pattern wins.
+func (r *RendererRegistry) Lookup(toolName string) (ToolRenderer, bool) {
+	if renderer, ok := r.renderers[toolName]; ok {
+		return renderer, true
+	}
+
+	var matches []string
+	for pattern := range r.renderers {
+		if ok, _ := path.Match(pattern, toolName); ok {
+			matches = append(matches, pattern)
+		}
+	}
+	if len(matches) == 0 {
+		return ToolRenderer{}, false
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if len(matches[i]) != len(matches[j]) {
+			return len(matches[i]) > len(matches[j])
+		}
+		return matches[i] < matches[j]
+	})
+	return r.renderers[matches[0]], true
+}
+
+// newRendererRegistry builds the registry for a config: the built-ins
+// followed by the [renderers] templates, which replace them per pattern
+func newRendererRegistry(cfg *config.Config) *RendererRegistry {
+	registry := NewRendererRegistry()
+	// Templates were compiled during validation, so errors cannot occur here
+	templates, _ := cfg.RendererTemplates()
+	for _, t := range templates {
+		registry.Register(t.Pattern, templateRenderer(t))
+	}
+	return registry
+}
+
+// templateRenderer wraps the compiled templates of a [renderers] entry
+func templateRenderer(t config.RendererTemplate) ToolRenderer {
+	var renderer ToolRenderer
+	if t.Input != nil {
+		renderer.Input = func(m *Model, input map[string]interface{}, width int) []string {
+			return m.renderTemplate(t.Input, input, width)
+		}
+	}
+	if t.Result != nil {
+		renderer.Result = func(m *Model, _ map[string]interface{}, result string, width int) []string {
+			var data interface{} = result
+			var decoded interface{}
+			if json.Unmarshal([]byte(result), &decoded) == nil {
+				data = decoded
+			}
+			return m.renderTemplate(t.Result, data, width)
+		}
+	}
+	return renderer
+}
+
+// renderTemplate executes a renderer template, reporting failures inline
+func (m *Model) renderTemplate(t *template.Template, data interface{}, width int) []string {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return []string{m.styles.Error.Render("Template error: " + err.Error())}
+	}
+
+	var lines []string
+	for _, line := range wrapLines(buf.String(), width) {
+		lines = append(lines, m.styles.Text.Render(line))
+	}
+	return lines
+}
+
+// renderToolInput lays out the JSON input of a tool call
+func (m *Model) renderToolInput(toolName, toolInput string, width int) []string {
+	var input map[string]interface{}
+	if renderer, ok := m.renderers.Lookup(toolName); ok && renderer.Input != nil &&
+		json.Unmarshal([]byte(toolInput), &input) == nil {
+		return renderer.Input(m, input, width)
+	}
+
+	lines := []string{m.styles.Highlight.Render("Arguments:")}
+	return append(lines, m.renderJSON(toolInput, width)...)
+}
+
+// renderToolResult lays out a tool_result message using the renderer of the
+// tool that produced it, found through the call with the same tool use ID
+func (m *Model) renderToolResult(msg *monitor.Message, width int) []string {
+	call := m.findToolCall(msg.ToolUseID)
+	if call == nil {
+		return m.renderPlainResult(msg.Content, width)
+	}
+
+	lines := []string{m.styles.Tool.Render("📥 "+strings.ToUpper(call.ToolName)) + " " +
+		m.styles.Muted.Render(ansi.Truncate(toolSummary(call.ToolName, call.ToolInput), width, "…")), ""}
+
+	var input map[string]interface{}
+	_ = json.Unmarshal([]byte(call.ToolInput), &input)
+	if renderer, ok := m.renderers.Lookup(call.ToolName); ok && renderer.Result != nil {
+		return append(lines, renderer.Result(m, input, msg.Content, width)...)
+	}
+	return append(lines, m.renderPlainResult(msg.Content, width)...)
+}
+
+// findToolCall returns the tool call of the open session with the given tool use ID
+func (m *Model) findToolCall(toolUseID string) *monitor.Message {
+	stats, ok := m.sessionStats.(*monitor.SessionStats)
+	if !ok || toolUseID == "" {
+		return nil
+	}
+	for i := range stats.MessageHistory {
+		msg := &stats.MessageHistory[i]
+		if msg.ToolName != "" && msg.ToolUseID == toolUseID {
+			return msg
+		}
+	}
+	return nil
+}
+
+// renderPlainResult shows a result without a tool renderer: JSON is
+// indented and highlighted, anything else wrapped as text
+func (m *Model) renderPlainResult(result string, width int) []string {
+	if json.Valid([]byte(strings.TrimSpace(result))) && strings.ContainsAny(result, "{[") {
+		return m.renderJSON(result, width)
+	}
+
+	var lines []string
+	for _, line := range wrapLines(result, width) {
+		lines = append(lines, m.styles.Text.Render(line))
+	}
+	return lines
+}
+
+// renderJSON shows a JSON document indented and highlighted
+func (m *Model) renderJSON(text string, width int) []string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(text), "", "  "); err != nil {
+		return wrapWords(strings.Join(strings.Fields(text), " "), width)
+	}
+
+	var lines []string
+	for _, line := range highlightLanguage(buf.String(), "json", m.theme.Syntax) {
+		lines = append(lines, strings.Split(ansi.Wrap(line, width, ""), "\n")...)
+	}
+	return lines
+}
+
+// wrapLines wraps each line of text to width, keeping indentation
+func wrapLines(text string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(expandTabs(text), "\n"), "\n") {
+		lines = append(lines, strings.Split(ansi.Wrap(line, width, ""), "\n")...)
+	}
+	return lines
+}
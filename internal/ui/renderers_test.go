@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// TestRendererLookup tests exact names, glob precedence and merging
+func TestRendererLookup(t *testing.T) {
+	named := func(name string) ToolRenderer {
+		return ToolRenderer{Input: func(*Model, map[string]interface{}, int) []string { return []string{name} }}
+	}
+
+	registry := NewRendererRegistry()
+	registry.Register("mcp__*", named("any mcp"))
+	registry.Register("mcp__github__*", named("github"))
+	registry.Register("mcp__github__create_issue", named("create issue"))
+	registry.Register("Read", ToolRenderer{Result: func(*Model, map[string]interface{}, string, int) []string { return nil }})
+
+	tests := []struct {
+		tool string
+		want string
+	}{
+		{"mcp__github__create_issue", "create issue"},
+		{"mcp__github__list_prs", "github"},
+		{"mcp__slack__post", "any mcp"},
+		{"Unknown", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			renderer, ok := registry.Lookup(tt.tool)
+			if tt.want == "" {
+				if ok {
+					t.Errorf("Expected no renderer for %s", tt.tool)
+				}
+				return
+			}
+			if !ok || renderer.Input == nil {
+				t.Fatalf("No input renderer for %s", tt.tool)
+			}
+			if got := renderer.Input(nil, nil, 80)[0]; got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Registering only a result keeps the built-in input renderer
+	if read, _ := registry.Lookup("Read"); read.Input == nil {
+		t.Errorf("Read input renderer lost when registering a result renderer")
+	}
+}
+
+// TestTemplateRenderers tests [renderers] templates for inputs and results
+func TestTemplateRenderers(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	cfg := config.Default()
+	cfg.Renderers = map[string]config.RendererConfig{
+		"mcp__github__*": {
+			Input:  `{{.owner}}/{{.repo}} #{{.number}}`,
+			Result: `{{.title}} ({{.state}}) by {{.user.login}}`,
+		},
+		"mcp__notes__read": {Result: `{{truncate 8 .}}`},
+		"Bash":             {Result: `exit: {{.}}`},
+	}
+	m := NewModel(cfg, false)
+	m.sessionStats = &monitor.SessionStats{MessageHistory: []monitor.Message{
+		{ToolName: "mcp__github__get_issue", ToolUseID: "t1", ToolInput: `{"owner":"acme","repo":"api","number":7}`},
+		{ToolName: "mcp__notes__read", ToolUseID: "t2", ToolInput: `{}`},
+		{ToolName: "Bash", ToolUseID: "t3", ToolInput: `{"command":"false"}`},
+	}}
+
+	tests := []struct {
+		name string
+		got  []string
+		want string
+	}{
+		{"input", m.renderToolInput("mcp__github__get_issue", `{"owner":"acme","repo":"api","number":7}`, 80), "acme/api #7"},
+		{"JSON result", m.renderToolResult(&monitor.Message{ToolUseID: "t1",
+			Content: `{"title":"Crash","state":"open","user":{"login":"bob"}}`}, 80), "Crash (open) by bob"},
+		{"text result", m.renderToolResult(&monitor.Message{ToolUseID: "t2", Content: "a long note"}, 80), "a long …"},
+		{"template replaces built-in result", m.renderToolResult(&monitor.Message{ToolUseID: "t3", Content: "1"}, 80), "exit: 1"},
+		{"built-in input kept", m.renderToolInput("Bash", `{"command":"false"}`, 80), "$ false"},
+		{"unlinked result", m.renderToolResult(&monitor.Message{ToolUseID: "t9", Content: "plain"}, 80), "plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if out := ansi.Strip(strings.Join(tt.got, "\n")); !strings.Contains(out, tt.want) {
+				t.Errorf("Output does not contain %q:\n%s", tt.want, out)
+			}
+		})
+	}
+}
+
+// TestBuiltinResultRenderers tests the result layouts of built-in tools
+func TestBuiltinResultRenderers(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	m := NewModel(config.Default(), false)
+
+	tests := []struct {
+		name   string
+		tool   string
+		input  string
+		result string
+		want   []string
+	}{
+		{"read keeps line numbers", "Read", `{"file_path":"/a.go"}`,
+			"     9→package a\n    10→\tfunc A() {}\n", []string{" 9 │ package a", "10 │     func A() {}"}},
+		{"grep matches", "Grep", `{"pattern":"TODO"}`,
+			"src/a.go:12:// TODO fix", []string{"src/a.go:12:// TODO fix"}},
+		{"glob count", "Glob", `{"pattern":"**/*.go"}`,
+			"/src/a.go\n/src/b.go", []string{"2 files", "/src/a.go"}},
+		{"glob relative paths with spaces", "Glob", `{"pattern":"**/*.md"}`,
+			"docs/release notes.md\nREADME.md\n(Results are truncated. Consider using a more specific path or pattern.)",
+			[]string{"2 files", "docs/release notes.md", "(Results are truncated."}},
+		{"glob without matches", "Glob", `{"pattern":"**/*.rs"}`,
+			"No files found", []string{"No files found"}},
+		{"web fetch as markdown", "WebFetch", `{"url":"https://x.dev"}`,
+			"# Summary\n\nAll good", []string{"Summary", "All good"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.sessionStats = &monitor.SessionStats{MessageHistory: []monitor.Message{
+				{ToolName: tt.tool, ToolUseID: "t1", ToolInput: tt.input},
+			}}
+			out := ansi.Strip(strings.Join(m.renderToolResult(&monitor.Message{ToolUseID: "t1", Content: tt.result}, 80), "\n"))
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("Output does not contain %q:\n%s", want, out)
+				}
+			}
+			if tt.tool == "Glob" && strings.Contains(out, "1 files") {
+				t.Errorf("Note counted as a file:\n%s", out)
+			}
+		})
+	}
+}
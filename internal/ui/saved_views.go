@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/filter"
+)
+
+// StartInView opens the TUI in a saved view instead of the default view
+func (m *Model) StartInView(v config.ViewConfig) {
+	m.applyView(v)
+}
+
+// openViewPicker shows the saved views, selecting the active one
+func (m *Model) openViewPicker() {
+	if len(m.config.SavedViews) == 0 {
+		m.statusMessage = "No saved views; add [[view]] tables to the config file"
+		m.statusIsError = true
+		return
+	}
+	m.showViews = true
+	m.selectedViewIdx = max(0, slices.Index(m.config.ViewNames(), m.activeView))
+}
+
+// updateViewPicker handles a key while the view picker is open
+func (m Model) updateViewPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	views := m.config.SavedViews
+	switch {
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Views):
+		m.showViews = false
+	case key.Matches(msg, m.keys.Up):
+		m.selectedViewIdx = max(m.selectedViewIdx-1, 0)
+	case key.Matches(msg, m.keys.Down):
+		m.selectedViewIdx = min(m.selectedViewIdx+1, len(views)-1)
+	case key.Matches(msg, m.keys.Home):
+		m.selectedViewIdx = 0
+	case key.Matches(msg, m.keys.End):
+		m.selectedViewIdx = len(views) - 1
+	case key.Matches(msg, m.keys.Open):
+		m.showViews = false
+		if m.selectedViewIdx < len(views) {
+			return m, m.applyView(views[m.selectedViewIdx])
+		}
+	}
+	return m, nil
+}
+
+// applyView switches to a saved view's mode with its sort column, columns and
+// filter, and returns the command loading what the mode shows
+func (m *Model) applyView(v config.ViewConfig) tea.Cmd {
+	m.activeView = v.Name
+	m.columns = m.config.Columns
+	sorted := m.sortOf(v.Mode)
+	*sorted = defaultSorts[v.Mode]
+	if v.Sort != "" {
+		*sorted = parseSort(v.Sort)
+	}
+	m.setViewColumns(v)
+	m.rebuildTables()
+	m.statusMessage = "View: " + v.Name
+	m.statusIsError = false
+
+	switch v.Mode {
+	case "processes":
+		m.viewMode = ViewProcesses
+		m.selectedProcIdx = 0
+		return m.refreshProcesses()
+	case "projects":
+		m.viewMode = ViewProjects
+		m.selectedProjIdx = 0
+		return m.loadProjects()
+	case "sessions":
+		m.sessionFilter = m.savedFilter("sessions")
+		if m.viewMode == ViewSessions {
+			m.selectedSessionIdx = 0
+			m.updateSessionTable()
+			return nil
+		}
+		return m.openAllSessions()
+	case "messages":
+		if m.viewMode == ViewSessionDetail {
+			m.messageFilter = m.savedFilter("messages")
+			m.selectedMessageIdx = 0
+			m.updateMessageTable()
+			return nil
+		}
+		m.statusMessage = "View: " + v.Name + " – open a session to apply it"
+		if m.viewMode != ViewSessions {
+			return m.openAllSessions()
+		}
+	}
+	return nil
+}
+
+// setViewColumns shows the columns a view lists in its mode's table
+func (m *Model) setViewColumns(v config.ViewConfig) {
+	if len(v.Columns) == 0 {
+		return
+	}
+	switch v.Mode {
+	case "processes":
+		m.columns.Processes = v.Columns
+	case "projects":
+		m.columns.Projects = v.Columns
+	case "sessions":
+		m.columns.Sessions = v.Columns
+	}
+}
+
+// savedFilter returns the filter of the active view when it is a view of
+// mode, and nil otherwise
+func (m Model) savedFilter(mode string) *filter.Expr {
+	v, ok := m.config.View(m.activeView)
+	if !ok || v.Mode != mode {
+		return nil
+	}
+	// Filters were parsed when the config was validated
+	expr, _ := v.Expr()
+	return expr
+}
+
+// openAllSessions switches to the session list of every project
+func (m *Model) openAllSessions() tea.Cmd {
+	if m.viewMode == ViewProjects {
+		m.sessionSourceMode = ViewProjects
+	} else {
+		m.sessionSourceMode = ViewProcesses
+	}
+	m.viewMode = ViewSessions
+	m.sessionsAll = true
+	m.selectedProc = nil
+	m.selectedSession = nil
+	m.sessionStats = nil
+	m.allSessions = nil
+	m.sessions = nil
+	m.sessionError = ""
+	m.selectedSessionIdx = 0
+	return m.loadAllSessions()
+}
+
+// renderViewPicker renders the saved views as an overlay
+func (m Model) renderViewPicker() string {
+	var lines []string
+	for i, v := range m.config.SavedViews {
+		line := fmt.Sprintf("%-28s %-9s %s", truncatePath(v.Name, 28), v.Mode, v.Filter)
+		switch {
+		case i == m.selectedViewIdx:
+			line = m.styles.SelectedRow.Render("▸ " + line)
+		case v.Name == m.activeView:
+			line = m.styles.Highlight.Render("• " + line)
+		default:
+			line = m.styles.Text.Render("  " + line)
+		}
+		lines = append(lines, line)
+	}
+
+	title := m.styles.Title.Render("Saved Views")
+	hint := m.styles.Muted.Render(formatHints([]key.Help{
+		{Key: firstKey(m.keys.Open), Desc: "Apply"},
+		{Key: firstKey(m.keys.Back), Desc: "Close"},
+	}))
+	box := m.styles.Box.Render(lipgloss.JoinVertical(lipgloss.Left, title, "", strings.Join(lines, "\n"), "", hint))
+
+	return lipgloss.Place(m.termWidth, m.termHeight, lipgloss.Center, lipgloss.Center, box)
+}
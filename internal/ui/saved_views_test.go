@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/filter"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// TestSavedViews tests picking a sessions view and a messages view
+func TestSavedViews(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	cfg := config.Default()
+	cfg.SavedViews = []config.ViewConfig{
+		{Name: "costly", Mode: "sessions", Filter: "cost>1", Columns: []string{"version", "lastmessage"}},
+		{Name: "prompts", Mode: "messages", Sort: "time", Filter: "role:user"},
+	}
+	var model tea.Model = NewModel(cfg, false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	m := model.(Model)
+	m.viewMode = ViewSessions
+	model, _ = m.Update(sessionsMsg{sessions: []SessionInfo{
+		{ID: "s1", Path: "s1.jsonl", LastMessageTime: 2, Summary: filter.Session{Cost: 2}},
+		{ID: "s2", Path: "s2.jsonl", LastMessageTime: 1, Summary: filter.Session{Cost: 0.5}},
+	}})
+
+	model, _ = model.Update(keyPress("v"))
+	if view := model.(Model).View(); !strings.Contains(view, "Saved Views") || !strings.Contains(view, "role:user") {
+		t.Fatalf("Picker missing views:\n%s", view)
+	}
+	model, _ = model.Update(keyPress("enter"))
+	m = model.(Model)
+	if m.activeView != "costly" || len(m.sessions) != 1 || m.sessions[0].ID != "s1" {
+		t.Errorf("Sessions view: active %q, sessions %+v", m.activeView, m.sessions)
+	}
+	if got := strings.Join(m.columns.Sessions, ","); got != "version,lastmessage" {
+		t.Errorf("Session columns = %q", got)
+	}
+
+	// A messages view applies to the session opened next
+	model, _ = model.Update(keyPress("v"))
+	model, _ = model.Update(keyPress("down"))
+	model, _ = model.Update(keyPress("enter"))
+	model, _ = model.Update(keyPress("enter"))
+	m = model.(Model)
+	if m.viewMode != ViewSessionDetail || m.messageFilter.String() != "role:user" || m.messageSort.Desc {
+		t.Errorf("Opened session: mode %v, filter %q, sort %v", m.viewMode, m.messageFilter, m.messageSort)
+	}
+	model, _ = model.Update(sessionDetailMsg{stats: &monitor.SessionStats{MessageHistory: []monitor.Message{
+		{Type: "prompt", Role: "user", Content: "first"},
+		{Type: "assistant_response", Role: "assistant", Content: "reply"},
+		{Type: "prompt", Role: "user", Content: "second"},
+	}}})
+	if got := model.(Model).filteredMessageCount; got != 2 {
+		t.Errorf("Messages view shows %d messages, want 2", got)
+	}
+}
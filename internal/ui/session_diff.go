@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/thieso2/promptwatch/internal/diff"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// sessionDiffMsg carries the aligned turns of the two sessions being diffed
+type sessionDiffMsg struct {
+	diffs []monitor.TurnDiff
+	err   error
+}
+
+// sessionDiffContext is the number of unchanged response lines shown around a change
+const sessionDiffContext = 2
+
+// openSessionDiff switches to the turn-by-turn diff of the two marked sessions
+func (m *Model) openSessionDiff() tea.Cmd {
+	if len(m.markedSessions) != 2 {
+		m.statusMessage = fmt.Sprintf("Mark exactly two sessions with %s to diff them", firstKey(m.keys.Mark))
+		m.statusIsError = true
+		return nil
+	}
+	m.viewMode = ViewSessionDiff
+	m.sessionDiffs = nil
+	m.sessionDiffError = ""
+	m.sessionDiffLines = nil
+	m.sessionDiffTurns = nil
+	m.sessionDiffScroll = 0
+
+	pathA, pathB := m.markedSessions[0].Path, m.markedSessions[1].Path
+	return func() tea.Msg {
+		a, err := monitor.ParseSessionFile(pathA)
+		if err != nil {
+			return sessionDiffMsg{err: err}
+		}
+		b, err := monitor.ParseSessionFile(pathB)
+		if err != nil {
+			return sessionDiffMsg{err: err}
+		}
+		return sessionDiffMsg{diffs: monitor.DiffSessions(a, b)}
+	}
+}
+
+// layoutSessionDiff renders each aligned pair of turns: unchanged turns on a
+// single line, changed ones with their prompts, tool calls and response hunks
+func (m *Model) layoutSessionDiff() {
+	m.sessionDiffLines = nil
+	m.sessionDiffTurns = nil
+	if m.sessionDiffs == nil {
+		return
+	}
+	width := max(m.termWidth-4, 20)
+	add := func(line string) { m.sessionDiffLines = append(m.sessionDiffLines, line) }
+	op := func(l diff.Line) string {
+		switch l.Op {
+		case diff.Delete:
+			return m.styles.Error.Render(ansi.Truncate("-"+l.Text, width, "…"))
+		case diff.Insert:
+			return m.styles.OK.Render(ansi.Truncate("+"+l.Text, width, "…"))
+		}
+		return m.styles.Text.Render(ansi.Truncate(" "+l.Text, width, "…"))
+	}
+	prompt := func(t *monitor.Turn) string {
+		if t.Prompt == "" {
+			return "(before the first prompt)"
+		}
+		return strings.Join(strings.Fields(t.Prompt), " ")
+	}
+
+	for _, d := range m.sessionDiffs {
+		var label string
+		switch {
+		case d.A == nil:
+			label = fmt.Sprintf("B%d only", d.IndexB+1)
+		case d.B == nil:
+			label = fmt.Sprintf("A%d only", d.IndexA+1)
+		default:
+			label = fmt.Sprintf("A%d ↔ B%d", d.IndexA+1, d.IndexB+1)
+		}
+
+		if !d.Changed() {
+			add(m.styles.Muted.Render(ansi.Truncate(fmt.Sprintf("  %s  identical  %s", label, prompt(d.A)), width, "…")))
+			continue
+		}
+		m.sessionDiffTurns = append(m.sessionDiffTurns, len(m.sessionDiffLines))
+		if len(m.sessionDiffTurns) > 1 {
+			add("")
+		}
+		switch {
+		case d.SamePrompt:
+			add(m.styles.Title.Render(label) + "  " + m.styles.Text.Render(ansi.Truncate(prompt(d.A), width-len(label)-2, "…")))
+		default:
+			add(m.styles.Title.Render(label) + m.styles.Warning.Render("  prompt changed"))
+			if d.A != nil {
+				add(m.styles.Error.Render(ansi.Truncate("-> "+prompt(d.A), width, "…")))
+			}
+			if d.B != nil {
+				add(m.styles.OK.Render(ansi.Truncate("+> "+prompt(d.B), width, "…")))
+			}
+		}
+
+		if len(d.Tools) > 0 {
+			calls := make([]string, len(d.Tools))
+			for i, l := range d.Tools {
+				calls[i] = op(l)
+			}
+			add(m.styles.Muted.Render("Tools ") + strings.Join(calls, " "))
+		}
+		hunks := diff.Hunks(d.Responses, sessionDiffContext)
+		if len(hunks) == 0 {
+			add(m.styles.Muted.Render("Responses identical"))
+		}
+		for _, h := range hunks {
+			add(m.styles.Info.Render(h.Header()))
+			for _, l := range h.Lines {
+				add(op(l))
+			}
+		}
+	}
+	if len(m.sessionDiffs) == 0 {
+		add(m.styles.Muted.Render("Both sessions are empty"))
+	}
+}
+
+// jumpSessionDiff scrolls to the previous or next changed turn
+func (m *Model) jumpSessionDiff(forward bool) {
+	turns := m.sessionDiffTurns
+	if forward {
+		if i := slices.IndexFunc(turns, func(line int) bool { return line > m.sessionDiffScroll }); i >= 0 {
+			m.sessionDiffScroll = turns[i]
+		}
+	} else {
+		for i := len(turns) - 1; i >= 0; i-- {
+			if turns[i] < m.sessionDiffScroll {
+				m.sessionDiffScroll = turns[i]
+				break
+			}
+		}
+	}
+	m.sessionDiffScroll = min(m.sessionDiffScroll, max(len(m.sessionDiffLines)-m.historyPageHeight(), 0))
+}
+
+// renderSessionDiffView displays the diff of the two marked sessions
+func (m Model) renderSessionDiffView() string {
+	title := "Session diff"
+	if len(m.markedSessions) == 2 {
+		title = fmt.Sprintf("Diff  A: %s  ↔  B: %s", sessionLabel(m.markedSessions[0]), sessionLabel(m.markedSessions[1]))
+	}
+
+	var summary, content string
+	switch {
+	case m.sessionDiffError != "":
+		content = m.styles.Error.Render("Error: " + m.sessionDiffError)
+	case m.sessionDiffs == nil:
+		content = m.styles.Muted.Render("Reading sessions…")
+	default:
+		summary = fmt.Sprintf("%d aligned turns, %d differ · %s/%s jumps between changes",
+			len(m.sessionDiffs), len(m.sessionDiffTurns), firstKey(m.keys.Prev), firstKey(m.keys.Next))
+		pageHeight := m.historyPageHeight()
+		start := min(m.sessionDiffScroll, len(m.sessionDiffLines))
+		end := min(start+pageHeight, len(m.sessionDiffLines))
+		content = strings.Join(m.sessionDiffLines[start:end], "\n")
+	}
+	return m.renderHistoryPage(title, summary, content)
+}
+
+// sessionLabel names a session by its title, first prompt or ID
+func sessionLabel(s SessionInfo) string {
+	label := s.Title
+	if label == "" {
+		label = s.FirstPrompt
+	}
+	if label == "" {
+		label = s.ID
+	}
+	return ansi.Truncate(label, 30, "…")
+}
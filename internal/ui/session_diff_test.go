@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thieso2/promptwatch/internal/config"
+)
+
+// TestSessionDiff tests diffing two marked sessions turn by turn
+func TestSessionDiff(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	dir := t.TempDir()
+	write := func(name, prompt, reply, tool string) string {
+		path := filepath.Join(dir, name)
+		data := `{"type":"user","timestamp":"2026-01-09T14:00:00.000Z","message":{"role":"user","content":"set up the project"}}
+{"type":"assistant","timestamp":"2026-01-09T14:00:05.000Z","message":{"role":"assistant","model":"claude-opus-4","content":[{"type":"text","text":"Done."}]}}
+{"type":"user","timestamp":"2026-01-09T14:01:00.000Z","message":{"role":"user","content":"` + prompt + `"}}
+{"type":"assistant","timestamp":"2026-01-09T14:01:05.000Z","message":{"role":"assistant","model":"claude-opus-4","content":[{"type":"text","text":"Added it.\n` + reply + `"}]}}
+{"type":"assistant","timestamp":"2026-01-09T14:01:10.000Z","message":{"role":"assistant","model":"claude-opus-4","content":[{"type":"tool_use","id":"t1","name":"` + tool + `","input":{}}]}}
+`
+		os.WriteFile(path, []byte(data), 0644)
+		return path
+	}
+	a := write("a.jsonl", "add a login page", "Uses sessions.", "Edit")
+	b := write("b.jsonl", "add a login page with OAuth", "Uses OAuth.", "Write")
+
+	var model tea.Model = NewModel(config.Default(), false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m := model.(Model)
+	m.viewMode = ViewSessions
+	model, _ = m.Update(sessionsMsg{sessions: []SessionInfo{
+		{ID: "a", Path: a, Title: "login A", LastMessageTime: 2},
+		{ID: "b", Path: b, Title: "login B", LastMessageTime: 1},
+	}})
+
+	model, _ = model.Update(keyPress("x"))
+	model, cmd := model.Update(keyPress("D"))
+	if m := model.(Model); cmd != nil || m.viewMode != ViewSessions || !m.statusIsError {
+		t.Fatalf("Diffed a single session: mode %v, status %q", m.viewMode, m.statusMessage)
+	}
+
+	model, _ = model.Update(keyPress("down"))
+	model, _ = model.Update(keyPress("x"))
+	model, cmd = model.Update(keyPress("D"))
+	if cmd == nil || model.(Model).viewMode != ViewSessionDiff {
+		t.Fatalf("Session diff not opened")
+	}
+	model, _ = model.Update(cmd())
+
+	view := model.View()
+	for _, want := range []string{"login A", "login B", "2 aligned turns, 1 differ", "A1 ↔ B1  identical  set up the project",
+		"prompt changed", "-> add a login page", "+> add a login page with OAuth", "-Edit +Write", "-Uses sessions.", "+Uses OAuth.", " Added it."} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Session diff missing %q:\n%s", want, view)
+		}
+	}
+
+	model, _ = model.Update(keyPress("esc"))
+	if m := model.(Model); m.viewMode != ViewSessions || len(m.markedSessions) != 2 {
+		t.Errorf("Back: mode %v, %d marked", m.viewMode, len(m.markedSessions))
+	}
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"io"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/notify"
+)
+
+// ConfigReloadedMsg delivers a freshly loaded config (sent on SIGHUP)
+type ConfigReloadedMsg struct {
+	Config *config.Config
+}
+
+// ConfigErrorMsg reports a config file that failed to reload
+type ConfigErrorMsg struct {
+	Err error
+}
+
+// applyConfig installs cfg and rebuilds everything derived from it
+func (m *Model) applyConfig(cfg *config.Config) {
+	m.config = cfg
+	m.updateInterval = cfg.UI.RefreshInterval
+
+	m.keys = NewKeyMap(cfg.UI.Keymap, cfg.Keys)
+	m.theme = resolveTheme(cfg)
+	m.styles = newStyles(m.theme)
+	m.renderers = newRendererRegistry(cfg)
+	m.watchdog.SetRules(cfg.HealthRules())
+	m.events.SetRules(cfg.EventRules())
+	m.notifier = backgroundNotifier(cfg.Notify)
+	m.terminalNotify = terminalNotifier(cfg.Notify, io.Discard) != nil
+	m.layoutDetail()
+
+	// A reloaded config keeps the active view if it still exists
+	m.columns = cfg.Columns
+	if v, ok := cfg.View(m.activeView); ok {
+		m.setViewColumns(v)
+	} else {
+		m.activeView = ""
+	}
+
+	m.rebuildTables()
+}
+
+// terminalNotifier builds the configured terminal backends (bell, OSC), which
+// write escape sequences to term. It returns nil when none is configured.
+func terminalNotifier(n config.NotifyConfig, term io.Writer) notify.Notifier {
+	var notifiers notify.Multi
+	for _, backend := range n.Backends {
+		switch backend {
+		case "bell":
+			notifiers = append(notifiers, notify.Bell{W: term})
+		case "osc9":
+			notifiers = append(notifiers, notify.OSC{W: term, Code: 9})
+		case "osc777":
+			notifiers = append(notifiers, notify.OSC{W: term, Code: 777})
+		}
+	}
+	if len(notifiers) == 0 {
+		return nil
+	}
+	return notifiers
+}
+
+// backgroundNotifier builds the configured backends that deliver outside the
+// terminal (command, webhook). It returns nil when none is configured.
+func backgroundNotifier(n config.NotifyConfig) notify.Notifier {
+	var notifiers notify.Multi
+	for _, backend := range n.Backends {
+		switch backend {
+		case "command":
+			notifiers = append(notifiers, notify.Command{Args: n.Command})
+		case "webhook":
+			notifiers = append(notifiers, notify.Webhook{URL: n.Webhook})
+		}
+	}
+	if len(notifiers) == 0 {
+		return nil
+	}
+	return notifiers
+}
+
+// thresholdStyle colours value by the configured warning and critical limits
+func (m *Model) thresholdStyle(value, warning, critical float64) lipgloss.Style {
+	if value >= critical {
+		return m.styles.Error
+	} else if value >= warning {
+		return m.styles.Warning
+	}
+	return m.styles.OK
+}
+
+// cpuStyle returns the highlight style for a CPU percentage
+func (m *Model) cpuStyle(percent float64) lipgloss.Style {
+	t := m.config.Thresholds
+	return m.thresholdStyle(percent, t.CPUWarning, t.CPUCritical)
+}
+
+// memoryStyle returns the highlight style for resident memory in MB
+func (m *Model) memoryStyle(mb float64) lipgloss.Style {
+	t := m.config.Thresholds
+	return m.thresholdStyle(mb, t.MemoryWarningMB, t.MemoryCriticalMB)
+}
+
+// costStyle returns the highlight style for a message cost in USD
+func (m *Model) costStyle(cost float64) lipgloss.Style {
+	t := m.config.Thresholds
+	return m.thresholdStyle(cost, t.CostWarning, t.CostCritical)
+}
+
+// contextStyle returns the highlight style for a context window percentage
+func (m *Model) contextStyle(percent float64) lipgloss.Style {
+	t := m.config.Thresholds
+	return m.thresholdStyle(percent, t.ContextWarning, t.ContextCritical)
+}
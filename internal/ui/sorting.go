@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/monitor"
+	"github.com/thieso2/promptwatch/internal/types"
+)
+
+// tableSort is the sort column and direction of a table
+type tableSort struct {
+	Column string
+	Desc   bool
+}
+
+// defaultSorts holds the order of each table, keyed by view mode, until the user picks another
+var defaultSorts = map[string]tableSort{
+	"processes": {Column: "pid"},
+	"projects":  {Column: "modified", Desc: true},
+	"sessions":  {Column: "lastmsgtime", Desc: true},
+	"messages":  {Column: "time", Desc: true},
+}
+
+// ascendingColumns are sorted A to Z first; numbers, times and states start with the largest
+var ascendingColumns = map[string]bool{
+	"pid": true, "workdir": true, "cmd": true, "root": true, "name": true,
+	"version": true, "gitbranch": true, "lastmessage": true,
+}
+
+// parseSort reads a sort setting such as "cpu" or "-cpu" (descending)
+func parseSort(s string) tableSort {
+	column, desc := strings.CutPrefix(s, "-")
+	return tableSort{Column: column, Desc: desc}
+}
+
+// String formats the sort the way parseSort reads it
+func (s tableSort) String() string {
+	if s.Desc {
+		return "-" + s.Column
+	}
+	return s.Column
+}
+
+// arrow marks the sorted column's header with the direction
+func (s tableSort) arrow() string {
+	if s.Desc {
+		return "▼"
+	}
+	return "▲"
+}
+
+// markSorted appends the direction arrow to the title of the sorted column
+func markSorted(specs []columnSpec, s tableSort) []columnSpec {
+	marked := slices.Clone(specs)
+	for i := range marked {
+		if marked[i].Key == s.Column {
+			marked[i].Title += " " + s.arrow()
+		}
+	}
+	return marked
+}
+
+// tableOf returns the view mode name of the table shown in mode, or "" for
+// views whose sort order is not a column
+func tableOf(mode ViewMode) string {
+	switch mode {
+	case ViewProcesses:
+		return "processes"
+	case ViewProjects:
+		return "projects"
+	case ViewSessions:
+		return "sessions"
+	case ViewSessionDetail:
+		return "messages"
+	}
+	return ""
+}
+
+// sortOf returns the sort state of a table
+func (m *Model) sortOf(table string) *tableSort {
+	switch table {
+	case "processes":
+		return &m.processSort
+	case "projects":
+		return &m.projectSort
+	case "sessions":
+		return &m.sessionSort
+	}
+	return &m.messageSort
+}
+
+// cycleSort sorts a table by its next visible column, largest or A first
+func (m *Model) cycleSort(table string) {
+	s := m.sortOf(table)
+	columns := config.SortColumns[table]
+	if table != "messages" {
+		visible, _ := m.columnKeys(table)
+		columns = slices.DeleteFunc(slices.Clone(columns), func(c string) bool { return !slices.Contains(visible, c) })
+	}
+	if len(columns) == 0 {
+		return
+	}
+	next := columns[(slices.Index(columns, s.Column)+1)%len(columns)]
+	*s = tableSort{Column: next, Desc: !ascendingColumns[next]}
+}
+
+// sortLabel describes a table's order for status lines
+func (m *Model) sortLabel(table string) string {
+	s := *m.sortOf(table)
+	if table == "messages" && s.Column == "time" {
+		if s.Desc {
+			return "newest→oldest"
+		}
+		return "oldest→newest"
+	}
+	return s.Column + " " + s.arrow()
+}
+
+// applySort orders the rows of the table that was just resorted
+func (m *Model) applySort(table string) {
+	m.statusMessage = "Sorted by " + m.sortLabel(table)
+	m.statusIsError = false
+	switch table {
+	case "messages":
+		m.selectedMessageIdx = 0
+		m.updateMessageTable()
+		m.messageViewport.GotoTop()
+	case "sessions":
+		m.selectedSessionIdx = 0
+		m.updateSessionTable()
+	case "projects":
+		m.selectedProjIdx = 0
+		fallthrough
+	default:
+		// The header arrow is part of the process and project columns
+		m.rebuildTables()
+	}
+}
+
+// sortBy stable-sorts rows by compare, reversed for descending sorts
+func sortBy[T any](rows []T, s tableSort, compare func(a, b T) int) {
+	slices.SortStableFunc(rows, func(a, b T) int {
+		if s.Desc {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
+}
+
+// processOrder compares processes by a process table column
+func (m *Model) processOrder(column string) func(a, b types.ClaudeProcess) int {
+	switch column {
+	case "status":
+		return func(a, b types.ClaudeProcess) int {
+			return cmp.Compare(m.states[a.PID].Urgency(), m.states[b.PID].Urgency())
+		}
+	case "cpu":
+		return func(a, b types.ClaudeProcess) int { return cmp.Compare(a.CPUPercent, b.CPUPercent) }
+	case "mem":
+		return func(a, b types.ClaudeProcess) int { return cmp.Compare(a.MemoryMB, b.MemoryMB) }
+	case "ctx":
+		return func(a, b types.ClaudeProcess) int { return cmp.Compare(a.ContextPercent, b.ContextPercent) }
+	case "alert":
+		return func(a, b types.ClaudeProcess) int { return cmp.Compare(len(m.alerts[a.PID]), len(m.alerts[b.PID])) }
+	case "uptime":
+		return func(a, b types.ClaudeProcess) int { return cmp.Compare(a.Uptime, b.Uptime) }
+	case "workdir":
+		return func(a, b types.ClaudeProcess) int { return strings.Compare(a.WorkingDir, b.WorkingDir) }
+	case "cmd":
+		return func(a, b types.ClaudeProcess) int { return strings.Compare(a.Command, b.Command) }
+	}
+	return func(a, b types.ClaudeProcess) int { return cmp.Compare(a.PID, b.PID) }
+}
+
+// projectOrder compares projects by a projects table column
+func projectOrder(column string) func(a, b ProjectDir) int {
+	switch column {
+	case "root":
+		return func(a, b ProjectDir) int { return strings.Compare(a.Root, b.Root) }
+	case "name":
+		return func(a, b ProjectDir) int { return strings.Compare(a.DisplayName, b.DisplayName) }
+	case "sessions":
+		return func(a, b ProjectDir) int { return cmp.Compare(a.Sessions, b.Sessions) }
+	}
+	return func(a, b ProjectDir) int { return a.Modified.Compare(b.Modified) }
+}
+
+// sessionOrder compares sessions by a session table column
+func sessionOrder(column string) func(a, b SessionInfo) int {
+	switch column {
+	case "version":
+		return func(a, b SessionInfo) int { return strings.Compare(a.Version, b.Version) }
+	case "gitbranch":
+		return func(a, b SessionInfo) int { return strings.Compare(a.GitBranch, b.GitBranch) }
+	case "tokens":
+		return func(a, b SessionInfo) int { return cmp.Compare(a.TotalTokens, b.TotalTokens) }
+	case "started":
+		return func(a, b SessionInfo) int { return a.Summary.Started.Compare(b.Summary.Started) }
+	case "duration":
+		return func(a, b SessionInfo) int {
+			return cmp.Compare(a.Summary.Ended.Sub(a.Summary.Started), b.Summary.Ended.Sub(b.Summary.Started))
+		}
+	case "commits":
+		return func(a, b SessionInfo) int { return cmp.Compare(a.Commits, b.Commits) }
+	case "lastmessage":
+		return func(a, b SessionInfo) int { return strings.Compare(a.LastMessage, b.LastMessage) }
+	}
+	return func(a, b SessionInfo) int { return cmp.Compare(a.LastMessageTime, b.LastMessageTime) }
+}
+
+// messageOrder compares messages by time, cost or tokens
+func messageOrder(column string) func(a, b monitor.Message) int {
+	switch column {
+	case "cost":
+		return func(a, b monitor.Message) int {
+			costA, _ := calculateMessageCost(&a)
+			costB, _ := calculateMessageCost(&b)
+			return cmp.Compare(costA, costB)
+		}
+	case "tokens":
+		tokens := func(m monitor.Message) int {
+			return m.InputTokens + m.OutputTokens + m.CacheCreation + m.CacheRead
+		}
+		return func(a, b monitor.Message) int { return cmp.Compare(tokens(a), tokens(b)) }
+	}
+	return func(a, b monitor.Message) int { return a.Timestamp.Compare(b.Timestamp) }
+}
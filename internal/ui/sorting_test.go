@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/types"
+)
+
+// TestSortColumns tests cycling the sort column and reversing it
+func TestSortColumns(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	cfg := config.Default()
+	cfg.Columns.Processes = []string{"pid", "cpu", "mem"}
+	var model tea.Model = NewModel(cfg, false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	model, _ = model.Update(processesMsg{processes: []types.ClaudeProcess{
+		{PID: 1, CPUPercent: 5, MemoryMB: 300},
+		{PID: 2, CPUPercent: 50, MemoryMB: 100},
+		{PID: 3, CPUPercent: 20, MemoryMB: 200},
+	}})
+
+	order := func() string {
+		var pids []string
+		for _, proc := range model.(Model).processes {
+			pids = append(pids, formatPID(proc.PID))
+		}
+		return strings.Join(pids, " ")
+	}
+
+	steps := []struct {
+		press  string
+		want   string
+		header string
+	}{
+		{"s", "2 3 1", "CPU% ▼"},
+		{"S", "1 3 2", "CPU% ▲"},
+		{"s", "1 3 2", "MEM ▼"},
+		{"s", "1 2 3", "PID ▲"}, // Hidden columns are skipped
+	}
+	for _, step := range steps {
+		model, _ = model.Update(keyPress(step.press))
+		if got := order(); got != step.want {
+			t.Errorf("After %q: order %q, want %q", step.press, got, step.want)
+		}
+		if view := model.View(); !strings.Contains(view, step.header) {
+			t.Errorf("After %q: header %q missing:\n%s", step.press, step.header, view)
+		}
+	}
+}
+
+// TestColumnEditor tests hiding and moving a column and saving the result
+func TestColumnEditor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	path := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(path, []byte("[ui]\nkeymap = \"default\"\n"), 0600)
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	cfg.Columns.Sessions = []string{"version", "gitbranch", "tokens"}
+
+	var model tea.Model = NewModel(cfg, false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	m := model.(Model)
+	m.viewMode = ViewSessions
+	model = m
+
+	// Hide VER, then move TOKENS in front of BRANCH
+	for _, press := range []string{"c", " ", "down", "down", "left", "enter"} {
+		model, _ = model.Update(keyPress(press))
+	}
+	m = model.(Model)
+	if got := strings.Join(m.columns.Sessions, ","); got != "tokens,gitbranch" {
+		t.Errorf("Session columns = %q, want tokens,gitbranch", got)
+	}
+
+	saved, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Reloading the config failed: %v", err)
+	}
+	if got := strings.Join(saved.Columns.Sessions, ","); got != "tokens,gitbranch" || saved.UI.Keymap != "default" {
+		t.Errorf("Saved columns = %q, keymap %q; status %q", got, saved.UI.Keymap, m.statusMessage)
+	}
+
+	// Esc leaves the columns alone
+	for _, press := range []string{"c", " ", "esc"} {
+		model, _ = model.Update(keyPress(press))
+	}
+	if got := strings.Join(model.(Model).columns.Sessions, ","); got != "tokens,gitbranch" {
+		t.Errorf("Cancelled edit changed columns to %q", got)
+	}
+}
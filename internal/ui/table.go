@@ -4,110 +4,97 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/charmbracelet/lipgloss"
 	"github.com/evertras/bubble-table/table"
+	"github.com/thieso2/promptwatch/internal/monitor"
 )
 
-// createTable initializes the bubble-table model with columns and styling
-func createTable() table.Model {
-	columns := []table.Column{
-		table.NewColumn("pid", "PID", 8),
-		table.NewColumn("cpu", "CPU%", 10),
-		table.NewColumn("mem", "MEM", 12),
-		table.NewColumn("uptime", "UPTIME", 12),
-		table.NewColumn("workdir", "WORKDIR", 60),
-		table.NewColumn("cmd", "COMMAND", 100),
-	}
+// columnSpec describes a table column. Flex columns share the width left
+// over by the fixed columns in proportion to Flex, never going below Width.
+type columnSpec struct {
+	Key   string
+	Title string
+	Width int // Fixed width, or minimum width for flex columns
+	Flex  int // Share of the remaining width (0 = fixed)
+}
 
-	t := table.New(columns).
-		WithPageSize(20).
-		WithBaseStyle(
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("255")),
-		).
-		Focused(true)
+// processColumns lists every process table column in default order
+var processColumns = []columnSpec{
+	{Key: "pid", Title: "PID", Width: 8},
+	{Key: "cpu", Title: "CPU%", Width: 10},
+	{Key: "mem", Title: "MEM", Width: 12},
+	{Key: "ctx", Title: "CTX%", Width: 6},
+	{Key: "status", Title: "STATUS", Width: 22},
+	{Key: "alert", Title: "ALERT", Width: 14},
+	{Key: "uptime", Title: "UPTIME", Width: 12},
+	{Key: "workdir", Title: "WORKDIR", Width: 20, Flex: 30},
+	{Key: "cmd", Title: "COMMAND", Width: 20, Flex: 42},
+}
 
-	return t
+// projectColumns lists every projects table column in default order
+var projectColumns = []columnSpec{
+	{Key: "root", Title: "ROOT", Width: 12},
+	{Key: "name", Title: "PROJECT", Width: 25, Flex: 40},
+	{Key: "modified", Title: "MODIFIED", Width: 16, Flex: 30},
+	{Key: "sessions", Title: "SESSIONS", Width: 8, Flex: 30},
 }
 
-// createTableWithWidth creates a table with columns sized for the given width
-func createTableWithWidth(width int) table.Model {
-	// Calculate responsive column widths
-	// Reserve space for borders and padding (roughly 2 chars per column)
-	availableWidth := width - 14 // Reserve for borders and spacing
-
-	// Proportional distribution: PID(5%) CPU(7%) MEM(8%) UPTIME(8%) WORKDIR(30%) CMD(42%)
-	pidWidth := 8
-	cpuWidth := 10
-	memWidth := 12
-	uptimeWidth := 12
-	workdirWidth := (availableWidth * 30) / 100
-	cmdWidth := availableWidth - pidWidth - cpuWidth - memWidth - uptimeWidth - workdirWidth
-
-	// Ensure minimum widths
-	if workdirWidth < 20 {
-		workdirWidth = 20
+// selectColumns returns the specs named by keys in that order, or all specs when keys is empty
+func selectColumns(specs []columnSpec, keys []string) []columnSpec {
+	if len(keys) == 0 {
+		return specs
 	}
-	if cmdWidth < 20 {
-		cmdWidth = 20
-	}
-
-	columns := []table.Column{
-		table.NewColumn("pid", "PID", pidWidth),
-		table.NewColumn("cpu", "CPU%", cpuWidth),
-		table.NewColumn("mem", "MEM", memWidth),
-		table.NewColumn("uptime", "UPTIME", uptimeWidth),
-		table.NewColumn("workdir", "WORKDIR", workdirWidth),
-		table.NewColumn("cmd", "COMMAND", cmdWidth),
+	var selected []columnSpec
+	for _, key := range keys {
+		for _, spec := range specs {
+			if spec.Key == key {
+				selected = append(selected, spec)
+			}
+		}
 	}
-
-	t := table.New(columns).
-		WithPageSize(20).
-		WithBaseStyle(
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("255")),
-		).
-		Focused(true)
-
-	return t
-}
-
-// styleHighCPU applies red styling to high CPU values
-func styleHighCPU(cpu string) string {
-	// This would be applied in view.go when rendering
-	return cpu
+	return selected
 }
 
-// styleWarningMemory applies yellow styling to high memory values
-func styleWarningMemory(mem string) string {
-	// This would be applied in view.go when rendering
-	return mem
-}
+// layoutColumns sizes specs to fill the available width
+func layoutColumns(specs []columnSpec, available int) []table.Column {
+	remaining := available
+	totalFlex := 0
+	for _, spec := range specs {
+		if spec.Flex == 0 {
+			remaining -= spec.Width
+		}
+		totalFlex += spec.Flex
+	}
 
-// createSessionTable initializes the session table
-func createSessionTable() table.Model {
-	columns := []table.Column{
-		table.NewColumn("id", "SESSION ID", 40),
-		table.NewColumn("title", "TITLE", 80),
-		table.NewColumn("updated", "UPDATED", 20),
+	columns := make([]table.Column, 0, len(specs))
+	for _, spec := range specs {
+		width := spec.Width
+		if spec.Flex > 0 {
+			if share := remaining * spec.Flex / totalFlex; share > width {
+				width = share
+			}
+		}
+		columns = append(columns, table.NewColumn(spec.Key, spec.Title, width))
 	}
+	return columns
+}
 
-	t := table.New(columns).
+// newTable creates a focused table; colours come from the theme (see Model.styleTable)
+func newTable(columns []table.Column) table.Model {
+	return table.New(columns).
 		WithPageSize(20).
-		WithBaseStyle(
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("255")),
-		).
 		Focused(true)
+}
 
-	return t
+// createTableWithWidth creates a process table with the given columns sized for the width
+func createTableWithWidth(width int, keys []string, sorted tableSort) table.Model {
+	// Reserve space for borders and padding (roughly 2 chars per column)
+	availableWidth := width - 14
+
+	return newTable(layoutColumns(markSorted(selectColumns(processColumns, keys), sorted), availableWidth))
 }
 
 // createSessionTableWithWidth creates a session table with columns sized for the given width
-func createSessionTableWithWidth(width int) table.Model {
-	// Calculate responsive column widths
-	availableWidth := width - 6 // Reserve for borders and spacing
-
+func createSessionTableWithWidth(width int, keys []string, sorted tableSort) table.Model {
 	// Column width distribution - sized for actual data
 	// Version: 8 chars (v2.1.25)
 	// GitBranch: 20 chars (ingress-validation or feature/name)
@@ -115,58 +102,35 @@ func createSessionTableWithWidth(width int) table.Model {
 	// Tokens: 16 chars (5039568/5211 format)
 	// Started: 16 chars (2026-01-30 14:23)
 	// Duration: 7 chars (12h34m or 999m)
+	// Commits: 9 chars (COMMITS header)
 	// Remaining for last message preview
-	versionWidth := 8
-	gitWidth := 20
-	lastMsgTimeWidth := 16
-	tokensWidth := 16
-	startedWidth := 16
-	durationWidth := 7
-	lastMsgWidth := availableWidth - versionWidth - gitWidth - lastMsgTimeWidth - tokensWidth - startedWidth - durationWidth
-
-	// Ensure minimum width for last message
-	if lastMsgWidth < 30 {
-		lastMsgWidth = 30
-	}
-
-	columns := []table.Column{
-		table.NewColumn("version", "VER", versionWidth),
-		table.NewColumn("gitbranch", "BRANCH", gitWidth),
-		table.NewColumn("lastmsgtime", "LAST MSG", lastMsgTimeWidth),
-		table.NewColumn("tokens", "TOKENS", tokensWidth),
-		table.NewColumn("started", "START", startedWidth),
-		table.NewColumn("duration", "LEN", durationWidth),
-		table.NewColumn("lastmessage", "PREVIEW", lastMsgWidth),
+	widths := ColumnWidths{
+		Version:     8,
+		GitBranch:   20,
+		LastMsgTime: 16,
+		Tokens:      16,
+		Started:     16,
+		Duration:    7,
+		Commits:     9,
+		LastMessage: 30,
 	}
 
-	t := table.New(columns).
-		WithPageSize(20).
-		WithBaseStyle(
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("255")),
-		).
-		Focused(true)
-
-	return t
+	return newTable(layoutColumns(markSorted(selectColumns(sessionColumns(widths), keys), sorted), width-6))
 }
 
-// createMessageTable initializes the message table
-func createMessageTable() table.Model {
-	columns := []table.Column{
-		table.NewColumn("role", "ROLE", 12),
-		table.NewColumn("content", "MESSAGE", 76),
-		table.NewColumn("time", "TIME", 12),
+// sessionColumns lists every session table column in default order.
+// The preview column takes whatever width the others leave.
+func sessionColumns(widths ColumnWidths) []columnSpec {
+	return []columnSpec{
+		{Key: "version", Title: "VER", Width: widths.Version},
+		{Key: "gitbranch", Title: "BRANCH", Width: widths.GitBranch},
+		{Key: "lastmsgtime", Title: "LAST MSG", Width: widths.LastMsgTime},
+		{Key: "tokens", Title: "TOKENS", Width: widths.Tokens},
+		{Key: "started", Title: "START", Width: widths.Started},
+		{Key: "duration", Title: "LEN", Width: widths.Duration},
+		{Key: "commits", Title: "COMMITS", Width: widths.Commits},
+		{Key: "lastmessage", Title: "PREVIEW", Width: widths.LastMessage, Flex: 1},
 	}
-
-	t := table.New(columns).
-		WithPageSize(15).
-		WithBaseStyle(
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("255")),
-		).
-		Focused(true)
-
-	return t
 }
 
 // createMessageTableWithWidth creates a message table with columns sized for the given width
@@ -190,46 +154,44 @@ func createMessageTableWithWidth(width int) table.Model {
 		table.NewColumn("time", "TIME", timeWidth),
 	}
 
-	t := table.New(columns).
-		WithPageSize(15).
-		WithBaseStyle(
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("255")),
-		).
-		Focused(true)
-
-	return t
+	return newTable(columns).WithPageSize(15)
 }
 
 // createProjectsTableWithWidth creates a projects directory table with responsive widths
-func createProjectsTableWithWidth(width int) table.Model {
-	// Calculate responsive column widths
-	availableWidth := width - 6
-
-	nameWidth := (availableWidth * 40) / 100
-	modifiedWidth := (availableWidth * 30) / 100
-	sessionsWidth := availableWidth - nameWidth - modifiedWidth
+func createProjectsTableWithWidth(width int, keys []string, sorted tableSort) table.Model {
+	return newTable(layoutColumns(markSorted(selectProjectColumns(keys), sorted), width-6))
+}
 
-	// Ensure minimum widths
-	if nameWidth < 25 {
-		nameWidth = 25
+// selectProjectColumns returns the projects table columns named by keys. By default
+// the ROOT column is only shown when projects come from more than one data root.
+func selectProjectColumns(keys []string) []columnSpec {
+	specs := selectColumns(projectColumns, keys)
+	if len(keys) == 0 && len(monitor.DataRoots()) <= 1 {
+		specs = specs[1:]
 	}
+	return specs
+}
 
-	columns := []table.Column{
-		table.NewColumn("name", "PROJECT", nameWidth),
-		table.NewColumn("modified", "MODIFIED", modifiedWidth),
-		table.NewColumn("sessions", "SESSIONS", sessionsWidth),
+// columnKeys returns the keys of a table's visible columns in display order,
+// and of all its columns in default order
+func (m *Model) columnKeys(tableName string) (visible, all []string) {
+	var shown, specs []columnSpec
+	switch tableName {
+	case "processes":
+		shown, specs = selectColumns(processColumns, m.columns.Processes), processColumns
+	case "projects":
+		shown, specs = selectProjectColumns(m.columns.Projects), projectColumns
+	case "sessions":
+		specs = sessionColumns(ColumnWidths{})
+		shown = selectColumns(specs, m.columns.Sessions)
 	}
-
-	t := table.New(columns).
-		WithPageSize(20).
-		WithBaseStyle(
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("255")),
-		).
-		Focused(true)
-
-	return t
+	for _, spec := range shown {
+		visible = append(visible, spec.Key)
+	}
+	for _, spec := range specs {
+		all = append(all, spec.Key)
+	}
+	return visible, all
 }
 
 // ColumnWidths holds calculated widths for session table columns
@@ -240,6 +202,7 @@ type ColumnWidths struct {
 	Tokens      int
 	Started     int
 	Duration    int
+	Commits     int
 	LastMessage int
 }
 
@@ -343,8 +306,10 @@ func CalculateSessionTableWidths(width int, sessions []SessionInfo) ColumnWidths
 		durationWidth = len("LEN") + 2
 	}
 
+	commitsWidth := len("COMMITS") + 2
+
 	// Fixed columns total
-	fixedWidth := versionWidth + gitWidth + lastMsgTimeWidth + tokensWidth + startedWidth + durationWidth
+	fixedWidth := versionWidth + gitWidth + lastMsgTimeWidth + tokensWidth + startedWidth + durationWidth + commitsWidth
 
 	// Last message preview gets remaining space, but ensure minimum
 	lastMessageWidth := availableWidth - fixedWidth
@@ -359,31 +324,15 @@ func CalculateSessionTableWidths(width int, sessions []SessionInfo) ColumnWidths
 		Tokens:      tokensWidth,
 		Started:     startedWidth,
 		Duration:    durationWidth,
+		Commits:     commitsWidth,
 		LastMessage: lastMessageWidth,
 	}
 }
 
-// CreateSessionTableWithDynamicWidths creates a session table with dynamically calculated column widths
-func CreateSessionTableWithDynamicWidths(width int, sessions []SessionInfo) table.Model {
+// CreateSessionTableWithDynamicWidths creates a session table with the given columns
+// and widths calculated from the session data
+func CreateSessionTableWithDynamicWidths(width int, sessions []SessionInfo, keys []string, sorted tableSort) table.Model {
 	widths := CalculateSessionTableWidths(width, sessions)
 
-	columns := []table.Column{
-		table.NewColumn("version", "VER", widths.Version),
-		table.NewColumn("gitbranch", "BRANCH", widths.GitBranch),
-		table.NewColumn("lastmsgtime", "LAST MSG", widths.LastMsgTime),
-		table.NewColumn("tokens", "TOKENS", widths.Tokens),
-		table.NewColumn("started", "START", widths.Started),
-		table.NewColumn("duration", "LEN", widths.Duration),
-		table.NewColumn("lastmessage", "PREVIEW", widths.LastMessage),
-	}
-
-	t := table.New(columns).
-		WithPageSize(20).
-		WithBaseStyle(
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("255")),
-		).
-		Focused(true)
-
-	return t
+	return newTable(layoutColumns(markSorted(selectColumns(sessionColumns(widths), keys), sorted), width-6))
 }
@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/thieso2/promptwatch/internal/config"
+)
+
+// Theme holds the colour of each semantic role. Colours are ANSI numbers or
+// hex values; an empty colour leaves the terminal default.
+type Theme struct {
+	Highlight  string // Titles and emphasised labels
+	Text       string // Body text
+	Faint      string // Secondary body text
+	Muted      string // Footers, hints and counts
+	Subtle     string // Metadata lines
+	Border     string // Separators and frames
+	Accent     string // User prompts and the selection marker
+	Assistant  string // Claude responses
+	Tool       string // Tool calls
+	Info       string // Token details
+	Success    string // OK values and summaries
+	Warning    string // Values above the warning threshold
+	Error      string // Errors and values above the critical threshold
+	SelectedFg string // Selected row text
+	SelectedBg string // Selected row background
+
+	Markdown string // glamour style for rendered markdown: "dark", "light" or "notty"
+	Syntax   string // chroma style for highlighted code; empty leaves code plain
+}
+
+// themes holds the built-in themes, keyed by the names in config.BuiltinThemes
+var themes = map[string]Theme{
+	"dark": {
+		Highlight:  "11",
+		Text:       "255",
+		Faint:      "250",
+		Muted:      "8",
+		Subtle:     "244",
+		Border:     "238",
+		Accent:     "226",
+		Assistant:  "51",
+		Tool:       "82",
+		Info:       "6",
+		Success:    "10",
+		Warning:    "3",
+		Error:      "1",
+		SelectedFg: "228",
+		SelectedBg: "23",
+		Markdown:   "dark",
+		Syntax:     "monokai",
+	},
+	"light": {
+		Highlight:  "94",
+		Text:       "235",
+		Faint:      "238",
+		Muted:      "244",
+		Subtle:     "240",
+		Border:     "250",
+		Accent:     "130",
+		Assistant:  "25",
+		Tool:       "28",
+		Info:       "30",
+		Success:    "28",
+		Warning:    "130",
+		Error:      "160",
+		SelectedFg: "231",
+		SelectedBg: "25",
+		Markdown:   "light",
+		Syntax:     "github",
+	},
+	"high-contrast": {
+		Highlight:  "11",
+		Text:       "15",
+		Faint:      "15",
+		Muted:      "7",
+		Subtle:     "7",
+		Border:     "15",
+		Accent:     "11",
+		Assistant:  "14",
+		Tool:       "10",
+		Info:       "14",
+		Success:    "10",
+		Warning:    "11",
+		Error:      "9",
+		SelectedFg: "0",
+		SelectedBg: "11",
+		Markdown:   "dark",
+		Syntax:     "native",
+	},
+	"no-color": {Markdown: "notty"},
+}
+
+// roles maps the config names of the theme roles to their fields
+func (t *Theme) roles() map[string]*string {
+	return map[string]*string{
+		"highlight":   &t.Highlight,
+		"text":        &t.Text,
+		"faint":       &t.Faint,
+		"muted":       &t.Muted,
+		"subtle":      &t.Subtle,
+		"border":      &t.Border,
+		"accent":      &t.Accent,
+		"assistant":   &t.Assistant,
+		"tool":        &t.Tool,
+		"info":        &t.Info,
+		"success":     &t.Success,
+		"warning":     &t.Warning,
+		"error":       &t.Error,
+		"selected_fg": &t.SelectedFg,
+		"selected_bg": &t.SelectedBg,
+	}
+}
+
+// resolveTheme picks the configured theme. NO_COLOR always wins; custom
+// themes start from their base theme, and [colors] overrides the threshold colours.
+func resolveTheme(cfg *config.Config) Theme {
+	if os.Getenv("NO_COLOR") != "" {
+		return themes["no-color"]
+	}
+
+	name := cfg.UI.Theme
+	theme, ok := themes[name]
+	if custom, isCustom := cfg.Themes[name]; isCustom {
+		base := custom["base"]
+		if base == "" && !ok {
+			base = "dark"
+		}
+		if base != "" {
+			theme = themes[base]
+		}
+		for role, field := range theme.roles() {
+			if color, set := custom[role]; set {
+				*field = color
+			}
+		}
+	}
+
+	if cfg.Colors.OK != "" {
+		theme.Success = cfg.Colors.OK
+	}
+	if cfg.Colors.Warning != "" {
+		theme.Warning = cfg.Colors.Warning
+	}
+	if cfg.Colors.Critical != "" {
+		theme.Error = cfg.Colors.Critical
+	}
+
+	return theme
+}
+
+// Styles are the semantic styles all views render with
+type Styles struct {
+	Title     lipgloss.Style
+	Highlight lipgloss.Style
+	Text      lipgloss.Style
+	Faint     lipgloss.Style
+	Muted     lipgloss.Style
+	Subtle    lipgloss.Style
+	Border    lipgloss.Style
+	Accent    lipgloss.Style
+	Prompt    lipgloss.Style // User prompt headers
+	Assistant lipgloss.Style // Claude response headers
+	Tool      lipgloss.Style // Tool call headers
+	Info      lipgloss.Style
+	OK        lipgloss.Style
+	Warning   lipgloss.Style
+	Error     lipgloss.Style
+
+	Selected     lipgloss.Style // Selected card header
+	SelectedText lipgloss.Style // Selected card content
+	SelectedRow  lipgloss.Style // Highlighted table row
+	Box          lipgloss.Style // Framed overlays
+}
+
+// newStyles builds the semantic styles for a theme
+func newStyles(t Theme) Styles {
+	fg := func(color string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(themeColor(color))
+	}
+
+	s := Styles{
+		Title:        fg(t.Highlight).Bold(true),
+		Highlight:    fg(t.Highlight),
+		Text:         fg(t.Text),
+		Faint:        fg(t.Faint),
+		Muted:        fg(t.Muted),
+		Subtle:       fg(t.Subtle),
+		Border:       fg(t.Border),
+		Accent:       fg(t.Accent),
+		Prompt:       fg(t.Accent).Bold(true),
+		Assistant:    fg(t.Assistant).Bold(true),
+		Tool:         fg(t.Tool).Bold(true),
+		Info:         fg(t.Info),
+		OK:           fg(t.Success),
+		Warning:      fg(t.Warning),
+		Error:        fg(t.Error),
+		SelectedText: fg(t.Text).Bold(true),
+		SelectedRow:  fg(t.SelectedFg).Background(themeColor(t.SelectedBg)),
+		Box: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(themeColor(t.Muted)).
+			Padding(1, 2),
+	}
+	s.Selected = s.SelectedRow.Bold(true).Padding(0, 1)
+
+	// Without a background colour the selection would be invisible
+	if t.SelectedBg == "" {
+		s.Selected = s.Selected.Reverse(true)
+		s.SelectedRow = s.SelectedRow.Reverse(true)
+	}
+
+	return s
+}
+
+// themeColor converts a theme colour, treating empty as the terminal default
+func themeColor(color string) lipgloss.TerminalColor {
+	if color == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(color)
+}
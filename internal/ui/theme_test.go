@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/thieso2/promptwatch/internal/config"
+)
+
+// TestResolveTheme tests built-in, custom and NO_COLOR theme selection
+func TestResolveTheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		noColor string
+		theme   string
+		custom  map[string]config.ThemeConfig
+		colors  config.ColorsConfig
+		check   func(Theme) string
+		want    string
+	}{
+		{"builtin", "", "light", nil, config.ColorsConfig{},
+			func(th Theme) string { return th.Text }, themes["light"].Text},
+		{"NO_COLOR wins", "1", "light", nil, config.ColorsConfig{},
+			func(th Theme) string { return th.Text }, ""},
+		{"custom overrides role", "", "mine",
+			map[string]config.ThemeConfig{"mine": {"base": "light", "accent": "#d75f00"}}, config.ColorsConfig{},
+			func(th Theme) string { return th.Accent }, "#d75f00"},
+		{"custom keeps base", "", "mine",
+			map[string]config.ThemeConfig{"mine": {"base": "light", "accent": "#d75f00"}}, config.ColorsConfig{},
+			func(th Theme) string { return th.Text }, themes["light"].Text},
+		{"custom defaults to dark", "", "mine",
+			map[string]config.ThemeConfig{"mine": {"tool": "2"}}, config.ColorsConfig{},
+			func(th Theme) string { return th.Assistant }, themes["dark"].Assistant},
+		{"builtin name customised", "", "dark",
+			map[string]config.ThemeConfig{"dark": {"border": "240"}}, config.ColorsConfig{},
+			func(th Theme) string { return th.Border }, "240"},
+		{"colors override thresholds", "", "dark", nil, config.ColorsConfig{Critical: "196"},
+			func(th Theme) string { return th.Error }, "196"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			cfg := config.Default()
+			cfg.UI.Theme = tt.theme
+			cfg.Themes = tt.custom
+			cfg.Colors = tt.colors
+
+			if got := tt.check(resolveTheme(cfg)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
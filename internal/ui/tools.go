@@ -0,0 +1,339 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/thieso2/promptwatch/internal/diff"
+)
+
+// toolSummary returns a one-line description of a tool call for headers
+func toolSummary(toolName, toolInput string) string {
+	var input map[string]interface{}
+	if json.Unmarshal([]byte(toolInput), &input) == nil {
+		for _, field := range []string{"file_path", "description", "command", "pattern", "url"} {
+			if value := stringField(input, field); value != "" {
+				return strings.Join(strings.Fields(value), " ")
+			}
+		}
+	}
+	return ansi.Truncate(toolInput, 80, "…")
+}
+
+// stringField returns a string value of a decoded JSON object, or ""
+func stringField(input map[string]interface{}, field string) string {
+	value, _ := input[field].(string)
+	return value
+}
+
+// renderFileHeader renders the path line above file contents and diffs
+func (m *Model) renderFileHeader(path, note string) string {
+	header := m.styles.Highlight.Render("📄 " + path)
+	if note != "" {
+		header += " " + m.styles.Muted.Render(note)
+	}
+	return header
+}
+
+// renderEditInput shows an Edit call as a unified diff of old_string to new_string
+func (m *Model) renderEditInput(input map[string]interface{}, width int) []string {
+	var note string
+	if replaceAll, _ := input["replace_all"].(bool); replaceAll {
+		note = "(all occurrences)"
+	}
+	lines := []string{m.renderFileHeader(stringField(input, "file_path"), note), ""}
+	return append(lines, m.renderDiff(stringField(input, "old_string"), stringField(input, "new_string"), width)...)
+}
+
+// renderMultiEditInput shows each edit of a MultiEdit call as its own diff
+func (m *Model) renderMultiEditInput(input map[string]interface{}, width int) []string {
+	edits, _ := input["edits"].([]interface{})
+	lines := []string{m.renderFileHeader(stringField(input, "file_path"), fmt.Sprintf("(%d edits)", len(edits)))}
+
+	for i, e := range edits {
+		edit, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		title := fmt.Sprintf("Edit %d/%d", i+1, len(edits))
+		if replaceAll, _ := edit["replace_all"].(bool); replaceAll {
+			title += " (all occurrences)"
+		}
+		lines = append(lines, "", m.styles.Subtle.Render(title))
+		lines = append(lines, m.renderDiff(stringField(edit, "old_string"), stringField(edit, "new_string"), width)...)
+	}
+	return lines
+}
+
+// renderDiff renders a coloured unified diff of two snippets
+func (m *Model) renderDiff(oldText, newText string, width int) []string {
+	hunks := diff.Hunks(diff.Lines(diff.SplitLines(oldText), diff.SplitLines(newText)), 3)
+	if len(hunks) == 0 {
+		return []string{m.styles.Muted.Render("(no changes)")}
+	}
+
+	var lines []string
+	for _, h := range hunks {
+		lines = append(lines, m.styles.Info.Render(h.Header()))
+		for _, l := range h.Lines {
+			text := ansi.Truncate(expandTabs(l.Op.Prefix()+l.Text), width, "…")
+			switch l.Op {
+			case diff.Delete:
+				lines = append(lines, m.styles.Error.Render(text))
+			case diff.Insert:
+				lines = append(lines, m.styles.OK.Render(text))
+			default:
+				lines = append(lines, m.styles.Faint.Render(text))
+			}
+		}
+	}
+	return lines
+}
+
+// renderWriteInput shows a Write call as the highlighted file content with line numbers
+func (m *Model) renderWriteInput(input map[string]interface{}, width int) []string {
+	path := stringField(input, "file_path")
+	content := expandTabs(stringField(input, "content"))
+	code := highlightCode(content, path, m.theme.Syntax)
+
+	lines := []string{m.renderFileHeader(path, fmt.Sprintf("(%d lines)", len(code))), ""}
+	gutter := len(fmt.Sprint(len(code)))
+	for i, line := range code {
+		number := m.styles.Muted.Render(fmt.Sprintf("%*d │ ", gutter, i+1))
+		lines = append(lines, ansi.Truncate(number+line, width, "…"))
+	}
+	return lines
+}
+
+// renderBashInput shows a Bash call as its description and highlighted command
+func (m *Model) renderBashInput(input map[string]interface{}, width int) []string {
+	var lines []string
+	if description := stringField(input, "description"); description != "" {
+		lines = append(lines, m.styles.Subtle.Render(description), "")
+	}
+
+	for i, line := range highlightLanguage(stringField(input, "command"), "bash", m.theme.Syntax) {
+		prompt := "  "
+		if i == 0 {
+			prompt = "$ "
+		}
+		lines = append(lines, ansi.Truncate(m.styles.Accent.Render(prompt)+line, width, "…"))
+	}
+
+	var flags []string
+	if timeout, ok := input["timeout"].(float64); ok {
+		flags = append(flags, fmt.Sprintf("timeout %gs", timeout/1000))
+	}
+	if background, _ := input["run_in_background"].(bool); background {
+		flags = append(flags, "in background")
+	}
+	if len(flags) > 0 {
+		lines = append(lines, "", m.styles.Muted.Render(strings.Join(flags, " · ")))
+	}
+	return lines
+}
+
+// renderBashResult shows command output with its indentation intact
+func (m *Model) renderBashResult(_ map[string]interface{}, result string, width int) []string {
+	var lines []string
+	for _, line := range wrapLines(result, width) {
+		lines = append(lines, m.styles.Faint.Render(line))
+	}
+	return lines
+}
+
+// renderReadInput shows the file and line range a Read call requested
+func (m *Model) renderReadInput(input map[string]interface{}, _ int) []string {
+	var note string
+	offset, hasOffset := input["offset"].(float64)
+	limit, hasLimit := input["limit"].(float64)
+	switch {
+	case hasOffset && hasLimit:
+		note = fmt.Sprintf("(lines %g-%g)", offset, offset+limit-1)
+	case hasOffset:
+		note = fmt.Sprintf("(from line %g)", offset)
+	case hasLimit:
+		note = fmt.Sprintf("(first %g lines)", limit)
+	}
+	return []string{m.renderFileHeader(stringField(input, "file_path"), note)}
+}
+
+// numberedLine matches the "cat -n" lines of Read results, e.g. "    12→code"
+var numberedLine = regexp.MustCompile(`^\s*(\d+)[→\t](.*)$`)
+
+// renderReadResult highlights the returned file content by extension,
+// keeping the line numbers in a gutter
+func (m *Model) renderReadResult(input map[string]interface{}, result string, width int) []string {
+	var numbers, code []string
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(result, "\n"), "\n") {
+		if match := numberedLine.FindStringSubmatch(line); match != nil {
+			numbers = append(numbers, match[1])
+			code = append(code, expandTabs(match[2]))
+		}
+	}
+	if len(code) == 0 {
+		return m.renderPlainResult(result, width)
+	}
+
+	highlighted := highlightCode(strings.Join(code, "\n"), stringField(input, "file_path"), m.theme.Syntax)
+	gutter := len(numbers[len(numbers)-1])
+	for i, line := range highlighted {
+		if i >= len(numbers) {
+			break
+		}
+		number := m.styles.Muted.Render(fmt.Sprintf("%*s │ ", gutter, numbers[i]))
+		lines = append(lines, ansi.Truncate(number+line, width, "…"))
+	}
+	return lines
+}
+
+// renderGrepInput shows the search pattern and its options
+func (m *Model) renderGrepInput(input map[string]interface{}, width int) []string {
+	lines := []string{m.styles.Highlight.Render("🔎 " + stringField(input, "pattern"))}
+
+	var options []string
+	for _, field := range []string{"path", "glob", "type", "output_mode"} {
+		if value := stringField(input, field); value != "" {
+			options = append(options, field+": "+value)
+		}
+	}
+	for _, flag := range []string{"-i", "-n", "multiline"} {
+		if set, _ := input[flag].(bool); set {
+			options = append(options, flag)
+		}
+	}
+	for _, field := range []string{"-A", "-B", "-C", "head_limit"} {
+		if value, ok := input[field].(float64); ok {
+			options = append(options, fmt.Sprintf("%s: %g", field, value))
+		}
+	}
+	for _, line := range wrapWords(strings.Join(options, " · "), width) {
+		if line != "" {
+			lines = append(lines, m.styles.Subtle.Render(line))
+		}
+	}
+	return lines
+}
+
+// grepMatch matches content-mode Grep lines, e.g. "src/a.go:12:text"
+var grepMatch = regexp.MustCompile(`^(.+?)([:-])(\d+)[:-](.*)$`)
+
+// renderGrepResult highlights file names and line numbers of matches
+func (m *Model) renderGrepResult(_ map[string]interface{}, result string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(result, "\n"), "\n") {
+		line = expandTabs(line)
+		switch match := grepMatch.FindStringSubmatch(line); {
+		case match != nil:
+			styled := m.styles.Highlight.Render(match[1]) + m.styles.Muted.Render(match[2]+match[3]+match[2]) + match[4]
+			lines = append(lines, ansi.Truncate(styled, width, "…"))
+		case strings.HasPrefix(line, "Found "):
+			lines = append(lines, m.styles.Subtle.Render(line))
+		default:
+			lines = append(lines, ansi.Truncate(m.styles.Highlight.Render(line), width, "…"))
+		}
+	}
+	return lines
+}
+
+// renderGlobInput shows the glob pattern and the directory searched
+func (m *Model) renderGlobInput(input map[string]interface{}, _ int) []string {
+	line := m.styles.Highlight.Render("🔎 " + stringField(input, "pattern"))
+	if dir := stringField(input, "path"); dir != "" {
+		line += " " + m.styles.Muted.Render("in "+dir)
+	}
+	return []string{line}
+}
+
+// renderGlobResult lists the matched files with their directories dimmed.
+// The result has one path per line, and a note in parentheses when the list
+// was cut short.
+func (m *Model) renderGlobResult(_ map[string]interface{}, result string, width int) []string {
+	result = strings.TrimSpace(result)
+	if result == "" || result == "No files found" {
+		return []string{m.styles.Subtle.Render("No files found")}
+	}
+
+	var files, notes []string
+	for _, line := range strings.Split(result, "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "(") && strings.HasSuffix(line, ")"):
+			notes = append(notes, line)
+		default:
+			files = append(files, line)
+		}
+	}
+	lines := []string{m.styles.Subtle.Render(fmt.Sprintf("%d files", len(files)))}
+	for _, file := range files {
+		dir, base := filepath.Split(file)
+		lines = append(lines, ansi.Truncate(m.styles.Muted.Render(dir)+m.styles.Highlight.Render(base), width, "…"))
+	}
+	for _, note := range notes {
+		lines = append(lines, m.styles.Subtle.Render(ansi.Truncate(note, width, "…")))
+	}
+	return lines
+}
+
+// renderWebFetchInput shows the URL and the prompt applied to the page
+func (m *Model) renderWebFetchInput(input map[string]interface{}, width int) []string {
+	lines := []string{m.styles.Highlight.Render("🌐 " + ansi.Truncate(stringField(input, "url"), width-3, "…"))}
+	if prompt := stringField(input, "prompt"); prompt != "" {
+		lines = append(lines, "")
+		for _, line := range wrapWords(prompt, width) {
+			lines = append(lines, m.styles.Subtle.Render(line))
+		}
+	}
+	return lines
+}
+
+// renderWebFetchResult renders the fetched summary as markdown
+func (m *Model) renderWebFetchResult(_ map[string]interface{}, result string, width int) []string {
+	rendered, err := renderMarkdown(result, width, m.theme.Markdown)
+	if err != nil {
+		return m.renderPlainResult(result, width)
+	}
+	return strings.Split(rendered, "\n")
+}
+
+// renderTodoWriteInput shows the todo list as a checklist
+func (m *Model) renderTodoWriteInput(input map[string]interface{}, width int) []string {
+	todos, _ := input["todos"].([]interface{})
+	var done int
+	var items []string
+	for _, t := range todos {
+		todo, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		content := stringField(todo, "content")
+		switch stringField(todo, "status") {
+		case "completed":
+			done++
+			items = append(items, m.styles.OK.Render("✔ ")+m.styles.Muted.Render(content))
+		case "in_progress":
+			if active := stringField(todo, "activeForm"); active != "" {
+				content = active
+			}
+			items = append(items, m.styles.Warning.Render("▶ ")+m.styles.Text.Bold(true).Render(content))
+		default:
+			items = append(items, m.styles.Muted.Render("○ ")+m.styles.Text.Render(content))
+		}
+	}
+
+	lines := []string{m.styles.Subtle.Render(fmt.Sprintf("%d of %d done", done, len(todos)))}
+	for _, item := range items {
+		lines = append(lines, ansi.Truncate(item, width, "…"))
+	}
+	return lines
+}
+
+// expandTabs replaces tabs so truncation and alignment see real widths
+func expandTabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "    ")
+}
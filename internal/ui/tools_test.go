@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/thieso2/promptwatch/internal/config"
+)
+
+// TestRenderToolInput tests the tool-aware layouts of tool call arguments
+func TestRenderToolInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		tool     string
+		input    string
+		want     []string
+		notWant  []string
+		maxWidth int
+	}{
+		{
+			name:  "edit as diff",
+			tool:  "Edit",
+			input: `{"file_path":"/src/main.go","old_string":"a := 1\nb := 2","new_string":"a := 1\nb := 3"}`,
+			want:  []string{"📄 /src/main.go", "@@ -1,2 +1,2 @@", " a := 1", "-b := 2", "+b := 3"},
+		},
+		{
+			name: "multi edit per edit",
+			tool: "MultiEdit",
+			input: `{"file_path":"/src/x.go","edits":[{"old_string":"x","new_string":"y"},` +
+				`{"old_string":"p","new_string":"q","replace_all":true}]}`,
+			want: []string{"(2 edits)", "Edit 1/2", "-x", "+y", "Edit 2/2 (all occurrences)", "-p", "+q"},
+		},
+		{
+			name:  "write with line numbers",
+			tool:  "Write",
+			input: `{"file_path":"/src/hello.py","content":"def hello():\n    print(\"hi\")\n"}`,
+			want:  []string{"📄 /src/hello.py (2 lines)", "1 │ def hello():", "2 │     print(\"hi\")"},
+		},
+		{
+			name:    "bash command and description",
+			tool:    "Bash",
+			input:   `{"command":"go test ./...","description":"Run tests","timeout":120000}`,
+			want:    []string{"Run tests", "$ go test ./...", "timeout 120s"},
+			notWant: []string{"in background"},
+		},
+		{
+			name:  "grep pattern and options",
+			tool:  "Grep",
+			input: `{"pattern":"TODO","glob":"*.go","-i":true,"-C":2}`,
+			want:  []string{"🔎 TODO", "glob: *.go · -i · -C: 2"},
+		},
+		{
+			name:  "todo checklist",
+			tool:  "TodoWrite",
+			input: `{"todos":[{"content":"Write tests","status":"completed"},{"content":"Fix bug","activeForm":"Fixing bug","status":"in_progress"},{"content":"Ship","status":"pending"}]}`,
+			want:  []string{"1 of 3 done", "✔ Write tests", "▶ Fixing bug", "○ Ship"},
+		},
+		{
+			name:  "unknown tool as indented JSON",
+			tool:  "mcp__jira__search",
+			input: `{"pattern":"TODO"}`,
+			want:  []string{"Arguments:", `  "pattern": "TODO"`},
+		},
+		{
+			name:  "invalid JSON as arguments",
+			tool:  "Edit",
+			input: `not json`,
+			want:  []string{"Arguments:", "not json"},
+		},
+		{
+			name:     "long lines truncated",
+			tool:     "Write",
+			input:    `{"file_path":"a.txt","content":"` + strings.Repeat("x", 100) + `"}`,
+			want:     []string{"…"},
+			maxWidth: 40,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "1")
+			m := NewModel(config.Default(), false)
+
+			width := 80
+			if tt.maxWidth > 0 {
+				width = tt.maxWidth
+			}
+			lines := m.renderToolInput(tt.tool, tt.input, width)
+			out := ansi.Strip(strings.Join(lines, "\n"))
+
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("Output does not contain %q:\n%s", want, out)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(out, notWant) {
+					t.Errorf("Output contains %q:\n%s", notWant, out)
+				}
+			}
+			for _, line := range lines {
+				if w := ansi.StringWidth(line); w > width {
+					t.Errorf("Line wider than %d columns (%d): %q", width, w, line)
+				}
+			}
+		})
+	}
+}
+
+// TestHighlightCode tests that highlighting keeps one entry per source line
+func TestHighlightCode(t *testing.T) {
+	code := "package main\n\n/* a\n   b */\nfunc main() {}\n"
+	lines := highlightCode(code, "main.go", "monokai")
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 lines, got %d: %q", len(lines), lines)
+	}
+	if got := ansi.Strip(lines[3]); got != "   b */" {
+		t.Errorf("Line 4: got %q, want %q", got, "   b */")
+	}
+	if lines[0] == ansi.Strip(lines[0]) {
+		t.Errorf("Expected highlighted output, got plain %q", lines[0])
+	}
+}
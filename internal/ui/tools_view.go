@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/evertras/bubble-table/table"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// toolCallsMsg carries the tool calls of the sessions a tools view covers
+type toolCallsMsg struct {
+	calls []monitor.ToolCall
+	err   error
+}
+
+// toolRanges lists the date ranges the tools view cycles through
+var toolRanges = []struct {
+	Label  string
+	Period time.Duration // 0 = all time
+}{
+	{"all time", 0},
+	{"last 24 hours", 24 * time.Hour},
+	{"last 7 days", 7 * 24 * time.Hour},
+	{"last 30 days", 30 * 24 * time.Hour},
+}
+
+// toolColumns lists the tools table columns
+var toolColumns = []columnSpec{
+	{Key: "tool", Title: "TOOL", Width: 24, Flex: 1},
+	{Key: "calls", Title: "CALLS", Width: 8},
+	{Key: "errors", Title: "ERR%", Width: 7},
+	{Key: "p50", Title: "P50", Width: 9},
+	{Key: "p95", Title: "P95", Width: 9},
+	{Key: "input", Title: "AVG INPUT", Width: 11},
+	{Key: "result", Title: "AVG RESULT", Width: 12},
+}
+
+// createToolsTableWithWidth creates the tools table sized for the width
+func createToolsTableWithWidth(width int) table.Model {
+	return newTable(layoutColumns(toolColumns, width-6))
+}
+
+// openTools switches to the tool usage of the listed sessions, or of every
+// session when opened from the process or project view
+func (m *Model) openTools() tea.Cmd {
+	m.toolsSourceMode = m.viewMode
+	m.viewMode = ViewTools
+	m.toolCalls = nil
+	m.toolsError = ""
+	m.toolsModelIdx = 0
+	m.selectedToolIdx = 0
+
+	var paths []string
+	if m.toolsSourceMode == ViewSessions {
+		m.toolsTitle = fmt.Sprintf("Tool usage in %d sessions", len(m.sessions))
+		for _, s := range m.sessions {
+			paths = append(paths, s.Path)
+		}
+	} else {
+		m.toolsTitle = "Tool usage in all projects"
+	}
+
+	return func() tea.Msg {
+		if paths == nil {
+			paths = monitor.AllSessionFiles(time.Time{})
+		}
+		calls, err := monitor.LoadToolCalls(paths)
+		return toolCallsMsg{calls: calls, err: err}
+	}
+}
+
+// toolModels returns the model filter choices: "" for all models, then each model seen
+func (m Model) toolModels() []string {
+	return append([]string{""}, monitor.Models(m.toolCalls)...)
+}
+
+// toolFilter returns the filter selected in the tools view
+func (m Model) toolFilter() monitor.ToolFilter {
+	var filter monitor.ToolFilter
+	if models := m.toolModels(); m.toolsModelIdx < len(models) {
+		filter.Model = models[m.toolsModelIdx]
+	}
+	if period := toolRanges[m.toolsRangeIdx].Period; period > 0 {
+		filter.Since = time.Now().Add(-period)
+	}
+	return filter
+}
+
+// toolRows returns the rows of the tools view
+func (m Model) toolRows() []monitor.ToolStats {
+	return monitor.AggregateToolCalls(m.toolCalls, m.toolFilter()).Grouped(monitor.ToolSortKeys[m.toolsSort])
+}
+
+// updateToolsTable refills the tools table
+func (m *Model) updateToolsTable() {
+	tools := m.toolRows()
+	rows := make([]table.Row, len(tools))
+	for i, tool := range tools {
+		name := tool.Name
+		nameStyle := m.styles.Text
+		switch {
+		case tool.ServerTotal:
+			name = "mcp: " + tool.Server
+			nameStyle = m.styles.Accent
+		case tool.Server != "":
+			name = "  └ " + tool.Name
+		}
+
+		errStyle := m.styles.Text
+		switch {
+		case tool.ErrorRate() >= 20:
+			errStyle = m.styles.Error
+		case tool.Errors > 0:
+			errStyle = m.styles.Warning
+		}
+
+		rows[i] = table.NewRow(table.RowData{
+			"tool":   table.NewStyledCell(truncatePath(name, 60), nameStyle),
+			"calls":  fmt.Sprintf("%d", tool.Calls),
+			"errors": table.NewStyledCell(fmt.Sprintf("%.1f", tool.ErrorRate()), errStyle),
+			"p50":    monitor.FormatLatency(tool.MedianLatency),
+			"p95":    monitor.FormatLatency(tool.P95Latency),
+			"input":  monitor.FormatSize(tool.AvgInputSize),
+			"result": monitor.FormatSize(tool.AvgResultSize),
+		})
+	}
+
+	m.selectedToolIdx = clampIndex(m.selectedToolIdx, len(tools))
+	m.toolsTable = m.toolsTable.WithRows(rows).WithHighlightedRow(m.selectedToolIdx)
+}
+
+// renderToolsView displays tool usage statistics
+func (m Model) renderToolsView() string {
+	filter := m.toolFilter()
+	model := filter.Model
+	if model == "" {
+		model = "all models"
+	}
+	summary := fmt.Sprintf("%s · %s · by %s", model, toolRanges[m.toolsRangeIdx].Label, monitor.ToolSortKeys[m.toolsSort])
+
+	var content string
+	switch {
+	case m.toolsError != "":
+		content = m.styles.Error.Render("Error: " + m.toolsError)
+	case m.toolCalls == nil:
+		content = m.styles.Muted.Render("Reading sessions…")
+	default:
+		report := monitor.AggregateToolCalls(m.toolCalls, filter)
+		if report.Calls == 0 {
+			content = m.styles.Muted.Render("No tool calls match")
+			break
+		}
+		summary = fmt.Sprintf("%d calls · %d tools · %s", report.Calls, len(report.Tools), summary)
+		content = m.toolsTable.View()
+	}
+
+	header := lipgloss.JoinVertical(lipgloss.Left, m.styles.Title.Render(m.toolsTitle), m.styles.Muted.Render(summary))
+	footer := m.styles.Muted.Render(formatHints(m.keys.ShortHelp(m.viewMode)))
+	return lipgloss.JoinVertical(lipgloss.Left, header, "", content, "", footer)
+}
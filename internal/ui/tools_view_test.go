@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// TestToolsView tests tool usage rows, MCP grouping and the model and date filters
+func TestToolsView(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var model tea.Model = NewModel(config.Default(), false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	m := model.(Model)
+	m.viewMode = ViewTools
+	m.toolsSourceMode = ViewSessions
+	now := time.Now()
+	model, _ = m.Update(toolCallsMsg{calls: []monitor.ToolCall{
+		{Name: "Bash", Model: "claude-opus-4", Time: now, HasResult: true, Latency: 2 * time.Second},
+		{Name: "Bash", Model: "claude-opus-4", Time: now, HasResult: true, IsError: true},
+		{Name: "mcp__github__get_pr", Model: "claude-sonnet-4", Time: now.AddDate(0, 0, -3), HasResult: true},
+	}})
+
+	view := model.(Model).View()
+	for _, want := range []string{"3 calls · 2 tools", "Bash", "50.0", "2.0s", "mcp: github", "└ get_pr"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View missing %q:\n%s", want, view)
+		}
+	}
+
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"M", "2 calls · 1 tools · claude-opus-4"},
+		{"M", "1 calls · 1 tools · claude-sonnet-4"},
+		{"M", "3 calls · 2 tools · all models"},
+		{"R", "2 calls · 1 tools · all models · last 24 hours"},
+		{"R", "3 calls · 2 tools · all models · last 7 days"},
+	}
+	for _, tt := range tests {
+		model, _ = model.Update(keyPress(tt.key))
+		if view := model.(Model).View(); !strings.Contains(view, tt.expected) {
+			t.Errorf("After %s: view missing %q:\n%s", tt.key, tt.expected, view)
+		}
+	}
+
+	model, _ = model.Update(keyPress("esc"))
+	if model.(Model).viewMode != ViewSessions {
+		t.Errorf("Back returns to %v, want sessions", model.(Model).viewMode)
+	}
+}
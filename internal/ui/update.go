@@ -2,37 +2,117 @@ package ui
 
 import (
 	"fmt"
-	"sort"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/evertras/bubble-table/table"
+	"github.com/thieso2/promptwatch/internal/filter"
 	"github.com/thieso2/promptwatch/internal/monitor"
 )
 
-// Cost constants based on Claude API pricing
-const (
-	InputTokenCost         = 3.0 / 1_000_000  // $3 per 1M input tokens
-	CacheCreationTokenCost = 3.0 / 1_000_000  // $3 per 1M cache creation tokens
-	CacheReadTokenCost     = 0.30 / 1_000_000 // $0.30 per 1M cache read tokens
-	OutputTokenCost        = 15.0 / 1_000_000 // $15 per 1M output tokens
-)
-
-// Update handles incoming messages and updates the model
+// Update handles incoming messages and updates the model, then counts the
+// commits of session rows that came into view
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	next, ok := model.(Model)
+	if !ok {
+		return model, cmd
+	}
+	if count := next.countVisibleCommits(); count != nil {
+		return next, tea.Batch(cmd, count)
+	}
+	return next, cmd
+}
+
+// update handles a message
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c":
+		m.statusMessage = ""
+
+		// ctrl+c always quits, whatever the keymap says
+		if msg.Type == tea.KeyCtrlC {
+			m.quitting = true
+			return m, tea.Quit
+		}
+
+		// The open filter bar takes every other key
+		if m.filterEditing {
+			return m.updateFilterBar(msg)
+		}
+
+		if key.Matches(msg, m.keys.Quit) {
 			m.quitting = true
 			return m, tea.Quit
-		case "esc":
+		}
+
+		// Any key closes the help overlay
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+		if m.showViews {
+			return m.updateViewPicker(msg)
+		}
+		if m.editingColumns {
+			return m.updateColumnEditor(msg)
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.Help):
+			m.showHelp = true
+			return m, nil
+		case key.Matches(msg, m.keys.Back):
 			// Go back to previous view
-			if m.viewMode == ViewMessageDetail {
+			if m.viewMode == ViewCompare {
+				m.viewMode = ViewSessions
+				m.compareProfiles = nil
+				m.compareLines = nil
+				return m, nil
+			} else if m.viewMode == ViewSessionDiff {
+				m.viewMode = ViewSessions
+				m.sessionDiffs = nil
+				m.sessionDiffLines = nil
+				return m, nil
+			} else if m.viewMode == ViewTools {
+				m.viewMode = m.toolsSourceMode
+				m.toolCalls = nil
+				m.toolsError = ""
+				return m, nil
+			} else if m.viewMode == ViewUsageHistory {
+				m.viewMode = m.usageSourceMode
+				m.usageSamples = nil
+				m.usageError = ""
+				return m, nil
+			} else if m.viewMode == ViewCommits {
+				m.viewMode = ViewSessionDetail
+				m.commits = nil
+				m.commitLines = nil
+				return m, nil
+			} else if m.viewMode == ViewCheckpointContent {
+				m.viewMode = ViewCheckpoints
+				m.historyLines = nil
+				return m, nil
+			} else if m.viewMode == ViewCheckpoints {
+				m.viewMode = ViewFileHistory
+				return m, nil
+			} else if m.viewMode == ViewFileHistory {
+				m.viewMode = ViewSessionDetail
+				m.fileHistory = nil
+				return m, nil
+			} else if m.viewMode == ViewFiles {
+				m.viewMode = m.filesSourceMode
+				m.fileReport = nil
+				m.filesError = ""
+				return m, nil
+			} else if m.viewMode == ViewMessageDetail {
 				m.viewMode = ViewSessionDetail
 				m.detailMessage = nil
 				m.detailScrollOffset = 0
+				m.layoutDetail()
 				return m, nil
 			} else if m.viewMode == ViewSessionDetail {
 				m.viewMode = ViewSessions
@@ -51,97 +131,169 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.selectedProc = nil
 				m.sessions = nil
+				m.allSessions = nil
+				m.sessionFilter = nil
 				m.sessionError = ""
 				m.selectedSessionIdx = 0
 				return m, nil
 			}
-		case "r":
-			// Manual refresh (only in process view)
-			if m.viewMode == ViewProcesses {
-				return m, m.refreshProcesses()
-			}
-		case "f":
-			// Toggle helpers filter (only in process view)
-			if m.viewMode == ViewProcesses {
-				m.showHelpers = !m.showHelpers
-				return m, m.refreshProcesses()
-			}
-		case "p":
-			// Toggle between processes and projects view
-			if m.viewMode == ViewProcesses {
-				m.viewMode = ViewProjects
-				m.selectedProjIdx = 0
-				return m, m.loadProjects()
-			} else if m.viewMode == ViewProjects {
-				m.viewMode = ViewProcesses
-				m.selectedProcIdx = 0
-				return m, m.refreshProcesses()
-			}
-		case "u":
-			// Filter to user messages only (in session detail view)
-			if m.viewMode == ViewSessionDetail {
-				m.messageFilter = FilterUserOnly
-				m.updateMessageTable()
-				if m.filteredMessageCount == 0 {
-					m.messageError = "No user prompts found in this session"
-				} else {
-					m.messageError = fmt.Sprintf("Showing %d user prompts", m.filteredMessageCount)
-				}
-				return m, nil
+		case key.Matches(msg, m.keys.Refresh) && m.viewMode == ViewProcesses:
+			// Manual refresh
+			return m, m.refreshProcesses()
+		case key.Matches(msg, m.keys.ToggleHelpers) && m.viewMode == ViewProcesses:
+			// Toggle helpers filter
+			m.showHelpers = !m.showHelpers
+			return m, m.refreshProcesses()
+		case key.Matches(msg, m.keys.ToggleProjects) && m.viewMode == ViewProcesses:
+			m.viewMode = ViewProjects
+			m.selectedProjIdx = 0
+			return m, m.loadProjects()
+		case key.Matches(msg, m.keys.ToggleProjects) && m.viewMode == ViewProjects:
+			m.viewMode = ViewProcesses
+			m.selectedProcIdx = 0
+			return m, m.refreshProcesses()
+		case key.Matches(msg, m.keys.Views) && (m.viewMode == ViewProcesses || m.viewMode == ViewProjects ||
+			m.viewMode == ViewSessions || m.viewMode == ViewSessionDetail):
+			m.openViewPicker()
+			return m, nil
+		case key.Matches(msg, m.keys.Filter) && (m.viewMode == ViewSessions || m.viewMode == ViewSessionDetail):
+			m.openFilterBar()
+			return m, nil
+		case key.Matches(msg, m.keys.FilterUser) && m.viewMode == ViewSessionDetail:
+			// Filter to user messages only
+			m.messageFilter, _ = filter.ParseMessages("role:user")
+			m.updateMessageTable()
+			if m.filteredMessageCount == 0 {
+				m.messageError = "No user prompts found in this session"
+			} else {
+				m.messageError = fmt.Sprintf("Showing %d user prompts", m.filteredMessageCount)
 			}
-		case "a":
-			// Filter to assistant messages only (in session detail view)
-			if m.viewMode == ViewSessionDetail {
-				m.messageFilter = FilterAssistantOnly
-				m.updateMessageTable()
-				if m.filteredMessageCount == 0 {
-					m.messageError = "No Claude responses found in this session"
-				} else {
-					m.messageError = fmt.Sprintf("Showing %d Claude responses", m.filteredMessageCount)
-				}
-				return m, nil
+			return m, nil
+		case key.Matches(msg, m.keys.FilterAssistant) && m.viewMode == ViewSessionDetail:
+			// Filter to assistant messages only
+			m.messageFilter, _ = filter.ParseMessages("role:assistant,tool")
+			m.updateMessageTable()
+			if m.filteredMessageCount == 0 {
+				m.messageError = "No Claude responses found in this session"
+			} else {
+				m.messageError = fmt.Sprintf("Showing %d Claude responses", m.filteredMessageCount)
 			}
-		case "b":
+			return m, nil
+		case key.Matches(msg, m.keys.FilterAll) && m.viewMode == ViewSessionDetail:
 			// Show both (all messages)
-			if m.viewMode == ViewSessionDetail {
-				m.messageFilter = FilterAll
-				m.updateMessageTable()
-				if m.filteredMessageCount == 0 {
-					m.messageError = "No messages found in this session"
-				} else {
-					m.messageError = fmt.Sprintf("Showing all %d messages", m.filteredMessageCount)
-				}
-				return m, nil
+			m.messageFilter = nil
+			m.updateMessageTable()
+			if m.filteredMessageCount == 0 {
+				m.messageError = "No messages found in this session"
+			} else {
+				m.messageError = fmt.Sprintf("Showing all %d messages", m.filteredMessageCount)
 			}
-		case "s":
-			// Toggle sort order (newest/oldest first)
-			if m.viewMode == ViewSessionDetail {
-				m.messageSortNewestFirst = !m.messageSortNewestFirst
-				m.updateMessageTable()
-				sortOrder := "oldest first"
-				if m.messageSortNewestFirst {
-					sortOrder = "newest first"
-				}
-				m.messageError = fmt.Sprintf("Sorting %s", sortOrder)
-				return m, nil
+			return m, nil
+		case key.Matches(msg, m.keys.Sort) && tableOf(m.viewMode) != "":
+			// Sort by the next column in its natural direction
+			m.cycleSort(tableOf(m.viewMode))
+			m.applySort(tableOf(m.viewMode))
+			return m, nil
+		case key.Matches(msg, m.keys.ReverseSort) && tableOf(m.viewMode) != "":
+			sorted := m.sortOf(tableOf(m.viewMode))
+			sorted.Desc = !sorted.Desc
+			m.applySort(tableOf(m.viewMode))
+			return m, nil
+		case key.Matches(msg, m.keys.Columns) && (m.viewMode == ViewProcesses || m.viewMode == ViewProjects || m.viewMode == ViewSessions):
+			m.openColumnEditor()
+			return m, nil
+		case key.Matches(msg, m.keys.FileActivity) && (m.viewMode == ViewSessions || m.viewMode == ViewSessionDetail):
+			return m, m.openFileActivity()
+		case key.Matches(msg, m.keys.FileHistory) && m.viewMode == ViewSessionDetail:
+			m.openFileHistory()
+			return m, nil
+		case key.Matches(msg, m.keys.Tools) && (m.viewMode == ViewProcesses || m.viewMode == ViewProjects || m.viewMode == ViewSessions):
+			return m, m.openTools()
+		case key.Matches(msg, m.keys.UsageHistory) && (m.viewMode == ViewProcesses || m.viewMode == ViewProjects):
+			return m, m.openUsageHistory()
+		case key.Matches(msg, m.keys.Refresh) && m.viewMode == ViewUsageHistory:
+			return m, m.loadUsageSamples()
+		case key.Matches(msg, m.keys.FilterRange) && m.viewMode == ViewUsageHistory:
+			m.usageRangeIdx = (m.usageRangeIdx + 1) % len(usageRanges)
+			m.selectedUsageIdx = 0
+			m.updateUsageTable()
+			return m, nil
+		case key.Matches(msg, m.keys.Sort) && m.viewMode == ViewTools:
+			m.toolsSort = (m.toolsSort + 1) % len(monitor.ToolSortKeys)
+			m.updateToolsTable()
+			return m, nil
+		case key.Matches(msg, m.keys.FilterModel) && m.viewMode == ViewTools:
+			m.toolsModelIdx = (m.toolsModelIdx + 1) % len(m.toolModels())
+			m.selectedToolIdx = 0
+			m.updateToolsTable()
+			return m, nil
+		case key.Matches(msg, m.keys.FilterRange) && m.viewMode == ViewTools:
+			m.toolsRangeIdx = (m.toolsRangeIdx + 1) % len(toolRanges)
+			m.selectedToolIdx = 0
+			m.updateToolsTable()
+			return m, nil
+		case key.Matches(msg, m.keys.Mark) && m.viewMode == ViewSessions:
+			m.toggleMark()
+			return m, nil
+		case key.Matches(msg, m.keys.Compare) && m.viewMode == ViewSessions:
+			return m, m.openComparison()
+		case key.Matches(msg, m.keys.Diff) && m.viewMode == ViewSessions:
+			return m, m.openSessionDiff()
+		case key.Matches(msg, m.keys.Commits) && m.viewMode == ViewSessionDetail:
+			return m, m.openCommits()
+		case key.Matches(msg, m.keys.Open) && m.viewMode == ViewFileHistory:
+			m.openCheckpoints()
+			return m, nil
+		case key.Matches(msg, m.keys.Open) && m.viewMode == ViewCheckpoints:
+			m.showCheckpoint(m.selectedVersionIdx, false)
+			return m, nil
+		case key.Matches(msg, m.keys.Mark) && m.viewMode == ViewCheckpoints:
+			if m.markedVersionIdx == m.selectedVersionIdx {
+				m.markedVersionIdx = -1
+			} else {
+				m.markedVersionIdx = m.selectedVersionIdx
 			}
-		case "enter":
+			m.updateCheckpointsTable()
+			return m, nil
+		case key.Matches(msg, m.keys.Diff) && m.viewMode == ViewCheckpoints:
+			m.showCheckpoint(m.selectedVersionIdx, true)
+			return m, nil
+		case key.Matches(msg, m.keys.Diff) && m.viewMode == ViewCheckpointContent:
+			m.showCheckpoint(m.selectedVersionIdx, !m.historyDiff)
+			return m, nil
+		case key.Matches(msg, m.keys.Sort) && m.viewMode == ViewFiles:
+			m.filesSort = (m.filesSort + 1) % len(monitor.FileSortKeys)
+			m.selectedFileIdx = 0
+			m.updateFilesTable()
+			return m, nil
+		case key.Matches(msg, m.keys.GroupDirs) && m.viewMode == ViewFiles:
+			// Cycle files -> depth 1 -> 2 -> 3 -> files
+			m.filesDepth = (m.filesDepth + 1) % 4
+			m.selectedFileIdx = 0
+			m.updateFilesTable()
+			return m, nil
+		case key.Matches(msg, m.keys.Open):
 			// Open session view for selected process/project or session detail for selected session
 			if m.viewMode == ViewProcesses && len(m.processes) > 0 && m.selectedProcIdx >= 0 && m.selectedProcIdx < len(m.processes) {
 				m.selectedProc = &m.processes[m.selectedProcIdx]
 				m.viewMode = ViewSessions
 				m.sessionSourceMode = ViewProcesses
+				m.sessionsAll = false
+				m.sessionFilter = m.savedFilter("sessions")
 				m.selectedSessionIdx = 0 // Reset to first session
 				return m, m.loadSessions()
 			} else if m.viewMode == ViewProjects && len(m.projects) > 0 && m.selectedProjIdx >= 0 && m.selectedProjIdx < len(m.projects) {
 				// Load sessions for selected project
 				m.viewMode = ViewSessions
 				m.sessionSourceMode = ViewProjects
+				m.sessionsAll = false
+				m.sessionFilter = m.savedFilter("sessions")
 				m.selectedSessionIdx = 0 // Reset to first session
 				return m, m.loadSessionsFromProject(m.projects[m.selectedProjIdx])
 			} else if m.viewMode == ViewSessions && len(m.sessions) > 0 && m.selectedSessionIdx >= 0 && m.selectedSessionIdx < len(m.sessions) {
+				m.selectedSession = &m.sessions[m.selectedSessionIdx]
 				m.viewMode = ViewSessionDetail
-				m.messageFilter = FilterAll // Reset filter when opening new session
+				m.messageFilter = m.savedFilter("messages") // Reset filter when opening new session
 				return m, m.loadSessionDetail()
 			} else if m.viewMode == ViewSessionDetail {
 				// Open message detail view for selected message
@@ -153,16 +305,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.detailMessage = &filteredMessages[m.selectedMessageIdx]
 						m.viewMode = ViewMessageDetail
 						m.detailScrollOffset = 0
+						m.layoutDetail()
 						return m, nil
 					}
 				}
 			}
 		}
-		// Fall through to table handling for navigation and other keys
+		// Everything else is navigation within the current view
+		return m.navigate(msg)
 
 	case tickMsg:
-		// Periodic refresh (only in process view)
-		if m.viewMode == ViewProcesses {
+		// Periodic refresh (only in process view, or everywhere when events are announced)
+		if m.viewMode == ViewProcesses || m.notifier != nil || m.terminalNotify {
 			return m, tea.Batch(m.refreshProcesses(), m.tick())
 		} else {
 			return m, m.tick()
@@ -173,8 +327,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Error refreshing - log but continue
 		}
 		m.processes = msg.processes
+		m.alerts = msg.alerts
+		m.states = msg.states
 		m.lastUpdate = time.Now()
 		m.updateTable()
+		if n := len(msg.events); n > 0 {
+			last := msg.events[n-1]
+			m.statusMessage = last.Title() + " – " + last.Text
+			m.statusIsError = last.Kind == monitor.EventError || last.Kind == monitor.EventAlert
+		}
+		cmd := m.announce(msg.events)
+		return m, cmd
+
+	case clearNoticesMsg:
+		if msg.gen == m.noticesGen {
+			m.notices = ""
+		}
+		return m, nil
+
+	case notifyErrMsg:
+		m.statusMessage = "Notification failed: " + msg.err.Error()
+		m.statusIsError = true
 		return m, nil
 
 	case sessionsMsg:
@@ -182,7 +355,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.sessionError = msg.err.Error()
 		} else {
 			m.sessionError = ""
-			m.sessions = msg.sessions
+			m.allSessions = msg.sessions
 			m.updateSessionTable()
 		}
 		return m, nil
@@ -193,6 +366,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.messageError = ""
 			m.sessionStats = msg.stats
+			if stats, ok := msg.stats.(*monitor.SessionStats); ok {
+				m.contextUsage = monitor.SessionContext(stats)
+			}
 			m.selectedMessageIdx = 0    // Reset cursor to first message
 			m.lastMessageIdx = 0        // Reset scroll tracking
 			m.messageViewport.GotoTop() // Reset viewport scroll when loading new session
@@ -210,214 +386,369 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case toolCallsMsg:
+		if msg.err != nil {
+			m.toolsError = msg.err.Error()
+		} else {
+			m.toolsError = ""
+			m.toolCalls = append([]monitor.ToolCall{}, msg.calls...)
+			m.updateToolsTable()
+		}
+		return m, nil
+
+	case usageSamplesMsg:
+		if msg.err != nil {
+			m.usageError = msg.err.Error()
+		} else {
+			m.usageError = ""
+			m.usagePath = msg.path
+			m.usageSamples = append([]monitor.Sample{}, msg.samples...)
+			m.updateUsageTable()
+		}
+		return m, nil
+
+	case commitsMsg:
+		if msg.err != nil {
+			m.commitsError = msg.err.Error()
+		} else {
+			m.commitsError = ""
+			m.commits = append([]monitor.Commit{}, msg.commits...)
+			m.layoutCommits()
+		}
+		return m, nil
+
+	case commitCountsMsg:
+		for path := range msg.counts {
+			delete(m.commitsPending, path)
+		}
+		for i, session := range m.allSessions {
+			if count, ok := msg.counts[session.Path]; ok {
+				m.allSessions[i].Commits = count
+				m.allSessions[i].CommitsCounted = true
+			}
+		}
+		m.updateSessionTable()
+		return m, nil
+
+	case compareMsg:
+		if msg.err != nil {
+			m.compareError = msg.err.Error()
+		} else {
+			m.compareError = ""
+			m.compareProfiles = msg.profiles
+			m.layoutComparison()
+		}
+		return m, nil
+
+	case sessionDiffMsg:
+		if msg.err != nil {
+			m.sessionDiffError = msg.err.Error()
+		} else {
+			m.sessionDiffError = ""
+			m.sessionDiffs = msg.diffs
+			m.layoutSessionDiff()
+		}
+		return m, nil
+
+	case fileActivityMsg:
+		if msg.err != nil {
+			m.filesError = msg.err.Error()
+		} else {
+			m.filesError = ""
+			m.fileReport = &msg.report
+			m.updateFilesTable()
+		}
+		return m, nil
+
+	case ConfigReloadedMsg:
+		m.applyConfig(msg.Config)
+		m.statusMessage = "Config reloaded"
+		m.statusIsError = false
+		return m, nil
+
+	case ConfigErrorMsg:
+		// Keep running with the previous config
+		m.statusMessage = "Config not reloaded: " + msg.Err.Error()
+		m.statusIsError = true
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		// Handle terminal resize
 		m.termWidth = msg.Width
 		m.termHeight = msg.Height
-		// Recreate tables with new responsive widths
-		// Process table: header (1) + blank (1) + blank (1) + footer (1) = 4 lines
-		m.table = createTableWithWidth(msg.Width).WithPageSize(msg.Height - 6)
-		// Projects table: header (2 lines) + blank (2 lines) + blank (1) + footer (1) = 6+ lines
-		// Use aggressive reduction to prevent clipping
-		m.projectsTable = createProjectsTableWithWidth(msg.Width).WithPageSize(msg.Height - 10)
-		// Session table: header info (~2) + blank (1) + blank (1) + footer (1) = ~5 lines
-		m.sessionTable = createSessionTableWithWidth(msg.Width).WithPageSize(msg.Height - 8)
-		// Message table: header (1) + time (1) + tool info (1) + blank (1) + blank (1) + scroll (1) + footer (1) = 7
-		m.messageTable = createMessageTableWithWidth(msg.Width).WithPageSize(msg.Height - 9)
-		// Resize message viewport (header ~8 lines + footer ~1 line = 9 lines reserved)
+		// Resize message viewport (header ~9 lines + footer ~1 line = 10 lines reserved)
 		m.messageViewport.Width = msg.Width
-		m.messageViewport.Height = msg.Height - 9
-		// Rebuild tables with current data
-		m.updateTable()
-		m.updateProjectsTable()
-		m.updateSessionTable()
-		m.updateMessageTable()
+		m.messageViewport.Height = msg.Height - 10
+		// Recreate tables with new responsive widths and current data
+		m.rebuildTables()
+		// Re-wrap the open message, checkpoint and commits for the new width
+		m.layoutDetail()
+		if m.viewMode == ViewCheckpointContent {
+			m.layoutCheckpoint()
+		}
+		m.layoutCommits()
+		m.layoutComparison()
+		m.layoutSessionDiff()
 		return m, nil
 	}
 
-	// Pass all other messages to the appropriate table
-	var cmd tea.Cmd
-	if m.viewMode == ViewProcesses {
-		m.table, cmd = m.table.Update(msg)
-		// Track arrow key presses for selection with wrapping
-		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			switch keyMsg.String() {
-			case "up":
-				if m.selectedProcIdx > 0 {
-					m.selectedProcIdx--
-				} else if len(m.processes) > 0 {
-					m.selectedProcIdx = len(m.processes) - 1
-				}
-			case "down":
-				if m.selectedProcIdx < len(m.processes)-1 {
-					m.selectedProcIdx++
-				} else if len(m.processes) > 0 {
-					m.selectedProcIdx = 0
-				}
-			}
+	return m, nil
+}
+
+// navigate moves the selection or scroll position of the current view
+func (m Model) navigate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.viewMode {
+	case ViewProcesses:
+		m.selectedProcIdx = m.moveSelection(msg, m.selectedProcIdx, len(m.processes), m.table.PageSize())
+		m.table = m.table.WithHighlightedRow(m.selectedProcIdx)
+	case ViewProjects:
+		m.selectedProjIdx = m.moveSelection(msg, m.selectedProjIdx, len(m.projects), m.projectsTable.PageSize())
+		m.projectsTable = m.projectsTable.WithHighlightedRow(m.selectedProjIdx)
+	case ViewSessions:
+		m.selectedSessionIdx = m.moveSelection(msg, m.selectedSessionIdx, len(m.sessions), m.sessionTable.PageSize())
+		m.sessionTable = m.sessionTable.WithHighlightedRow(m.selectedSessionIdx)
+	case ViewFiles:
+		m.selectedFileIdx = m.moveSelection(msg, m.selectedFileIdx, len(m.filesTable.GetVisibleRows()), m.filesTable.PageSize())
+		m.filesTable = m.filesTable.WithHighlightedRow(m.selectedFileIdx)
+	case ViewFileHistory:
+		if m.fileHistory != nil {
+			m.selectedHistoryFileIdx = m.moveSelection(msg, m.selectedHistoryFileIdx, len(m.fileHistory.Files()), m.historyFilesTable.PageSize())
+			m.historyFilesTable = m.historyFilesTable.WithHighlightedRow(m.selectedHistoryFileIdx)
 		}
-	} else if m.viewMode == ViewProjects {
-		m.projectsTable, cmd = m.projectsTable.Update(msg)
-		// Track arrow key presses for project selection with wrapping
-		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			switch keyMsg.String() {
-			case "up":
-				if m.selectedProjIdx > 0 {
-					m.selectedProjIdx--
-				} else if len(m.projects) > 0 {
-					m.selectedProjIdx = len(m.projects) - 1
-				}
-			case "down":
-				if m.selectedProjIdx < len(m.projects)-1 {
-					m.selectedProjIdx++
-				} else if len(m.projects) > 0 {
-					m.selectedProjIdx = 0
-				}
-			}
+	case ViewCheckpoints:
+		m.selectedVersionIdx = m.moveSelection(msg, m.selectedVersionIdx, len(m.historyVersions), m.checkpointsTable.PageSize())
+		m.checkpointsTable = m.checkpointsTable.WithHighlightedRow(m.selectedVersionIdx)
+	case ViewCheckpointContent:
+		pageHeight := m.historyPageHeight()
+		maxScroll := max(len(m.historyLines)-pageHeight, 0)
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			m.historyScroll--
+		case key.Matches(msg, m.keys.Down):
+			m.historyScroll++
+		case key.Matches(msg, m.keys.PageUp):
+			m.historyScroll -= pageHeight
+		case key.Matches(msg, m.keys.PageDown):
+			m.historyScroll += pageHeight
+		case key.Matches(msg, m.keys.Home):
+			m.historyScroll = 0
+		case key.Matches(msg, m.keys.End):
+			m.historyScroll = maxScroll
+		case key.Matches(msg, m.keys.Prev):
+			m.showCheckpoint(m.selectedVersionIdx-1, m.historyDiff)
+		case key.Matches(msg, m.keys.Next):
+			m.showCheckpoint(m.selectedVersionIdx+1, m.historyDiff)
 		}
-	} else if m.viewMode == ViewSessions {
-		m.sessionTable, cmd = m.sessionTable.Update(msg)
-		// Track arrow key presses for session selection with wrapping
-		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			switch keyMsg.String() {
-			case "up":
-				if m.selectedSessionIdx > 0 {
-					m.selectedSessionIdx--
-				} else if len(m.sessions) > 0 {
-					m.selectedSessionIdx = len(m.sessions) - 1
-				}
-			case "down":
-				if m.selectedSessionIdx < len(m.sessions)-1 {
-					m.selectedSessionIdx++
-				} else if len(m.sessions) > 0 {
-					m.selectedSessionIdx = 0
-				}
-			}
+		m.historyScroll = min(max(m.historyScroll, 0), maxScroll)
+	case ViewTools:
+		m.selectedToolIdx = m.moveSelection(msg, m.selectedToolIdx, len(m.toolsTable.GetVisibleRows()), m.toolsTable.PageSize())
+		m.toolsTable = m.toolsTable.WithHighlightedRow(m.selectedToolIdx)
+	case ViewUsageHistory:
+		m.selectedUsageIdx = m.moveSelection(msg, m.selectedUsageIdx, len(m.usageTable.GetVisibleRows()), m.usageTable.PageSize())
+		m.usageTable = m.usageTable.WithHighlightedRow(m.selectedUsageIdx)
+	case ViewCommits:
+		m.commitsScroll = m.scrollLines(msg, m.commitsScroll, len(m.commitLines))
+	case ViewCompare:
+		m.compareScroll = m.scrollLines(msg, m.compareScroll, len(m.compareLines))
+	case ViewSessionDiff:
+		switch {
+		case key.Matches(msg, m.keys.Prev):
+			m.jumpSessionDiff(false)
+		case key.Matches(msg, m.keys.Next):
+			m.jumpSessionDiff(true)
+		default:
+			m.sessionDiffScroll = m.scrollLines(msg, m.sessionDiffScroll, len(m.sessionDiffLines))
 		}
-	} else if m.viewMode == ViewSessionDetail {
-		m.messageTable, cmd = m.messageTable.Update(msg)
+	case ViewSessionDetail:
 		// Handle cursor movement and scrolling in session detail view
-		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			stats, ok := m.sessionStats.(*monitor.SessionStats)
-			if ok {
-				filteredMessages := m.getFilteredMessages(stats)
-				needsRender := false
-
-				switch keyMsg.String() {
-				case "up":
-					// Move cursor up
-					if m.selectedMessageIdx > 0 {
-						m.selectedMessageIdx--
-						needsRender = true
-					}
-				case "down":
-					// Move cursor down
-					if m.selectedMessageIdx < len(m.messages)-1 {
-						m.selectedMessageIdx++
-						needsRender = true
-					}
-				case "pgup":
-					// Page up
-					m.messageViewport.HalfViewUp()
-				case "pgdn":
-					// Page down
-					m.messageViewport.HalfViewDown()
-				case "home":
-					// Jump to top
-					m.selectedMessageIdx = 0
-					needsRender = true
-				case "end":
-					// Jump to bottom
-					m.selectedMessageIdx = len(m.messages) - 1
-					needsRender = true
-				case "enter":
-					// Open message detail view for selected message
-					if m.selectedMessageIdx >= 0 && m.selectedMessageIdx < len(filteredMessages) {
-						m.viewMode = ViewMessageDetail
-						m.detailMessage = &filteredMessages[m.selectedMessageIdx]
-						m.detailScrollOffset = 0
-					}
-				}
-
-				// Only re-render viewport content when cursor moves
-				if needsRender {
-					cardsContent := m.renderMessageCards()
-					m.messageViewport.SetContent(cardsContent)
-					// Scroll to keep selected message visible
-					m.scrollToSelection()
-				}
+		needsRender := false
+
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			// Move cursor up
+			if m.selectedMessageIdx > 0 {
+				m.selectedMessageIdx--
+				needsRender = true
 			}
+		case key.Matches(msg, m.keys.Down):
+			// Move cursor down
+			if m.selectedMessageIdx < len(m.messages)-1 {
+				m.selectedMessageIdx++
+				needsRender = true
+			}
+		case key.Matches(msg, m.keys.PageUp):
+			m.messageViewport.HalfViewUp()
+		case key.Matches(msg, m.keys.PageDown):
+			m.messageViewport.HalfViewDown()
+		case key.Matches(msg, m.keys.Home):
+			// Jump to top
+			m.selectedMessageIdx = 0
+			needsRender = true
+		case key.Matches(msg, m.keys.End):
+			// Jump to bottom
+			m.selectedMessageIdx = len(m.messages) - 1
+			needsRender = true
+		}
+
+		// Only re-render viewport content when cursor moves
+		if needsRender {
+			cardsContent := m.renderMessageCards()
+			m.messageViewport.SetContent(cardsContent)
+			// Scroll to keep selected message visible
+			m.scrollToSelection()
 		}
-	} else if m.viewMode == ViewMessageDetail {
+	case ViewMessageDetail:
 		// Handle scrolling and navigation in message detail view
-		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			if m.detailMessage != nil {
-				content := m.detailMessage.Content
-				lines := strings.Split(content, "\n")
-				pageHeight := m.termHeight - 6 // Leave space for header and footer
-				maxScroll := len(lines) - pageHeight
-				if maxScroll < 0 {
-					maxScroll = 0
-				}
+		if m.detailMessage == nil {
+			return m, nil
+		}
+		pageHeight := m.detailPageHeight()
+		maxScroll := len(m.detailLines) - pageHeight
+		if maxScroll < 0 {
+			maxScroll = 0
+		}
 
-				switch keyMsg.String() {
-				case "up":
-					if m.detailScrollOffset > 0 {
-						m.detailScrollOffset--
-					}
-				case "down":
-					if m.detailScrollOffset < maxScroll {
-						m.detailScrollOffset++
-					}
-				case "home":
-					m.detailScrollOffset = 0
-				case "end":
-					m.detailScrollOffset = maxScroll
-				case "pgup":
-					m.detailScrollOffset -= pageHeight
-					if m.detailScrollOffset < 0 {
-						m.detailScrollOffset = 0
-					}
-				case "pgdn":
-					m.detailScrollOffset += pageHeight
-					if m.detailScrollOffset > maxScroll {
-						m.detailScrollOffset = maxScroll
-					}
-				case "left":
-					// Previous message
-					if m.selectedMessageIdx > 0 {
-						m.selectedMessageIdx--
-						m.detailScrollOffset = 0
-						stats, ok := m.sessionStats.(*monitor.SessionStats)
-						if ok {
-							filteredMessages := m.getFilteredMessages(stats)
-							if m.selectedMessageIdx >= 0 && m.selectedMessageIdx < len(filteredMessages) {
-								m.detailMessage = &filteredMessages[m.selectedMessageIdx]
-							}
-						}
-					}
-				case "right":
-					// Next message
-					stats, ok := m.sessionStats.(*monitor.SessionStats)
-					if ok {
-						filteredMessages := m.getFilteredMessages(stats)
-						if m.selectedMessageIdx < len(filteredMessages)-1 {
-							m.selectedMessageIdx++
-							m.detailScrollOffset = 0
-							if m.selectedMessageIdx >= 0 && m.selectedMessageIdx < len(filteredMessages) {
-								m.detailMessage = &filteredMessages[m.selectedMessageIdx]
-							}
-						}
-					}
-				}
+		switch {
+		case key.Matches(msg, m.keys.Up):
+			if m.detailScrollOffset > 0 {
+				m.detailScrollOffset--
+			}
+		case key.Matches(msg, m.keys.Down):
+			if m.detailScrollOffset < maxScroll {
+				m.detailScrollOffset++
 			}
+		case key.Matches(msg, m.keys.Home):
+			m.detailScrollOffset = 0
+		case key.Matches(msg, m.keys.End):
+			m.detailScrollOffset = maxScroll
+		case key.Matches(msg, m.keys.PageUp):
+			m.detailScrollOffset -= pageHeight
+			if m.detailScrollOffset < 0 {
+				m.detailScrollOffset = 0
+			}
+		case key.Matches(msg, m.keys.PageDown):
+			m.detailScrollOffset += pageHeight
+			if m.detailScrollOffset > maxScroll {
+				m.detailScrollOffset = maxScroll
+			}
+		case key.Matches(msg, m.keys.Prev):
+			m.showMessage(m.selectedMessageIdx - 1)
+		case key.Matches(msg, m.keys.Next):
+			m.showMessage(m.selectedMessageIdx + 1)
+		case key.Matches(msg, m.keys.ToggleMarkdown):
+			m.rawMarkdown = !m.rawMarkdown
+			m.layoutDetail()
+			if m.detailScrollOffset > len(m.detailLines)-1 {
+				m.detailScrollOffset = 0
+			}
+		}
+	}
+	return m, nil
+}
+
+// moveSelection applies a navigation key to a list selection. Up and down wrap around.
+func (m Model) moveSelection(msg tea.KeyMsg, idx, count, pageSize int) int {
+	if count == 0 {
+		return 0
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		idx--
+		if idx < 0 {
+			idx = count - 1
+		}
+	case key.Matches(msg, m.keys.Down):
+		idx++
+		if idx >= count {
+			idx = 0
+		}
+	case key.Matches(msg, m.keys.PageUp):
+		idx -= pageSize
+	case key.Matches(msg, m.keys.PageDown):
+		idx += pageSize
+	case key.Matches(msg, m.keys.Home):
+		idx = 0
+	case key.Matches(msg, m.keys.End):
+		idx = count - 1
+	}
+
+	return clampIndex(idx, count)
+}
+
+// scrollLines applies a navigation key to the scroll offset of a page of count lines
+func (m Model) scrollLines(msg tea.KeyMsg, offset, count int) int {
+	pageHeight := m.historyPageHeight()
+	maxScroll := max(count-pageHeight, 0)
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		offset--
+	case key.Matches(msg, m.keys.Down):
+		offset++
+	case key.Matches(msg, m.keys.PageUp):
+		offset -= pageHeight
+	case key.Matches(msg, m.keys.PageDown):
+		offset += pageHeight
+	case key.Matches(msg, m.keys.Home):
+		offset = 0
+	case key.Matches(msg, m.keys.End):
+		offset = maxScroll
+	}
+	return min(max(offset, 0), maxScroll)
+}
+
+// clampIndex limits idx to a valid index into a list of count items
+func clampIndex(idx, count int) int {
+	if idx >= count {
+		idx = count - 1
+	}
+	if idx < 0 {
+		idx = 0
+	}
+	return idx
+}
+
+// showMessage switches the message detail view to the filtered message at idx
+func (m *Model) showMessage(idx int) {
+	stats, ok := m.sessionStats.(*monitor.SessionStats)
+	if !ok {
+		return
+	}
+	filteredMessages := m.getFilteredMessages(stats)
+	if idx < 0 || idx >= len(filteredMessages) {
+		return
+	}
+	m.selectedMessageIdx = idx
+	m.detailMessage = &filteredMessages[idx]
+	m.detailScrollOffset = 0
+	m.layoutDetail()
+}
+
+// sortProcesses orders processes by the sort column, then by PID, keeping
+// the same process selected
+func (m *Model) sortProcesses() {
+	var selected int32
+	if m.selectedProcIdx < len(m.processes) {
+		selected = m.processes[m.selectedProcIdx].PID
+	}
+
+	sortBy(m.processes, tableSort{Column: "pid"}, m.processOrder("pid"))
+	sortBy(m.processes, m.processSort, m.processOrder(m.processSort.Column))
+
+	if m.selectedProcIdx < len(m.processes) && m.processes[m.selectedProcIdx].PID == selected {
+		return
+	}
+	for i, proc := range m.processes {
+		if proc.PID == selected {
+			m.selectedProcIdx = i
+			break
 		}
 	}
-	return m, cmd
 }
 
 // updateTable rebuilds the table with current process data
 func (m *Model) updateTable() {
+	m.sortProcesses()
 	rows := make([]table.Row, len(m.processes))
 
 	for i, proc := range m.processes {
@@ -428,26 +759,36 @@ func (m *Model) updateTable() {
 
 		rows[i] = table.NewRow(table.RowData{
 			"pid":     formatPID(proc.PID),
-			"cpu":     cpu,
-			"mem":     formatMemory(proc.MemoryMB),
+			"cpu":     table.NewStyledCell(cpu, m.cpuStyle(proc.CPUPercent)),
+			"mem":     table.NewStyledCell(formatMemory(proc.MemoryMB), m.memoryStyle(proc.MemoryMB)),
+			"ctx":     m.contextCell(proc.ContextPercent),
+			"alert":   m.alertCell(m.alerts[proc.PID]),
+			"status":  m.stateCell(m.states[proc.PID]),
 			"uptime":  formatUptime(proc.Uptime),
 			"workdir": truncatePathForDisplay(proc.WorkingDir),
 			"cmd":     truncateCommand(proc.Command),
 		})
 	}
 
-	m.table = m.table.WithRows(rows)
+	m.selectedProcIdx = clampIndex(m.selectedProcIdx, len(m.processes))
+	m.table = m.table.WithRows(rows).WithHighlightedRow(m.selectedProcIdx)
 }
 
 // updateSessionTable rebuilds the session table with current session data
 func (m *Model) updateSessionTable() {
-	// Sort sessions by last message time (newest first)
-	sort.Slice(m.sessions, func(i, j int) bool {
-		return m.sessions[i].LastMessageTime > m.sessions[j].LastMessageTime
-	})
+	sortBy(m.allSessions, m.sessionSort, sessionOrder(m.sessionSort.Column))
+
+	m.sessions = nil
+	for _, session := range m.allSessions {
+		if m.sessionFilter.Match(session.Summary) {
+			m.sessions = append(m.sessions, session)
+		}
+	}
+	m.selectedSessionIdx = clampIndex(m.selectedSessionIdx, len(m.sessions))
 
 	// Recreate session table with dynamic widths based on current data
-	m.sessionTable = CreateSessionTableWithDynamicWidths(m.termWidth, m.sessions)
+	m.sessionTable = m.styleTable(CreateSessionTableWithDynamicWidths(m.termWidth, m.sessions, m.columns.Sessions, m.sessionSort)).
+		WithPageSize(m.termHeight - 8)
 
 	rows := make([]table.Row, len(m.sessions))
 
@@ -489,28 +830,43 @@ func (m *Model) updateSessionTable() {
 			}
 		}
 
+		// Format commits (show "…" until counted, "-" outside a git repository)
+		commitsStr := "-"
+		if !session.CommitsCounted {
+			commitsStr = "…"
+		} else if session.Commits >= 0 {
+			commitsStr = fmt.Sprintf("%d", session.Commits)
+		}
+
 		// Mark sidechain with indicator
 		titleStr := truncatePath(session.Title, 36)
 		if session.IsSidechain {
 			titleStr = "🔀 " + titleStr
 		}
 
-		rows[i] = table.NewRow(table.RowData{
+		row := table.NewRow(table.RowData{
 			"version":     versionStr,
 			"gitbranch":   gitStr,
 			"lastmsgtime": lastMsgTimeStr,
 			"tokens":      tokensStr,
 			"started":     session.Started,
 			"duration":    session.Duration,
+			"commits":     commitsStr,
 			"lastmessage": lastMsgPreview,
 		})
+		if m.isMarked(session.Path) {
+			row = row.WithStyle(m.styles.Accent)
+		}
+		rows[i] = row
 	}
 
-	m.sessionTable = m.sessionTable.WithRows(rows)
+	m.selectedSessionIdx = clampIndex(m.selectedSessionIdx, len(m.sessions))
+	m.sessionTable = m.sessionTable.WithRows(rows).WithHighlightedRow(m.selectedSessionIdx)
 }
 
 // updateProjectsTable rebuilds the projects table with current project data
 func (m *Model) updateProjectsTable() {
+	sortBy(m.projects, m.projectSort, projectOrder(m.projectSort.Column))
 	rows := make([]table.Row, len(m.projects))
 
 	for i, proj := range m.projects {
@@ -524,13 +880,15 @@ func (m *Model) updateProjectsTable() {
 		}
 
 		rows[i] = table.NewRow(table.RowData{
+			"root":     proj.Root,
 			"name":     truncatePath(displayName, 50),
 			"modified": modifiedStr,
 			"sessions": sessionsStr,
 		})
 	}
 
-	m.projectsTable = m.projectsTable.WithRows(rows)
+	m.selectedProjIdx = clampIndex(m.selectedProjIdx, len(m.projects))
+	m.projectsTable = m.projectsTable.WithRows(rows).WithHighlightedRow(m.selectedProjIdx)
 }
 
 // updateMessageTable rebuilds the message list with current message data
@@ -545,29 +903,7 @@ func (m *Model) updateMessageTable() {
 		return
 	}
 
-	// Filter messages based on current filter
-	var filteredMessages []monitor.Message
-	for _, msg := range stats.MessageHistory {
-		switch m.messageFilter {
-		case FilterUserOnly:
-			if msg.Type == "prompt" {
-				filteredMessages = append(filteredMessages, msg)
-			}
-		case FilterAssistantOnly:
-			if msg.Type == "assistant_response" || msg.Type == "tool_result" {
-				filteredMessages = append(filteredMessages, msg)
-			}
-		default:
-			filteredMessages = append(filteredMessages, msg)
-		}
-	}
-
-	// Reverse order if sorting newest first
-	if m.messageSortNewestFirst {
-		for i, j := 0, len(filteredMessages)-1; i < j; i, j = i+1, j-1 {
-			filteredMessages[i], filteredMessages[j] = filteredMessages[j], filteredMessages[i]
-		}
-	}
+	filteredMessages := m.getFilteredMessages(stats)
 
 	// Update the filtered message count
 	m.filteredMessageCount = len(filteredMessages)
@@ -655,22 +991,8 @@ func calculateMessageCost(msg *monitor.Message) (cost float64, savings float64)
 		return 0, 0
 	}
 
-	// Input cost
-	inputCost := float64(msg.InputTokens) * InputTokenCost
-	cacheCreationCost := float64(msg.CacheCreation) * CacheCreationTokenCost
-	cacheReadCost := float64(msg.CacheRead) * CacheReadTokenCost
-	outputCost := float64(msg.OutputTokens) * OutputTokenCost
-
-	cost = inputCost + cacheCreationCost + cacheReadCost + outputCost
-
-	// Cache savings (what it would have cost without cache hits)
-	if msg.CacheRead > 0 {
-		// Cache hits would have cost regular input rate
-		normalCacheReadCost := float64(msg.CacheRead) * InputTokenCost
-		savings = normalCacheReadCost - cacheReadCost
-	}
-
-	return cost, savings
+	// Prices come from the model's pricing, including config overrides
+	return monitor.MessageCost(*msg)
 }
 
 // calculateRatio calculates input/output ratio and output percentage
@@ -697,29 +1019,14 @@ func calculateRatio(inputTokens, outputTokens int) (ratio float64, outputPercent
 
 // getFilteredMessages returns the messages filtered by current filter
 func (m *Model) getFilteredMessages(stats *monitor.SessionStats) []monitor.Message {
-	var filteredMessages []monitor.Message
-	for _, msg := range stats.MessageHistory {
-		switch m.messageFilter {
-		case FilterUserOnly:
-			if msg.Type == "prompt" {
-				filteredMessages = append(filteredMessages, msg)
-			}
-		case FilterAssistantOnly:
-			if msg.Type == "assistant_response" || msg.Type == "tool_result" {
-				filteredMessages = append(filteredMessages, msg)
-			}
-		default:
-			filteredMessages = append(filteredMessages, msg)
-		}
-	}
+	filteredMessages := m.messageFilter.Messages(stats.MessageHistory)
 
-	// Apply same sort order as displayed in message list
-	if m.messageSortNewestFirst {
-		// Reverse to show newest first
-		for i, j := 0, len(filteredMessages)-1; i < j; i, j = i+1, j-1 {
-			filteredMessages[i], filteredMessages[j] = filteredMessages[j], filteredMessages[i]
-		}
+	// Apply same sort order as displayed in message list. Reversing first
+	// keeps messages with equal keys newest first too.
+	if m.messageSort.Desc {
+		slices.Reverse(filteredMessages)
 	}
+	sortBy(filteredMessages, m.messageSort, messageOrder(m.messageSort.Column))
 
 	return filteredMessages
 }
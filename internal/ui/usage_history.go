@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/evertras/bubble-table/table"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// usageSamplesMsg carries the samples written by `promptwatch record`
+type usageSamplesMsg struct {
+	samples []monitor.Sample
+	path    string
+	err     error
+}
+
+// usageRanges lists the time ranges the resource history view cycles through
+var usageRanges = []struct {
+	Label  string
+	Period time.Duration // 0 = everything recorded
+}{
+	{"last 7 days", 7 * 24 * time.Hour},
+	{"last 30 days", 30 * 24 * time.Hour},
+	{"all recorded", 0},
+	{"last 24 hours", 24 * time.Hour},
+}
+
+// usageBuckets is the number of columns of each history chart
+const usageBuckets = 16
+
+// usageColumns lists the resource history table columns
+var usageColumns = []columnSpec{
+	{Key: "project", Title: "PROJECT", Width: 20, Flex: 1},
+	{Key: "agents", Title: "AGENTS peak/avg", Width: 28},
+	{Key: "cpu", Title: "CPU% peak/avg", Width: 28},
+	{Key: "mem", Title: "MEMORY peak/avg", Width: 32},
+}
+
+// createUsageTableWithWidth creates the resource history table sized for the width
+func createUsageTableWithWidth(width int) table.Model {
+	return newTable(layoutColumns(usageColumns, width-6))
+}
+
+// openUsageHistory switches to the recorded resource use of all projects
+func (m *Model) openUsageHistory() tea.Cmd {
+	m.usageSourceMode = m.viewMode
+	m.viewMode = ViewUsageHistory
+	m.usageSamples = nil
+	m.usageError = ""
+	m.selectedUsageIdx = 0
+	return m.loadUsageSamples()
+}
+
+// loadUsageSamples reads the sample file in the background
+func (m Model) loadUsageSamples() tea.Cmd {
+	cfg := m.config
+	return func() tea.Msg {
+		store, err := cfg.SampleStore()
+		if err != nil {
+			return usageSamplesMsg{err: err}
+		}
+		samples, err := store.Load(time.Time{})
+		return usageSamplesMsg{samples: samples, path: store.Path, err: err}
+	}
+}
+
+// usageHistory summarises the samples of the selected range
+func (m Model) usageHistory() monitor.UsageHistory {
+	end := time.Now()
+	var start time.Time
+	if period := usageRanges[m.usageRangeIdx].Period; period > 0 {
+		start = end.Add(-period)
+	} else if len(m.usageSamples) > 0 {
+		start = m.usageSamples[0].Time
+	}
+	return monitor.SummarizeSamples(m.usageSamples, start, end, usageBuckets)
+}
+
+// usageChart draws a sparkline of per-bucket peaks scaled to the overall
+// peak. Buckets without samples stay blank.
+func (m Model) usageChart(values []float64, covered []bool, peak float64) string {
+	var b strings.Builder
+	for i, v := range values {
+		switch {
+		case !covered[i]:
+			b.WriteRune(' ')
+		case peak <= 0:
+			b.WriteRune(sparkLevels[0])
+		default:
+			b.WriteRune(sparkLevels[int(v/peak*float64(len(sparkLevels)-1)+0.5)])
+		}
+	}
+	return m.styles.Accent.Render(b.String())
+}
+
+// updateUsageTable refills the resource history table: all projects first,
+// then each project
+func (m *Model) updateUsageTable() {
+	h := m.usageHistory()
+	if h.Samples == 0 {
+		m.usageTable = m.usageTable.WithRows(nil)
+		return
+	}
+
+	usages := append([]monitor.ProjectUsage{h.Total}, h.Projects...)
+	rows := make([]table.Row, len(usages))
+	for i, u := range usages {
+		name := table.NewStyledCell(truncatePath(u.Dir, 60), m.styles.Text)
+		if u.Dir == "" {
+			name = table.NewStyledCell("all projects", m.styles.Accent)
+		}
+		rows[i] = table.NewRow(table.RowData{
+			"project": name,
+			"agents":  m.usageChart(u.Agents, h.Covered, float64(u.PeakAgents)) + fmt.Sprintf(" %d/%.1f", u.PeakAgents, u.AvgAgents),
+			"cpu":     m.usageChart(u.CPU, h.Covered, u.PeakCPU) + fmt.Sprintf(" %.0f/%.0f", u.PeakCPU, u.AvgCPU),
+			"mem":     m.usageChart(u.Memory, h.Covered, u.PeakMemory) + " " + formatMemory(u.PeakMemory) + "/" + formatMemory(u.AvgMemory),
+		})
+	}
+
+	m.selectedUsageIdx = clampIndex(m.selectedUsageIdx, len(rows))
+	m.usageTable = m.usageTable.WithRows(rows).WithHighlightedRow(m.selectedUsageIdx)
+}
+
+// renderUsageHistoryView displays the recorded resource use per project
+func (m Model) renderUsageHistoryView() string {
+	summary := usageRanges[m.usageRangeIdx].Label
+
+	var content string
+	switch {
+	case m.usageError != "":
+		content = m.styles.Error.Render("Error: " + m.usageError)
+	case m.usageSamples == nil:
+		content = m.styles.Muted.Render("Reading samples…")
+	default:
+		h := m.usageHistory()
+		if h.Samples == 0 {
+			content = m.styles.Muted.Render(fmt.Sprintf(
+				"No samples in this range. Run `promptwatch record` to sample running processes into %s", m.usagePath))
+			break
+		}
+		summary = fmt.Sprintf("%d samples · %s · %s – %s · each bar is %s",
+			h.Samples, summary, h.Start.Local().Format("Jan 2 15:04"), h.End.Local().Format("Jan 2 15:04"),
+			formatUptime(h.End.Sub(h.Start)/usageBuckets))
+		content = m.usageTable.View()
+	}
+
+	header := lipgloss.JoinVertical(lipgloss.Left, m.styles.Title.Render("Resource usage history"), m.styles.Muted.Render(summary))
+	footer := m.styles.Muted.Render(formatHints(m.keys.ShortHelp(m.viewMode)))
+	return lipgloss.JoinVertical(lipgloss.Left, header, "", content, "", footer)
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thieso2/promptwatch/internal/config"
+	"github.com/thieso2/promptwatch/internal/monitor"
+)
+
+// TestUsageHistoryView tests the per-project history rows and the range filter
+func TestUsageHistoryView(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	var model tea.Model = NewModel(config.Default(), false)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 140, Height: 40})
+
+	m := model.(Model)
+	m.viewMode = ViewUsageHistory
+	m.usageSourceMode = ViewProcesses
+	now := time.Now()
+	model, _ = m.Update(usageSamplesMsg{path: "/tmp/samples.jsonl", samples: []monitor.Sample{
+		{Time: now.AddDate(0, 0, -20), Projects: []monitor.ProjectSample{{Dir: "/work/old", Agents: 3, CPU: 90, MemoryMB: 3072}}},
+		{Time: now.Add(-2 * time.Hour), Projects: []monitor.ProjectSample{{Dir: "/work/api", Agents: 2, CPU: 40, MemoryMB: 512}}},
+		{Time: now.Add(-time.Hour), Projects: []monitor.ProjectSample{{Dir: "/work/api", Agents: 1, CPU: 20, MemoryMB: 256}}},
+	}})
+
+	view := model.(Model).View()
+	for _, want := range []string{"2 samples · last 7 days", "all projects", "/work/api", " 2/1.5", " 40/30", "512.00M/384.00M"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "/work/old") {
+		t.Errorf("Project outside the range shown:\n%s", view)
+	}
+
+	model, _ = model.Update(keyPress("R"))
+	if view := model.(Model).View(); !strings.Contains(view, "3 samples · last 30 days") || !strings.Contains(view, "/work/old") {
+		t.Errorf("Range filter not applied:\n%s", view)
+	}
+
+	model, _ = model.Update(usageSamplesMsg{path: "/tmp/samples.jsonl", samples: []monitor.Sample{}})
+	if view := model.(Model).View(); !strings.Contains(view, "Run `promptwatch record`") {
+		t.Errorf("Empty history does not explain recording:\n%s", view)
+	}
+
+	model, _ = model.Update(keyPress("esc"))
+	if model.(Model).viewMode != ViewProcesses {
+		t.Errorf("Back returns to %v, want processes", model.(Model).viewMode)
+	}
+}
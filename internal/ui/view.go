@@ -4,15 +4,89 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/thieso2/promptwatch/internal/monitor"
 )
 
-// View renders the UI
+// View renders the UI, led by the escape sequences of pending terminal notices
 func (m Model) View() string {
 	if m.quitting {
 		return "Goodbye!\n"
 	}
+	return m.notices + m.renderScreen()
+}
+
+// renderScreen renders the current view or overlay
+func (m Model) renderScreen() string {
+	if m.showHelp {
+		return m.renderHelpOverlay()
+	}
+	if m.showViews {
+		return m.renderViewPicker()
+	}
+	if m.editingColumns {
+		return m.renderColumnEditor()
+	}
+
+	view := m.renderView()
+	if m.filterEditing {
+		view = lipgloss.JoinVertical(lipgloss.Left, view, m.renderFilterBar())
+	}
+	if m.statusMessage != "" {
+		view = lipgloss.JoinVertical(lipgloss.Left, view, m.renderStatusMessage())
+	}
+	return view
+}
+
+// renderStatusMessage renders the one-off notice shown below the current view
+func (m Model) renderStatusMessage() string {
+	if m.statusIsError {
+		return m.styles.Error.Render(m.statusMessage)
+	}
+	return m.styles.OK.Render(m.statusMessage)
+}
+
+// renderHelpOverlay lists the key bindings active in the current view
+func (m Model) renderHelpOverlay() string {
+	h := help.New()
+	h.Width = m.termWidth - 8 // Border and padding
+	h.Styles.FullKey = m.styles.Highlight
+	h.Styles.FullDesc = m.styles.Faint
+
+	title := m.styles.Title.Render("Keys")
+	hint := m.styles.Muted.Render("Press any key to close")
+
+	box := m.styles.Box.Render(lipgloss.JoinVertical(lipgloss.Left, title, "", h.FullHelpView(m.keys.FullHelp(m.viewMode)), "", hint))
+
+	return lipgloss.Place(m.termWidth, m.termHeight, lipgloss.Center, lipgloss.Center, box)
+}
+
+// renderView renders the view for the current mode
+func (m Model) renderView() string {
+
+	if m.viewMode == ViewFiles {
+		return m.renderFilesView()
+	}
+
+	switch m.viewMode {
+	case ViewFileHistory:
+		return m.renderFileHistoryView()
+	case ViewCheckpoints:
+		return m.renderCheckpointsView()
+	case ViewCheckpointContent:
+		return m.renderCheckpointContentView()
+	case ViewCommits:
+		return m.renderCommitsView()
+	case ViewTools:
+		return m.renderToolsView()
+	case ViewUsageHistory:
+		return m.renderUsageHistoryView()
+	case ViewCompare:
+		return m.renderCompareView()
+	case ViewSessionDiff:
+		return m.renderSessionDiffView()
+	}
 
 	if m.viewMode == ViewMessageDetail {
 		return m.renderMessageDetailView()
@@ -39,18 +113,11 @@ func (m Model) View() string {
 
 // renderEmpty displays a message when no processes are found
 func (m Model) renderEmpty() string {
-	header := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("11")).
-		Render("promptwatch")
+	header := m.styles.Title.Render("promptwatch")
 
-	content := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8")).
-		Render("No Claude instances found.")
+	content := m.styles.Muted.Render("No Claude instances found.")
 
-	footer := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8")).
-		Render("Press 'r' to refresh or 'q' to quit")
+	footer := m.styles.Muted.Render(fmt.Sprintf("Press '%s' to refresh or '%s' to quit", firstKey(m.keys.Refresh), firstKey(m.keys.Quit)))
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -73,19 +140,18 @@ func (m Model) renderSessionDetailView() string {
 	}
 
 	// Header with session title
-	headerTitle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("11")).
-		Render("Session Details")
+	headerTitle := m.styles.Title.Render("Session Details")
 
 	sessionPath := fmt.Sprintf("Path: %s", truncatePath(stats.FilePath, 60))
-	pathStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
+	pathStyle := m.styles.Muted
 	pathText := pathStyle.Render(sessionPath)
 
 	// Session metadata line (version, git, tokens, etc.)
 	var metadataItems []string
 	if m.selectedSession != nil {
+		if m.selectedSession.Root != "" && len(monitor.DataRoots()) > 1 {
+			metadataItems = append(metadataItems, "root:"+m.selectedSession.Root)
+		}
 		if m.selectedSession.Version != "" {
 			metadataItems = append(metadataItems, "v:"+m.selectedSession.Version)
 		}
@@ -113,8 +179,7 @@ func (m Model) renderSessionDetailView() string {
 	metadataText := ""
 	if len(metadataItems) > 0 {
 		metadataStr := strings.Join(metadataItems, "  |  ")
-		metadataStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("8"))
+		metadataStyle := m.styles.Muted
 		metadataText = metadataStyle.Render(metadataStr)
 	}
 
@@ -125,44 +190,36 @@ func (m Model) renderSessionDetailView() string {
 		if len(prompt) > 80 {
 			prompt = prompt[:77] + "..."
 		}
-		promptStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("11"))
+		promptStyle := m.styles.Highlight
 		firstPromptText = promptStyle.Render("Initial: " + prompt)
 	}
 
 	// Stats section
-	statsStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("10"))
+	statsStyle := m.styles.OK
 	statsText := statsStyle.Render(stats.GetSummary())
 
 	// Detailed stats
-	detailedStats := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8")).
-		Render(stats.GetDetailedStats())
+	detailedStats := m.styles.Muted.Render(stats.GetDetailedStats())
 
 	// Messages section - use viewport for scrolling
 	var messagesComponents []string
 
 	if m.filteredMessageCount == 0 {
 		// Show feedback when filter results in no messages
-		feedbackStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("3"))
+		feedbackStyle := m.styles.Warning
 		if m.messageError != "" {
 			messagesComponents = append(messagesComponents, feedbackStyle.Render(m.messageError))
 		} else {
 			messagesComponents = append(messagesComponents, feedbackStyle.Render("No messages to display with current filter"))
 		}
-	} else if m.messageError != "" && m.messageFilter != FilterAll {
+	} else if m.messageError != "" && !m.messageFilter.IsEmpty() {
 		// Show status message for filter mode
-		statusStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("10"))
+		statusStyle := m.styles.OK
 		messagesComponents = append(messagesComponents, statusStyle.Render(m.messageError))
 		// Show viewport with message cards
 		messagesComponents = append(messagesComponents, m.messageViewport.View())
 	} else if len(stats.MessageHistory) == 0 {
-		messagesComponents = append(messagesComponents, lipgloss.NewStyle().
-			Foreground(lipgloss.Color("8")).
-			Render("No messages in this session"))
+		messagesComponents = append(messagesComponents, m.styles.Muted.Render("No messages in this session"))
 	} else {
 		// Show viewport with message cards
 		messagesComponents = append(messagesComponents, m.messageViewport.View())
@@ -172,35 +229,33 @@ func (m Model) renderSessionDetailView() string {
 
 	// Filter status with count
 	filterStr := ""
-	filterColor := lipgloss.Color("11")
-	switch m.messageFilter {
-	case FilterUserOnly:
+	filterStyle := m.styles.Highlight
+	switch m.messageFilter.String() {
+	case "":
+		filterStr = fmt.Sprintf(" [All Messages: %d]", m.filteredMessageCount)
+	case "role:user":
 		filterStr = fmt.Sprintf(" [User Prompts: %d]", m.filteredMessageCount)
-		if m.filteredMessageCount == 0 {
-			filterColor = lipgloss.Color("1")
-		}
-	case FilterAssistantOnly:
+	case "role:assistant,tool":
 		filterStr = fmt.Sprintf(" [Claude Responses: %d]", m.filteredMessageCount)
-		if m.filteredMessageCount == 0 {
-			filterColor = lipgloss.Color("1")
-		}
 	default:
-		filterStr = fmt.Sprintf(" [All Messages: %d]", m.filteredMessageCount)
+		filterStr = fmt.Sprintf(" [%s: %d]", m.messageFilter, m.filteredMessageCount)
+	}
+	if !m.messageFilter.IsEmpty() && m.filteredMessageCount == 0 {
+		filterStyle = m.styles.Error
 	}
-	filterStyle := lipgloss.NewStyle().
-		Foreground(filterColor)
 	filterText := filterStyle.Render(filterStr)
 
 	// Footer with sort order indicator
-	sortIndicator := "oldest→newest"
-	if m.messageSortNewestFirst {
-		sortIndicator = "newest→oldest"
-	}
+	sortIndicator := m.sortLabel("messages")
 
-	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
-	helpText := "↑/↓: Scroll  |  PgUp/PgDn: Page  |  Home/End: Jump  |  u: User  |  a: Assistant  |  b: Both  |  s: Sort (" + sortIndicator + ")  |  esc: Back  |  q: Quit"
-	footer := footerStyle.Render(helpText)
+	footerStyle := m.styles.Muted
+	hints := m.keys.ShortHelp(ViewSessionDetail)
+	for i := range hints {
+		if hints[i].Desc == "Sort" {
+			hints[i].Desc = "Sort (" + sortIndicator + ")"
+		}
+	}
+	footer := footerStyle.Render(formatHints(hints))
 
 	headerComponents := []string{headerTitle, pathText}
 	if metadataText != "" {
@@ -209,7 +264,11 @@ func (m Model) renderSessionDetailView() string {
 	if firstPromptText != "" {
 		headerComponents = append(headerComponents, firstPromptText)
 	}
-	headerComponents = append(headerComponents, "", statsText, detailedStats, "", "Messages:"+filterText)
+	headerComponents = append(headerComponents, "", statsText, detailedStats)
+	if contextText := m.renderContextLine(); contextText != "" {
+		headerComponents = append(headerComponents, contextText)
+	}
+	headerComponents = append(headerComponents, "", "Messages:"+filterText)
 
 	allComponents := append(headerComponents, messagesContent, "", footer)
 
@@ -223,17 +282,18 @@ func (m Model) renderSessionDetailView() string {
 func (m Model) renderSessionView() string {
 	var headerLine string
 
-	if m.selectedProc != nil {
+	if m.sessionsAll {
+		headerLine = m.styles.Title.Render("Sessions in all projects")
+	} else if m.selectedProc != nil {
 		// Viewing sessions from a process
-		headerTitle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("11")).
-			Render("Sessions for: " + truncatePath(m.selectedProc.WorkingDir, 50))
+		headerTitle := m.styles.Title.Render("Sessions for: " + truncatePath(m.selectedProc.WorkingDir, 50))
 
 		processInfo := fmt.Sprintf("PID: %d | CPU: %.1f%% | MEM: %.2f MB",
 			m.selectedProc.PID, m.selectedProc.CPUPercent, m.selectedProc.MemoryMB)
-		processStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("8"))
+		if m.selectedProc.Root != "" {
+			processInfo += " | Root: " + m.selectedProc.Root
+		}
+		processStyle := m.styles.Muted
 		processText := processStyle.Render(processInfo)
 
 		headerLine = lipgloss.JoinVertical(
@@ -250,37 +310,43 @@ func (m Model) renderSessionView() string {
 			projName = "Project"
 		}
 
-		headerTitle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("11")).
-			Render("Sessions for: " + truncatePath(projName, 50))
+		headerTitle := m.styles.Title.Render("Sessions for: " + truncatePath(projName, 50))
 
 		headerLine = headerTitle
 	}
 
+	if !m.sessionFilter.IsEmpty() {
+		filterInfo := fmt.Sprintf("Filter: %s · %d of %d sessions", m.sessionFilter, len(m.sessions), len(m.allSessions))
+		headerLine = lipgloss.JoinVertical(lipgloss.Left, headerLine, m.styles.Highlight.Render(filterInfo))
+	}
+	if n := len(m.markedSessions); n > 0 {
+		markInfo := fmt.Sprintf("%d marked · %s compares them", n, firstKey(m.keys.Compare))
+		if n == 2 {
+			markInfo += fmt.Sprintf(", %s diffs them", firstKey(m.keys.Diff))
+		}
+		headerLine = lipgloss.JoinVertical(lipgloss.Left, headerLine, m.styles.Accent.Render(markInfo))
+	}
+
 	// Check for errors
 	if m.sessionError != "" {
-		errorStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("1"))
+		errorStyle := m.styles.Error
 		errorText := errorStyle.Render("Error: " + m.sessionError)
-		return lipgloss.JoinVertical(lipgloss.Left, headerLine, "", errorText, "", footerHint())
+		return lipgloss.JoinVertical(lipgloss.Left, headerLine, "", errorText, "", m.footerHint())
 	}
 
 	// Show table or empty message
 	var content string
-	if len(m.sessions) == 0 {
+	if len(m.sessions) == 0 && len(m.allSessions) > 0 {
+		content = m.styles.Muted.Render("No sessions match the filter")
+	} else if len(m.sessions) == 0 {
 		// Show empty message when no sessions found
-		content = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("8")).
-			Render("No sessions found for this directory")
+		content = m.styles.Muted.Render("No sessions found for this directory")
 	} else {
 		content = m.sessionTable.View()
 	}
 
-	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
-	helpText := "↑/↓: Navigate  |  enter: Open  |  esc: Back  |  q: Quit"
-	footer := footerStyle.Render(helpText)
+	footerStyle := m.styles.Muted
+	footer := footerStyle.Render(formatHints(m.keys.ShortHelp(m.viewMode)))
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -294,15 +360,15 @@ func (m Model) renderSessionView() string {
 
 // renderProjectsView displays all project directories sorted by modification time
 func (m Model) renderProjectsView() string {
-	// Header with title
-	headerTitle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("11")).
-		Render("Claude Projects (~/.claude/projects)")
+	// Header with title listing the data roots
+	var rootDirs []string
+	for _, root := range monitor.DataRoots() {
+		rootDirs = append(rootDirs, truncatePath(root.ProjectsDir(), 40))
+	}
+	headerTitle := m.styles.Title.Render("Claude Projects (" + strings.Join(rootDirs, ", ") + ")")
 
 	projectCount := fmt.Sprintf("%d projects", len(m.projects))
-	countStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
+	countStyle := m.styles.Muted
 	countText := countStyle.Render(projectCount)
 
 	headerLine := lipgloss.JoinVertical(
@@ -313,27 +379,22 @@ func (m Model) renderProjectsView() string {
 
 	// Check for errors
 	if m.projectsError != "" {
-		errorStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("1"))
+		errorStyle := m.styles.Error
 		errorText := errorStyle.Render("Error: " + m.projectsError)
-		return lipgloss.JoinVertical(lipgloss.Left, headerLine, "", errorText, "", footerHint())
+		return lipgloss.JoinVertical(lipgloss.Left, headerLine, "", errorText, "", m.footerHint())
 	}
 
 	// Show table or empty message
 	var content string
 	if len(m.projects) == 0 {
 		// Show empty message when no projects found
-		content = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("8")).
-			Render("No projects found in ~/.claude/projects")
+		content = m.styles.Muted.Render("No projects found in " + strings.Join(rootDirs, ", "))
 	} else {
 		content = m.projectsTable.View()
 	}
 
-	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
-	helpText := "↑/↓: Navigate  |  enter: View sessions  |  p: Processes  |  q: Quit"
-	footer := footerStyle.Render(helpText)
+	footerStyle := m.styles.Muted
+	footer := footerStyle.Render(formatHints(m.keys.ShortHelp(m.viewMode)))
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -349,22 +410,16 @@ func (m Model) renderProjectsView() string {
 // renderWithTable displays the full UI with the process table
 func (m Model) renderWithTable() string {
 	// Header with title and status
-	headerTitle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("11")).
-		Render("promptwatch")
+	headerTitle := m.styles.Title.Render("promptwatch")
 
 	status := fmt.Sprintf("%d instances", len(m.processes))
 	if m.showHelpers {
 		status += " (including helpers)"
 	}
-	statusStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
+	statusStyle := m.styles.Muted
 	statusText := statusStyle.Render(status)
 
-	timestamp := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8")).
-		Render(fmt.Sprintf("Updated: %s", m.lastUpdate.Format("15:04:05")))
+	timestamp := m.styles.Muted.Render(fmt.Sprintf("Updated: %s", m.lastUpdate.Format("15:04:05")))
 
 	headerLine := lipgloss.JoinHorizontal(
 		lipgloss.Left,
@@ -379,27 +434,24 @@ func (m Model) renderWithTable() string {
 	tableView := m.table.View()
 
 	// Footer with help text
-	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
+	footerStyle := m.styles.Muted
 
-	helpText := "↑/↓: Navigate  |  enter: View sessions  |  p: Projects  |  r: Refresh  |  f: Toggle helpers  |  q: Quit"
-	footer := footerStyle.Render(helpText)
+	footer := footerStyle.Render(formatHints(m.keys.ShortHelp(m.viewMode)))
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
 		headerLine,
 		"",
 		tableView,
-		"",
+		m.renderAlertLine(),
 		footer,
 	)
 }
 
 // footerHint returns a generic footer hint
-func footerHint() string {
-	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
-	return footerStyle.Render("Press 'esc' to go back")
+func (m Model) footerHint() string {
+	footerStyle := m.styles.Muted
+	return footerStyle.Render(fmt.Sprintf("Press '%s' to go back", firstKey(m.keys.Back)))
 }
 
 // renderMessageDetailView displays a message with full text and line wrapping
@@ -414,31 +466,30 @@ func (m Model) renderMessageDetailView() string {
 	// Build header based on message type
 	var headerTitle, metadataSection string
 
-	if msg.Role == "user" {
+	if msg.Type == "tool_result" {
+		// Tool output returned to Claude
+		headerTitle = m.styles.Tool.Render("📥 TOOL RESULT")
+
+		timeStr := msg.Timestamp.Format("2006-01-02 15:04:05 MST")
+		metadataSection = m.styles.Subtle.Render(fmt.Sprintf("returned at %s", timeStr))
+
+	} else if msg.Role == "user" {
 		// User message style
-		headerTitle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("226")).
-			Render("👤 YOUR PROMPT")
+		headerTitle = m.styles.Prompt.Render("👤 YOUR PROMPT")
 
 		timeStr := msg.Timestamp.Format("2006-01-02 15:04:05 MST")
-		metadataSection = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("244")).
-			Render(fmt.Sprintf("sent at %s", timeStr))
+		metadataSection = m.styles.Subtle.Render(fmt.Sprintf("sent at %s", timeStr))
 
 	} else if msg.Role == "assistant" {
 		if msg.ToolName != "" {
 			// Tool call style
-			headerTitle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("82")).
-				Render(fmt.Sprintf("🔧 TOOL CALL: %s", strings.ToUpper(msg.ToolName)))
+			headerTitle = m.styles.Tool.Render(fmt.Sprintf("🔧 TOOL CALL: %s", strings.ToUpper(msg.ToolName)))
 
 			var toolDetails []string
 			toolDetails = append(toolDetails, fmt.Sprintf("Tool: %s", msg.ToolName))
 
 			if msg.ToolInput != "" {
-				toolDetails = append(toolDetails, fmt.Sprintf("Arguments: %s", msg.ToolInput))
+				toolDetails = append(toolDetails, toolSummary(msg.ToolName, msg.ToolInput))
 			}
 
 			// Add UUID if available
@@ -446,15 +497,10 @@ func (m Model) renderMessageDetailView() string {
 				toolDetails = append(toolDetails, fmt.Sprintf("ID: %s", msg.UUID[:8]))
 			}
 
-			metadataSection = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("11")).
-				Render(strings.Join(toolDetails, " • "))
+			metadataSection = m.styles.Highlight.Render(strings.Join(toolDetails, " • "))
 		} else {
 			// Regular assistant response
-			headerTitle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("51")).
-				Render("🤖 CLAUDE RESPONSE")
+			headerTitle = m.styles.Assistant.Render("🤖 CLAUDE RESPONSE")
 
 			// Build metadata for assistant message
 			var metaParts []string
@@ -479,19 +525,8 @@ func (m Model) renderMessageDetailView() string {
 				}
 
 				// Cost calculation
-				inputCost := float64(msg.InputTokens+msg.CacheCreation) * 0.000003
-				cacheReadCost := float64(msg.CacheRead) * 0.0000003
-				outputCost := float64(msg.OutputTokens) * 0.000015
-				totalCost := inputCost + cacheReadCost + outputCost
-
-				costColor := "10" // Green
-				if totalCost > 0.10 {
-					costColor = "1" // Red
-				} else if totalCost > 0.01 {
-					costColor = "3" // Yellow
-				}
-				costStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(costColor))
-				metaParts = append(metaParts, costStyle.Render(fmt.Sprintf("$%.4f", totalCost)))
+				totalCost, _ := monitor.MessageCost(*msg)
+				metaParts = append(metaParts, m.costStyle(totalCost).Render(fmt.Sprintf("$%.4f", totalCost)))
 			}
 
 			// Add UUID if available
@@ -499,25 +534,16 @@ func (m Model) renderMessageDetailView() string {
 				metaParts = append(metaParts, fmt.Sprintf("ID:%s", msg.UUID[:8]))
 			}
 
-			metadataSection = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("244")).
-				Render(strings.Join(metaParts, " · "))
+			metadataSection = m.styles.Subtle.Render(strings.Join(metaParts, " · "))
 		}
 	} else {
 		// Fallback for other types
-		headerTitle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("11")).
-			Render("MESSAGE")
-		metadataSection = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("244")).
-			Render(msg.Timestamp.Format("2006-01-02 15:04:05"))
+		headerTitle = m.styles.Title.Render("MESSAGE")
+		metadataSection = m.styles.Subtle.Render(msg.Timestamp.Format("2006-01-02 15:04:05"))
 	}
 
 	// Separator line
-	separator := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("238")).
-		Render(strings.Repeat("─", 88))
+	separator := m.styles.Border.Render(strings.Repeat("─", 88))
 
 	// Build detailed metadata section with all available fields
 	var detailsLines []string
@@ -545,20 +571,9 @@ func (m Model) renderMessageDetailView() string {
 		}
 
 		// Calculate cost
-		inputCost := float64(msg.InputTokens+msg.CacheCreation) * 0.000003
-		cacheReadCost := float64(msg.CacheRead) * 0.0000003
-		outputCost := float64(msg.OutputTokens) * 0.000015
-		totalCost := inputCost + cacheReadCost + outputCost
-
+		totalCost, _ := monitor.MessageCost(*msg)
 		if totalCost > 0 {
-			costColor := "10" // Green
-			if totalCost > 0.10 {
-				costColor = "1" // Red
-			} else if totalCost > 0.01 {
-				costColor = "3" // Yellow
-			}
-			costStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(costColor))
-			tokenInfo = append(tokenInfo, costStyle.Render(fmt.Sprintf("Cost: $%.6f", totalCost)))
+			tokenInfo = append(tokenInfo, m.costStyle(totalCost).Render(fmt.Sprintf("Cost: $%.6f", totalCost)))
 		}
 
 		details = append(details, strings.Join(tokenInfo, " • "))
@@ -596,13 +611,11 @@ func (m Model) renderMessageDetailView() string {
 
 	// Format details
 	if len(details) > 0 {
-		detailsStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("242"))
+		detailsStyle := m.styles.Subtle
 		for i, detail := range details {
 			// First line (tokens/model) with different style
 			if i == 0 && msg.Role == "assistant" && (msg.InputTokens > 0 || msg.OutputTokens > 0 || msg.Model != "") {
-				tokenStyle := lipgloss.NewStyle().
-					Foreground(lipgloss.Color("6"))
+				tokenStyle := m.styles.Info
 				detailsLines = append(detailsLines, tokenStyle.Render(detail))
 			} else {
 				detailsLines = append(detailsLines, detailsStyle.Render(detail))
@@ -610,87 +623,9 @@ func (m Model) renderMessageDetailView() string {
 		}
 	}
 
-	// Content display with word wrapping
-	content := msg.Content
-
-	// Set max width for wrapping (use 80 chars or terminal width, whichever is smaller)
-	maxWidth := 80
-	if m.termWidth > 0 && m.termWidth < 80 {
-		maxWidth = m.termWidth - 2
-	}
-
-	// Word-wrap the content
-	var wrappedLines []string
-
-	// Add tool info if this is a tool call
-	if msg.ToolName != "" {
-		toolHeader := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("82")).
-			Bold(true).
-			Render("🔧 " + strings.ToUpper(msg.ToolName))
-		wrappedLines = append(wrappedLines, toolHeader)
-
-		if msg.ToolInput != "" {
-			wrappedLines = append(wrappedLines, "")
-			wrappedLines = append(wrappedLines, lipgloss.NewStyle().
-				Foreground(lipgloss.Color("11")).
-				Render("Arguments:"))
-
-			// Wrap tool input
-			words := strings.Fields(msg.ToolInput)
-			var currentLine string
-			for _, word := range words {
-				if currentLine == "" {
-					currentLine = word
-				} else if len(currentLine)+1+len(word) <= maxWidth {
-					currentLine += " " + word
-				} else {
-					wrappedLines = append(wrappedLines, currentLine)
-					currentLine = word
-				}
-			}
-			if currentLine != "" {
-				wrappedLines = append(wrappedLines, currentLine)
-			}
-		}
-
-		// Add separator before content
-		if content != "" {
-			wrappedLines = append(wrappedLines, "")
-		}
-	}
-
-	// Add regular message content
-	for _, paragraph := range strings.Split(content, "\n") {
-		// Handle empty lines
-		if paragraph == "" {
-			wrappedLines = append(wrappedLines, "")
-			continue
-		}
-
-		// Word-wrap long lines
-		words := strings.Fields(paragraph)
-		var currentLine string
-		for _, word := range words {
-			if currentLine == "" {
-				currentLine = word
-			} else if len(currentLine)+1+len(word) <= maxWidth {
-				currentLine += " " + word
-			} else {
-				wrappedLines = append(wrappedLines, currentLine)
-				currentLine = word
-			}
-		}
-		if currentLine != "" {
-			wrappedLines = append(wrappedLines, currentLine)
-		}
-	}
-
-	// Calculate visible lines based on terminal height
-	pageHeight := m.termHeight - 10 // Leave space for header, footer, metadata
-	if pageHeight < 5 {
-		pageHeight = 5 // Minimum
-	}
+	// Content was wrapped or rendered by layoutDetail
+	wrappedLines := m.detailLines
+	pageHeight := m.detailPageHeight()
 
 	// Get the visible portion of wrapped lines
 	var visibleLines []string
@@ -700,11 +635,7 @@ func (m Model) renderMessageDetailView() string {
 		visibleLines = wrappedLines[m.detailScrollOffset:]
 	}
 
-	// Display the visible content
-	contentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("255"))
-
-	contentText := contentStyle.Render(strings.Join(visibleLines, "\n"))
+	contentText := strings.Join(visibleLines, "\n")
 
 	// Scroll position indicator showing actual line numbers
 	totalLines := len(wrappedLines)
@@ -718,15 +649,15 @@ func (m Model) renderMessageDetailView() string {
 		}
 		scrollInfo = fmt.Sprintf("Line %d-%d of %d", m.detailScrollOffset+1, endLine, totalLines)
 	}
-	scrollStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
+	if isMarkdownMessage(msg.Role, msg.ToolName) && m.rawMarkdown {
+		scrollInfo += " · raw markdown"
+	}
+	scrollStyle := m.styles.Muted
 	scrollText := scrollStyle.Render(scrollInfo)
 
 	// Footer with help
-	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8"))
-	helpText := "↑/↓: Scroll  |  ←/→: Prev/Next  |  PgUp/PgDn: Page  |  Home/End: Jump  |  esc: Back  |  q: Quit"
-	footer := footerStyle.Render(helpText)
+	footerStyle := m.styles.Muted
+	footer := footerStyle.Render(formatHints(m.keys.ShortHelp(m.viewMode)))
 
 	// Build output
 	output := []string{
@@ -751,6 +682,15 @@ func (m Model) renderMessageDetailView() string {
 	)
 }
 
+// detailPageHeight returns the number of content lines the message detail view shows
+func (m Model) detailPageHeight() int {
+	pageHeight := m.termHeight - 10 // Leave space for header, footer, metadata
+	if pageHeight < 5 {
+		pageHeight = 5 // Minimum
+	}
+	return pageHeight
+}
+
 // renderMessageCards renders all messages as cards for the viewport with cursor
 func (m *Model) renderMessageCards() string {
 	if len(m.messages) == 0 {
@@ -762,7 +702,7 @@ func (m *Model) renderMessageCards() string {
 	// Render all cards with cursor indicator
 	for i := range m.messages {
 		isSelected := (i == m.selectedMessageIdx)
-		card := renderMessageCard(m.messages[i], isSelected)
+		card := m.renderMessageCard(m.messages[i], isSelected)
 		cards = append(cards, card)
 	}
 
@@ -771,7 +711,7 @@ func (m *Model) renderMessageCards() string {
 
 // renderMessageCard renders a single message as a fixed-height card (4 lines)
 // Beautiful format with proper left alignment
-func renderMessageCard(msg MessageRow, isSelected bool) string {
+func (m *Model) renderMessageCard(msg MessageRow, isSelected bool) string {
 	// Role emoji and label
 	roleEmoji := "👤"
 	roleLabel := "user"
@@ -822,16 +762,11 @@ func renderMessageCard(msg MessageRow, isSelected bool) string {
 	var headerLine string
 	if isSelected {
 		// Bright, bold header with background for selected
-		headerStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("228")).
-			Background(lipgloss.Color("23")).
-			Bold(true).
-			Padding(0, 1)
+		headerStyle := m.styles.Selected
 		headerLine = headerStyle.Render(headerText)
 	} else {
 		// Subtle styling for non-selected
-		headerStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("244"))
+		headerStyle := m.styles.Subtle
 		headerLine = headerStyle.Render(headerText)
 	}
 
@@ -845,15 +780,10 @@ func renderMessageCard(msg MessageRow, isSelected bool) string {
 	var contentLine string
 	if isSelected {
 		// Bright text for selected content
-		contentLine = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("255")).
-			Bold(true).
-			Render(contentCompact)
+		contentLine = m.styles.SelectedText.Render(contentCompact)
 	} else {
 		// Regular text for non-selected
-		contentLine = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("250")).
-			Render(contentCompact)
+		contentLine = m.styles.Faint.Render(contentCompact)
 	}
 
 	// Build metrics line with proper left alignment
@@ -871,46 +801,28 @@ func renderMessageCard(msg MessageRow, isSelected bool) string {
 			}
 
 			// Cost with color
-			costColor := "10" // Green
-			if msg.Cost > 0.10 {
-				costColor = "1" // Red
-			} else if msg.Cost > 0.01 {
-				costColor = "3" // Yellow
-			}
-			costStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(costColor))
-			metricParts = append(metricParts, costStyle.Render(fmt.Sprintf("$%.4f", msg.Cost)))
+			metricParts = append(metricParts, m.costStyle(msg.Cost).Render(fmt.Sprintf("$%.4f", msg.Cost)))
 		}
 	} else {
 		// User message metrics
 		metricParts = append(metricParts, fmt.Sprintf("tokens:%d", msg.InputTokens))
 
 		if msg.Cost > 0 {
-			costColor := "10"
-			if msg.Cost > 0.01 {
-				costColor = "3"
-			}
-			costStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(costColor))
-			metricParts = append(metricParts, costStyle.Render(fmt.Sprintf("$%.6f", msg.Cost)))
+			metricParts = append(metricParts, m.costStyle(msg.Cost).Render(fmt.Sprintf("$%.6f", msg.Cost)))
 		}
 	}
 
 	metricStr := strings.Join(metricParts, " ")
-	metricLine := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("8")).
-		Render(metricStr)
+	metricLine := m.styles.Muted.Render(metricStr)
 
 	// Separator - no leading spaces, just use full width up to reasonable length
 	var separatorLine string
 	if isSelected {
 		// Bright separator for selected
-		separatorLine = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("226")).
-			Render(strings.Repeat("▬", 88))
+		separatorLine = m.styles.Accent.Render(strings.Repeat("▬", 88))
 	} else {
 		// Subtle separator for non-selected
-		separatorLine = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("238")).
-			Render(strings.Repeat("─", 88))
+		separatorLine = m.styles.Border.Render(strings.Repeat("─", 88))
 	}
 
 	// Build card: always 4 lines (left-aligned)